@@ -0,0 +1,249 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/backtest"
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+	"github.com/twk/trader-b/internal/store"
+	"github.com/twk/trader-b/internal/strategy"
+)
+
+// Decimal places of the printed ratios, as percentages.
+const (
+	percentPlaces = 2
+	ratioPlaces   = 2
+	percent       = 100
+)
+
+// strategyHold is the name of the buy and hold strategy, the baseline of the backtests.
+const strategyHold = "hold"
+
+// NewBacktestCmd creates a new cobra command for the backtest command. Its store flag shares the configuration key of the data command,
+// so the flags are only bound when it runs.
+func NewBacktestCmd(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "store", Description: "The path of the candle store file", DefaultValue: "./trader-b.db"}, MapKey: "data.store_path", EnvName: "TRADER_B_STORE"},
+		{Flag: config.FlagDetail{Name: "strategy", Shorthand: "s", Description: "The strategy to replay: hold, or a strategy listed by 'strategy list'", DefaultValue: strategyHold}, MapKey: "backtest.strategy"},
+		{Flag: config.FlagDetail{Name: "params", Shorthand: "p", Description: "The comma separated name=value parameters of the strategy, e.g. fast=10,slow=30", DefaultValue: ""}, MapKey: "backtest.params"},
+		{Flag: config.FlagDetail{Name: "exchange", Description: "The exchange of the stored candles", DefaultValue: binance.Name}, MapKey: "backtest.exchange"},
+		{Flag: config.FlagDetail{Name: "symbol", Description: "The symbol of the candles, e.g. BTCUSDT", DefaultValue: ""}, MapKey: "backtest.symbol"},
+		{Flag: config.FlagDetail{Name: "interval", Shorthand: "i", Description: "The interval of the candles, e.g. 1h", DefaultValue: "1h"}, MapKey: "backtest.interval"},
+		{Flag: config.FlagDetail{Name: "start", Description: "The start of the replayed range as an RFC 3339 time or a date, defaults to the first stored candle", DefaultValue: ""}, MapKey: "backtest.start"},
+		{Flag: config.FlagDetail{Name: "end", Description: "The end of the replayed range as an RFC 3339 time or a date, defaults to the last stored candle", DefaultValue: ""}, MapKey: "backtest.end"},
+		{Flag: config.FlagDetail{Name: "quote", Shorthand: "q", Description: "The quote asset of the symbol, which the capital is held in", DefaultValue: "USDT"}, MapKey: "backtest.quote"},
+		{Flag: config.FlagDetail{Name: "capital", Description: "The initial balance of the quote asset", DefaultValue: "10000"}, MapKey: "backtest.capital"},
+		{Flag: config.FlagDetail{Name: "maker-fee", Description: "The fee of the limit orders, as a fraction of the traded amount", DefaultValue: "0.001"}, MapKey: "backtest.maker_fee"},
+		{Flag: config.FlagDetail{Name: "taker-fee", Description: "The fee of the market orders, as a fraction of the traded amount", DefaultValue: "0.001"}, MapKey: "backtest.taker_fee"},
+		{Flag: config.FlagDetail{Name: "slippage", Description: "The fraction of the open price market orders are filled worse than it", DefaultValue: "0.0005"}, MapKey: "backtest.slippage"},
+		{Flag: config.FlagDetail{Name: "output", Shorthand: "o", Description: "The CSV file the trade log is written to", DefaultValue: "./trades.csv"}, MapKey: "backtest.output"},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "backtest",
+		Short: "Replay a strategy over the stored candles",
+		Long: `The 'backtest' command replays the candles of the candle store through a strategy in time order, simulating its orders
+with the fees and slippage, prints the performance report and writes the trade log to the output CSV file.
+The orders a strategy places on a candle are executed from the next candle.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return backtestRun(cmd.Context(), cmd.OutOrStdout(), v, l)
+		},
+	}
+
+	cmd.PersistentPreRunE = func(_ *cobra.Command, _ []string) error {
+		if err := v.Binds(cmd, b); err != nil {
+			return fmt.Errorf("error binding backtest flags: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := v.SetFlags(cmd, b); err != nil {
+		return nil
+	}
+
+	return cmd
+}
+
+func backtestRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger) error {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info("running backtest command", zap.Any("config", cfg))
+
+	bc := cfg.Backtest
+	bc.Symbol, bc.Quote = strings.ToUpper(bc.Symbol), strings.ToUpper(bc.Quote)
+
+	if bc.Symbol == "" {
+		return errors.New("a symbol is required")
+	}
+
+	if !strings.HasSuffix(bc.Symbol, bc.Quote) || bc.Symbol == bc.Quote {
+		return fmt.Errorf("the symbol %s is not quoted in %s", bc.Symbol, bc.Quote)
+	}
+
+	s, err := newStrategy(bc)
+	if err != nil {
+		return err
+	}
+
+	opts, err := backtestOptions(bc)
+	if err != nil {
+		return err
+	}
+
+	candles, err := loadCandles(cfg.Data.StorePath, bc)
+	if err != nil {
+		return err
+	}
+
+	r, err := backtest.New(opts...).Run(ctx, s, candles)
+	if err != nil {
+		return fmt.Errorf("error running backtest: %w", err)
+	}
+
+	if err := writeTradeLog(bc.Output, r.Trades); err != nil {
+		return err
+	}
+
+	return printReport(w, r, bc)
+}
+
+// newStrategy creates the configured strategy, replayed through a strategy runner unless it is the buy and hold baseline.
+func newStrategy(bc config.Backtest) (backtest.Strategy, error) {
+	name := strings.ToLower(bc.Strategy)
+	if name == strategyHold {
+		return &backtest.BuyAndHold{Quote: bc.Quote}, nil
+	}
+
+	s, err := newRegisteredStrategy(name, bc.Params, bc.Symbol, bc.Quote)
+	if err != nil {
+		return nil, err
+	}
+
+	return strategy.Replay(s), nil
+}
+
+func backtestOptions(bc config.Backtest) ([]backtest.Option, error) {
+	values := make(map[string]decimal.Decimal)
+
+	for name, s := range map[string]string{"capital": bc.Capital, "maker fee": bc.MakerFee, "taker fee": bc.TakerFee, "slippage": bc.Slippage} {
+		d, err := decimal.NewFromString(s)
+		if err != nil || d.IsNegative() {
+			return nil, fmt.Errorf("invalid %s %q", name, s)
+		}
+
+		values[name] = d
+	}
+
+	return []backtest.Option{
+		backtest.WithCapital(values["capital"]),
+		backtest.WithFees(backtest.Fees{Maker: values["maker fee"], Taker: values["taker fee"]}),
+		backtest.WithSlippage(values["slippage"]),
+		backtest.WithAssets(strings.TrimSuffix(bc.Symbol, bc.Quote), bc.Quote),
+	}, nil
+}
+
+// loadCandles reads the candles of the configured series and range from the candle store.
+func loadCandles(path string, bc config.Backtest) ([]connector.Candle, error) {
+	var (
+		start, end time.Time
+		err        error
+	)
+
+	if bc.Start != "" {
+		if start, err = config.ParseTime(bc.Start); err != nil {
+			return nil, fmt.Errorf("invalid start: %w", err)
+		}
+	}
+
+	if bc.End != "" {
+		if end, err = config.ParseTime(bc.End); err != nil {
+			return nil, fmt.Errorf("invalid end: %w", err)
+		}
+	}
+
+	s, err := store.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening candle store: %w", err)
+	}
+	defer s.Close()
+
+	series := store.Series{Exchange: bc.Exchange, Symbol: bc.Symbol, Interval: bc.Interval}
+
+	candles, err := s.Candles(series, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("error reading candles: %w", err)
+	}
+
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no stored candle of %s in the range, import them with the data command", series)
+	}
+
+	return candles, nil
+}
+
+func writeTradeLog(path string, trades []backtest.Trade) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating trade log: %w", err)
+	}
+
+	if err := backtest.WriteTrades(f, trades); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error closing trade log: %w", err)
+	}
+
+	return nil
+}
+
+func printReport(w io.Writer, r *backtest.Report, bc config.Backtest) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "STRATEGY\t%s\n", bc.Strategy)
+	fmt.Fprintf(tw, "SYMBOL\t%s %s\n", r.Symbol, r.Interval)
+	fmt.Fprintf(tw, "RANGE\t%s - %s\n", r.Start.UTC().Format(time.RFC3339), r.End.UTC().Format(time.RFC3339))
+	fmt.Fprintf(tw, "CANDLES\t%d\n", r.Candles)
+	fmt.Fprintf(tw, "INITIAL EQUITY\t%s %s\n", r.InitialEquity.StringFixed(valuePlaces), bc.Quote)
+	fmt.Fprintf(tw, "FINAL EQUITY\t%s %s\n", r.FinalEquity.StringFixed(valuePlaces), bc.Quote)
+	fmt.Fprintf(tw, "TOTAL RETURN\t%s%%\n", pct(r.TotalReturn))
+	fmt.Fprintf(tw, "MAX DRAWDOWN\t%s%%\n", pct(r.MaxDrawdown))
+	fmt.Fprintf(tw, "SHARPE\t%s\n", decimal.NewFromFloat(r.Sharpe).StringFixed(ratioPlaces))
+	fmt.Fprintf(tw, "SORTINO\t%s\n", decimal.NewFromFloat(r.Sortino).StringFixed(ratioPlaces))
+	fmt.Fprintf(tw, "TRADES\t%d\n", len(r.Trades))
+	fmt.Fprintf(tw, "CLOSED TRADES\t%d\n", r.ClosedTrades)
+	fmt.Fprintf(tw, "WIN RATE\t%s%%\n", pct(r.WinRate))
+	fmt.Fprintf(tw, "EXPOSURE\t%s%%\n", pct(r.Exposure))
+	fmt.Fprintf(tw, "FEES\t%s %s\n", r.Fees.StringFixed(valuePlaces), bc.Quote)
+	fmt.Fprintf(tw, "TRADE LOG\t%s\n", bc.Output)
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error writing backtest report: %w", err)
+	}
+
+	return nil
+}
+
+func pct(d decimal.Decimal) string {
+	return d.Mul(decimal.NewFromInt(percent)).StringFixed(percentPlaces)
+}
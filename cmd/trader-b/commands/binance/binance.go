@@ -2,11 +2,19 @@
 package binance
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/twk/trader-b/internal/config"
 	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+	"github.com/twk/trader-b/internal/connector/exchanges"
 )
 
 // NewBinanceCommand creates a new Binance command.
@@ -14,14 +22,21 @@ func NewBinanceCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
 	b := []config.BindDetail{
 		{Flag: config.FlagDetail{Name: "binance-api-key", Description: "The API key for the Binance API", DefaultValue: ""}, MapKey: "connector.binance.api_key", EnvName: "BINANCE_API_KEY"},
 		{Flag: config.FlagDetail{Name: "binance-api-secret", Description: "The API secret for the Binance API", DefaultValue: ""}, MapKey: "connector.binance.secret_key", EnvName: "BINANCE_API_SECRET"},
-		{Flag: config.FlagDetail{Name: "binance-base-url", Description: "The base URL for the Binance API", DefaultValue: ""}, MapKey: "connector.binance.base_url", EnvName: "BINANCE_BASE_URL"},
+		{Flag: config.FlagDetail{Name: "binance-testnet-api-key", Description: "The API key for the Binance testnet API", DefaultValue: ""}, MapKey: "connector.binance.testnet_api_key", EnvName: "BINANCE_TESTNET_API_KEY"},
+		{Flag: config.FlagDetail{Name: "binance-testnet-api-secret", Description: "The API secret for the Binance testnet API", DefaultValue: ""}, MapKey: "connector.binance.testnet_secret_key", EnvName: "BINANCE_TESTNET_API_SECRET"},
+		{Flag: config.FlagDetail{Name: "binance-environment", Description: "The Binance environment to use: mainnet or testnet", DefaultValue: config.EnvironmentMainnet}, MapKey: "connector.binance.environment", EnvName: "BINANCE_ENVIRONMENT"},
+		{Flag: config.FlagDetail{Name: "binance-base-url", Description: "Overrides the REST base URL of the Binance environment", DefaultValue: ""}, MapKey: "connector.binance.base_url", EnvName: "BINANCE_BASE_URL"},
+		{Flag: config.FlagDetail{Name: "binance-ws-base-url", Description: "Overrides the websocket base URL of the Binance environment", DefaultValue: ""}, MapKey: "connector.binance.ws_base_url", EnvName: "BINANCE_WS_BASE_URL"},
+		{Flag: config.FlagDetail{Name: "binance-time-sync-interval", Description: "How often long running commands sync the timestamps of the signed requests with the Binance server time", DefaultValue: binance.DefaultTimeSyncInterval}, MapKey: "connector.binance.time_sync_interval"},
+		{Flag: config.FlagDetail{Name: "binance-symbols-ttl", Description: "How long the Binance symbol catalog is cached before the exchange info is fetched again", DefaultValue: time.Hour}, MapKey: "connector.binance.symbols_ttl"},
 	}
 
 	cmd := &cobra.Command{
 		Use:   "binance",
 		Short: "Interact with the Binance API",
-		RunE: func(_ *cobra.Command, _ []string) error {
-			return binanceRun(v, l)
+		Long:  `The 'binance' command fetches the account from the Binance API and prints the non-zero balances.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return binanceRun(cmd.Context(), cmd.OutOrStdout(), v, l)
 		},
 	}
 
@@ -29,10 +44,18 @@ func NewBinanceCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
 		return nil
 	}
 
+	cmd.AddCommand(newSymbolsCommand(v, l))
+	cmd.AddCommand(newOrderCommand(v, l))
+	cmd.AddCommand(newKlinesCommand(v, l))
+	cmd.AddCommand(newWatchCommand(v, l))
+	cmd.AddCommand(newBookCommand(v, l))
+	cmd.AddCommand(newUserStreamCommand(v, l))
+	cmd.AddCommand(newTimeCommand(v, l))
+
 	return cmd
 }
 
-func binanceRun(v *config.Viper, l *zap.Logger) error {
+func binanceRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger) error {
 	cfg, err := v.BuildConfig()
 	if err != nil {
 		return fmt.Errorf("error building config: %w", err)
@@ -40,5 +63,82 @@ func binanceRun(v *config.Viper, l *zap.Logger) error {
 
 	l.Info("running binance command", zap.Any("config", cfg))
 
+	if err := cfg.Connector.Binance.ValidateCredentials(); err != nil {
+		return fmt.Errorf("error validating binance config: %w", err)
+	}
+
+	e, err := exchanges.NewRegistry().Get(cfg, binance.Name)
+	if err != nil {
+		return fmt.Errorf("error creating binance exchange: %w", err)
+	}
+
+	balances, err := e.GetBalances(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting binance balances: %w", err)
+	}
+
+	return printBalances(w, connector.NonZeroBalances(balances))
+}
+
+func printBalances(w io.Writer, balances []connector.Balance) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ASSET\tFREE\tLOCKED")
+
+	for _, b := range balances {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", b.Asset, b.Free, b.Locked)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error writing balances: %w", err)
+	}
+
 	return nil
 }
+
+// logRateLimitUsage logs the usage of the Binance rate limits, once the requests of a command are made.
+func logRateLimitUsage(l *zap.Logger, s *binance.Service) {
+	for _, u := range s.RateLimitUsage() {
+		l.Info("binance rate limit usage", zap.String("kind", u.Kind), zap.Duration("interval", u.Interval),
+			zap.Int("used", u.Used), zap.Int("limit", u.Limit))
+	}
+}
+
+// NewTradingService creates the Binance service of the commands placing orders. It warns when the endpoints are the mainnet ones.
+func NewTradingService(l *zap.Logger, cfg *config.Config) (*binance.Service, error) {
+	if err := cfg.Connector.Binance.ValidateCredentials(); err != nil {
+		return nil, fmt.Errorf("error validating binance config: %w", err)
+	}
+
+	e, err := binance.ResolveEndpoints(cfg.Connector.Binance)
+	if err != nil {
+		return nil, fmt.Errorf("error validating binance config: %w", err)
+	}
+
+	if e.Mainnet() {
+		l.Warn("!!! BINANCE MAINNET: this command uses live trading endpoints with real funds !!!",
+			zap.String("base_url", e.BaseURL), zap.Bool("dry_run", cfg.Connector.Binance.DryRun))
+	}
+
+	s, err := binance.NewBinanceService(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating binance service: %w", err)
+	}
+
+	return s, nil
+}
+
+// KeepTimeSynced syncs the timestamps of the signed requests of the service with the Binance server time at every sync interval,
+// until the context is done. It is used by the long running commands.
+func KeepTimeSynced(ctx context.Context, l *zap.Logger, s *binance.Service) {
+	go func() {
+		_ = s.TimeSync().Run(ctx, func(d binance.Drift, err error) {
+			if err != nil {
+				l.Warn("error syncing time with binance, keeping the previous offset", zap.Error(err))
+				return
+			}
+
+			l.Info("synced time with binance", zap.Duration("offset", d.Offset), zap.Duration("latency", d.Latency))
+		})
+	}()
+}
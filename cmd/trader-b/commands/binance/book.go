@@ -0,0 +1,159 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+	"github.com/twk/trader-b/internal/orderbook"
+)
+
+// clearScreen moves the cursor home and clears the terminal before every render of the live view.
+const clearScreen = "\033[H\033[2J"
+
+func newBookCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "depth", Description: "The number of levels shown on each side", DefaultValue: 10}, MapKey: "book.depth"},
+		{Flag: config.FlagDetail{Name: "refresh", Description: "How often the view is refreshed", DefaultValue: time.Second}, MapKey: "book.refresh"},
+		{Flag: config.FlagDetail{Name: "vwap-quantity", Description: "Shows the VWAP of buying and selling this quantity against the book", DefaultValue: ""}, MapKey: "book.quantity"},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "book <symbol>",
+		Short: "Show the live order book of a symbol",
+		Long: `The 'book' command maintains a local order book of a symbol from a depth snapshot and the diff depth stream,
+and shows its best levels, spread and VWAP until it is interrupted.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return bookRun(cmd.Context(), cmd.OutOrStdout(), v, l, strings.ToUpper(args[0]))
+		},
+	}
+
+	if err := v.SetFlagAndBind(cmd, b); err != nil {
+		return nil
+	}
+
+	return cmd
+}
+
+func bookRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger, symbol string) error {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info("running binance book command", zap.Any("config", cfg))
+
+	var quantity decimal.Decimal
+
+	if cfg.Book.Quantity != "" {
+		if quantity, err = decimal.NewFromString(cfg.Book.Quantity); err != nil {
+			return fmt.Errorf("invalid vwap quantity %q: %w", cfg.Book.Quantity, err)
+		}
+	}
+
+	if cfg.Book.Refresh <= 0 {
+		return fmt.Errorf("invalid refresh interval %s", cfg.Book.Refresh)
+	}
+
+	e, err := binance.ResolveEndpoints(cfg.Connector.Binance)
+	if err != nil {
+		return fmt.Errorf("error validating binance config: %w", err)
+	}
+
+	s, err := binance.NewBinanceService(cfg)
+	if err != nil {
+		return fmt.Errorf("error creating binance service: %w", err)
+	}
+
+	defer logRateLimitUsage(l, s)
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	syncer := binance.NewBookSyncer(s, binance.NewStreamer(e.WSBaseURL, binance.WithStreamLogger(l)), symbol, binance.WithBookLogger(l))
+
+	errc := make(chan error, 1)
+
+	go func() {
+		errc <- syncer.Run(ctx, nil)
+	}()
+
+	ticker := time.NewTicker(cfg.Book.Refresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-errc:
+			if err != nil && !errors.Is(err, context.Canceled) {
+				return fmt.Errorf("error syncing the %s order book: %w", symbol, err)
+			}
+
+			return nil
+		case <-ticker.C:
+			if syncer.Book().LastUpdateID() == 0 {
+				continue
+			}
+
+			if err := printBook(w, syncer.Book(), cfg.Book.Depth, quantity); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func printBook(w io.Writer, book *orderbook.Book, depth int, quantity decimal.Decimal) error {
+	top := book.Top(depth)
+
+	fmt.Fprint(w, clearScreen)
+	fmt.Fprintf(w, "%s  last update %d  %s\n", top.Symbol, top.LastUpdateID, time.Now().UTC().Format(time.RFC3339))
+
+	if spread, err := book.Spread(); err == nil {
+		fmt.Fprintf(w, "spread %s\n", spread)
+	}
+
+	if quantity.IsPositive() {
+		for _, side := range []connector.Side{connector.SideBuy, connector.SideSell} {
+			vwap, err := book.VWAP(side, quantity)
+			if err != nil {
+				fmt.Fprintf(w, "VWAP %s %s: %s\n", side, quantity, err)
+				continue
+			}
+
+			fmt.Fprintf(w, "VWAP %s %s: %s\n", side, quantity, vwap.Round(8))
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "SIDE\tPRICE\tQUANTITY")
+
+	// The asks are printed from the highest, so that the best bid and ask meet in the middle.
+	for i := len(top.Asks) - 1; i >= 0; i-- {
+		fmt.Fprintf(tw, "ASK\t%s\t%s\n", top.Asks[i].Price, top.Asks[i].Quantity)
+	}
+
+	for _, l := range top.Bids {
+		fmt.Fprintf(tw, "BID\t%s\t%s\n", l.Price, l.Quantity)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error writing order book: %w", err)
+	}
+
+	return nil
+}
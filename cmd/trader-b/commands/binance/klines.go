@@ -0,0 +1,177 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/candlefile"
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+)
+
+func newKlinesCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "symbol", Description: "The symbol of the klines, e.g. BTCUSDT", DefaultValue: ""}, MapKey: "klines.symbol"},
+		{Flag: config.FlagDetail{Name: "interval", Shorthand: "i", Description: "The kline interval, e.g. 1m, 1h or 1d", DefaultValue: "1h"}, MapKey: "klines.interval"},
+		{Flag: config.FlagDetail{Name: "start", Description: "The start of the range as an RFC 3339 time or a date, e.g. 2024-01-01", DefaultValue: ""}, MapKey: "klines.start"},
+		{Flag: config.FlagDetail{Name: "end", Description: "The end of the range as an RFC 3339 time or a date, defaults to now", DefaultValue: ""}, MapKey: "klines.end"},
+		{Flag: config.FlagDetail{Name: "output", Shorthand: "o", Description: "The file the klines are appended to, defaults to the standard output", DefaultValue: ""}, MapKey: "klines.output"},
+		{Flag: config.FlagDetail{Name: "format", Shorthand: "f", Description: "The output format, csv or jsonl", DefaultValue: string(candlefile.FormatCSV)}, MapKey: "klines.format"},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "klines",
+		Short: "Download the klines of a symbol",
+		Long: `The 'klines' command downloads the closed klines of a symbol between two times and writes them as CSV or JSON lines.
+When the output file already holds klines, the download resumes after the last one.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return klinesRun(cmd.Context(), cmd.OutOrStdout(), v, l)
+		},
+	}
+
+	if err := v.SetFlagAndBind(cmd, b); err != nil {
+		return nil
+	}
+
+	return cmd
+}
+
+func klinesRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger) error {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info("running binance klines command", zap.Any("config", cfg))
+
+	now := time.Now()
+
+	q, format, err := newKlinesQuery(cfg.Klines, now)
+	if err != nil {
+		return err
+	}
+
+	s, err := binance.NewBinanceService(cfg)
+	if err != nil {
+		return fmt.Errorf("error creating binance service: %w", err)
+	}
+
+	defer logRateLimitUsage(l, s)
+
+	out, header := w, format == candlefile.FormatCSV
+
+	if cfg.Klines.Output != "" {
+		f, err := os.OpenFile(cfg.Klines.Output, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("error opening klines file: %w", err)
+		}
+		defer f.Close()
+
+		if header, err = resumeKlines(f, format, &q, l); err != nil {
+			return err
+		}
+
+		out = f
+	}
+
+	if q.Start.IsZero() {
+		return errors.New("a start time is required unless the output file already holds klines")
+	}
+
+	cw := candlefile.NewWriter(out, format, header)
+	written := 0
+
+	err = s.FetchKlines(ctx, q, func(candles []connector.Candle) error {
+		closed := make([]connector.Candle, 0, len(candles))
+
+		for _, c := range candles {
+			if c.Closed(now) {
+				closed = append(closed, c)
+			}
+		}
+
+		written += len(closed)
+
+		return cw.Write(closed)
+	})
+	if err != nil {
+		return fmt.Errorf("error downloading klines: %w", err)
+	}
+
+	l.Info("downloaded klines", zap.String("symbol", q.Symbol), zap.String("interval", q.Interval), zap.Int("count", written))
+
+	return nil
+}
+
+// resumeKlines moves the start of the query after the last kline of the file, and reports whether the file still needs a header.
+func resumeKlines(f *os.File, format candlefile.Format, q *binance.KlinesQuery, l *zap.Logger) (bool, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return false, fmt.Errorf("error reading klines file: %w", err)
+	}
+
+	last, ok, err := candlefile.Last(f, format)
+	if err != nil {
+		return false, fmt.Errorf("error reading klines file: %w", err)
+	}
+
+	if !ok {
+		return info.Size() == 0, nil
+	}
+
+	if last.Symbol != q.Symbol || last.Interval != q.Interval {
+		return false, fmt.Errorf("klines file %s holds %s %s klines, not %s %s", f.Name(), last.Symbol, last.Interval, q.Symbol, q.Interval)
+	}
+
+	d, err := binance.IntervalDuration(q.Interval)
+	if err != nil {
+		return false, fmt.Errorf("error resuming klines download: %w", err)
+	}
+
+	if next := last.OpenTime.Add(d); next.After(q.Start) {
+		l.Info("resuming klines download", zap.String("file", f.Name()), zap.Time("from", next))
+		q.Start = next
+	}
+
+	return false, nil
+}
+
+func newKlinesQuery(k config.Klines, now time.Time) (binance.KlinesQuery, candlefile.Format, error) {
+	q := binance.KlinesQuery{Symbol: strings.ToUpper(k.Symbol), Interval: k.Interval, End: now}
+
+	if q.Symbol == "" {
+		return q, "", errors.New("a symbol is required")
+	}
+
+	if _, err := binance.IntervalDuration(q.Interval); err != nil {
+		return q, "", fmt.Errorf("invalid klines interval: %w", err)
+	}
+
+	format, err := candlefile.ParseFormat(k.Format)
+	if err != nil {
+		return q, "", fmt.Errorf("invalid klines format: %w", err)
+	}
+
+	if k.Start != "" {
+		if q.Start, err = config.ParseTime(k.Start); err != nil {
+			return q, "", fmt.Errorf("invalid klines start: %w", err)
+		}
+	}
+
+	if k.End != "" {
+		if q.End, err = config.ParseTime(k.End); err != nil {
+			return q, "", fmt.Errorf("invalid klines end: %w", err)
+		}
+	}
+
+	return q, format, nil
+}
@@ -0,0 +1,216 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/cmd/trader-b/commands/flags"
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+	"github.com/twk/trader-b/internal/risk"
+)
+
+// newOrderCommand creates the binance order command. Its flags share the configuration keys of the order command,
+// so they are only bound when one of its subcommands runs.
+func newOrderCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "symbol", Description: "The symbol of the order, e.g. BTCUSDT", DefaultValue: ""}, MapKey: "order.symbol"},
+		{Flag: config.FlagDetail{Name: "order-id", Description: "The ID of the order to cancel or query", DefaultValue: 0}, MapKey: "order.id"},
+	}
+	b = append(b, flags.DryRun()...)
+	b = append(b, flags.Risk()...)
+
+	cmd := &cobra.Command{
+		Use:   "order",
+		Short: "Place, cancel and query Binance spot orders",
+	}
+
+	cmd.PersistentPreRunE = func(_ *cobra.Command, _ []string) error {
+		if err := v.Binds(cmd, b); err != nil {
+			return fmt.Errorf("error binding binance order flags: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := v.SetFlags(cmd, b); err != nil {
+		return nil
+	}
+
+	cmd.AddCommand(newOrderPlaceCommand(v, l))
+	cmd.AddCommand(newOrderCancelCommand(v, l))
+	cmd.AddCommand(newOrderStatusCommand(v, l))
+	cmd.AddCommand(newOrderOpenCommand(v, l))
+
+	return cmd
+}
+
+func newOrderPlaceCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "side", Description: "The side of the order, BUY or SELL", DefaultValue: ""}, MapKey: "order.side"},
+		{Flag: config.FlagDetail{Name: "type", Description: "The type of the order, LIMIT or MARKET", DefaultValue: string(connector.OrderTypeLimit)}, MapKey: "order.type"},
+		{Flag: config.FlagDetail{Name: "quantity", Description: "The quantity of the order in the base asset", DefaultValue: ""}, MapKey: "order.quantity"},
+		{Flag: config.FlagDetail{Name: "price", Description: "The limit price of the order, ignored for market orders", DefaultValue: ""}, MapKey: "order.price"},
+		{Flag: config.FlagDetail{Name: "time-in-force", Description: "The time in force of limit orders, GTC, IOC or FOK", DefaultValue: ""}, MapKey: "order.time_in_force"},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "place",
+		Short: "Place a new order",
+		Long:  `The 'place' command rounds the price and quantity to the symbol filters, checks the order with the risk engine and places it.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return orderPlaceRun(cmd.Context(), cmd.OutOrStdout(), v, l)
+		},
+	}
+
+	if err := v.SetFlagAndBind(cmd, b); err != nil {
+		return nil
+	}
+
+	return cmd
+}
+
+func newOrderCancelCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel an open order",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return orderRun(cmd.Context(), cmd.OutOrStdout(), v, l, func(ctx context.Context, s *binance.Service, cfg *config.Config) ([]connector.Order, error) {
+				order, err := s.CancelOrder(ctx, cfg.Order.Symbol, cfg.Order.ID)
+				if err != nil {
+					return nil, fmt.Errorf("error cancelling order: %w", err)
+				}
+
+				return []connector.Order{*order}, nil
+			})
+		},
+	}
+}
+
+func newOrderStatusCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the status of an order",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return orderRun(cmd.Context(), cmd.OutOrStdout(), v, l, func(ctx context.Context, s *binance.Service, cfg *config.Config) ([]connector.Order, error) {
+				order, err := s.GetOrder(ctx, cfg.Order.Symbol, cfg.Order.ID)
+				if err != nil {
+					return nil, fmt.Errorf("error getting order: %w", err)
+				}
+
+				return []connector.Order{*order}, nil
+			})
+		},
+	}
+}
+
+func newOrderOpenCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "open",
+		Short: "List the open orders of the symbol, or of every symbol when none is given",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return orderRun(cmd.Context(), cmd.OutOrStdout(), v, l, func(ctx context.Context, s *binance.Service, cfg *config.Config) ([]connector.Order, error) {
+				orders, err := s.ListOpenOrders(ctx, cfg.Order.Symbol)
+				if err != nil {
+					return nil, fmt.Errorf("error listing open orders: %w", err)
+				}
+
+				return orders, nil
+			})
+		},
+	}
+}
+
+type orderFunc func(ctx context.Context, s *binance.Service, cfg *config.Config) ([]connector.Order, error)
+
+func orderRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger, f orderFunc) error {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info("running binance order command", zap.Any("config", cfg))
+
+	s, err := NewTradingService(l, cfg)
+	if err != nil {
+		return err
+	}
+
+	defer logRateLimitUsage(l, s)
+
+	orders, err := f(ctx, s, cfg)
+	if err != nil {
+		return err
+	}
+
+	return PrintOrders(w, orders)
+}
+
+func orderPlaceRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger) error {
+	return orderRun(ctx, w, v, l, func(ctx context.Context, s *binance.Service, cfg *config.Config) ([]connector.Order, error) {
+		req, err := connector.NewOrderRequest(cfg.Order)
+		if err != nil {
+			return nil, err
+		}
+
+		n := binance.NewNormalizedService(s, binance.NewCatalog(s, 0))
+
+		g, err := risk.NewFromConfig(cfg, n, risk.WithLogger(l))
+		if err != nil {
+			return nil, fmt.Errorf("error creating risk engine: %w", err)
+		}
+
+		order, err := PlaceOrder(ctx, l, g, n, req)
+		if err != nil {
+			return nil, err
+		}
+
+		return []connector.Order{*order}, nil
+	})
+}
+
+// PlaceOrder rounds the order to the filters of its symbol and logs it, then places it with the trader, e.g. the risk engine of the service.
+// In dry-run mode, the order logged is the one Binance validated but that was not placed.
+func PlaceOrder(ctx context.Context, l *zap.Logger, t connector.Trader, s *binance.NormalizedService, req connector.OrderRequest) (*connector.Order, error) {
+	req, err := s.Normalize(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Info("placing order", zap.Bool("dry_run", s.DryRun()), zap.String("symbol", req.Symbol), zap.String("side", string(req.Side)), zap.String("type", string(req.Type)),
+		zap.Stringer("price", req.Price), zap.Stringer("quantity", req.Quantity))
+
+	order, err := t.PlaceOrder(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("error placing order: %w", err)
+	}
+
+	if s.DryRun() {
+		l.Warn("dry run: the order was validated by Binance but not placed", zap.Any("order", order))
+	}
+
+	return order, nil
+}
+
+// PrintOrders prints the orders as a table.
+func PrintOrders(w io.Writer, orders []connector.Order) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tSYMBOL\tSIDE\tTYPE\tSTATUS\tPRICE\tQUANTITY\tEXECUTED")
+
+	for _, o := range orders {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", o.ID, o.Symbol, o.Side, o.Type, o.Status, o.Price, o.Quantity, o.ExecutedQuantity)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error writing orders: %w", err)
+	}
+
+	return nil
+}
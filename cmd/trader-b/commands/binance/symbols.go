@@ -0,0 +1,78 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+)
+
+func newSymbolsCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "quote", Shorthand: "q", Description: "Only list the symbols quoted in this asset, e.g. USDT", DefaultValue: ""}, MapKey: "symbols.quote_asset"},
+		{Flag: config.FlagDetail{Name: "status", Shorthand: "s", Description: "Only list the symbols with this status, e.g. TRADING", DefaultValue: ""}, MapKey: "symbols.status"},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "symbols",
+		Short: "List the symbols listed on Binance",
+		Long:  `The 'symbols' command lists the symbols listed on Binance with their trading filters.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return symbolsRun(cmd.Context(), cmd.OutOrStdout(), v, l)
+		},
+	}
+
+	if err := v.SetFlagAndBind(cmd, b); err != nil {
+		return nil
+	}
+
+	return cmd
+}
+
+func symbolsRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger) error {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info("running binance symbols command", zap.Any("config", cfg))
+
+	s, err := binance.NewBinanceService(cfg)
+	if err != nil {
+		return fmt.Errorf("error creating binance service: %w", err)
+	}
+
+	defer logRateLimitUsage(l, s)
+
+	c := binance.NewCatalog(s, cfg.Connector.Binance.SymbolsTTL)
+
+	symbols, err := c.Symbols(ctx, binance.SymbolQuery{QuoteAsset: cfg.Symbols.QuoteAsset, Status: cfg.Symbols.Status})
+	if err != nil {
+		return fmt.Errorf("error getting binance symbols: %w", err)
+	}
+
+	return printSymbols(w, symbols)
+}
+
+func printSymbols(w io.Writer, symbols []connector.Symbol) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "SYMBOL\tBASE\tQUOTE\tSTATUS\tTICK SIZE\tSTEP SIZE\tMIN NOTIONAL")
+
+	for _, s := range symbols {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", s.Name, s.BaseAsset, s.QuoteAsset, s.Status, s.Filters.TickSize, s.Filters.StepSize, s.Filters.MinNotional)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error writing symbols: %w", err)
+	}
+
+	return nil
+}
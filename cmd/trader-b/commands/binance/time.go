@@ -0,0 +1,110 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector/binance"
+)
+
+// timeLayout is the layout of the local and server times, to the millisecond like the Binance timestamps.
+const timeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+func newTimeCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "samples", Shorthand: "n", Description: "The number of measures of the clock drift", DefaultValue: 5}, MapKey: "time.samples"},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "time",
+		Short: "Show the drift of the local clock from the Binance server clock",
+		Long: `The 'time' command queries the Binance server time several times, and shows the offset of the server clock from the local clock
+and the round trip latency of every measure. The offset of the measure with the lowest latency is the most accurate one.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return timeRun(cmd.Context(), cmd.OutOrStdout(), v, l)
+		},
+	}
+
+	if err := v.SetFlagAndBind(cmd, b); err != nil {
+		return nil
+	}
+
+	return cmd
+}
+
+func timeRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger) error {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info("running binance time command", zap.Any("config", cfg))
+
+	if cfg.Time.Samples <= 0 {
+		return fmt.Errorf("invalid number of samples %d", cfg.Time.Samples)
+	}
+
+	s, err := binance.NewBinanceService(cfg)
+	if err != nil {
+		return fmt.Errorf("error creating binance service: %w", err)
+	}
+
+	defer logRateLimitUsage(l, s)
+
+	drifts := make([]binance.Drift, 0, cfg.Time.Samples)
+
+	for i := 0; i < cfg.Time.Samples; i++ {
+		d, err := s.TimeSync().Measure(ctx)
+		if err != nil {
+			return fmt.Errorf("error measuring binance clock drift: %w", err)
+		}
+
+		drifts = append(drifts, d)
+	}
+
+	return printDrifts(w, drifts)
+}
+
+func printDrifts(w io.Writer, drifts []binance.Drift) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "SAMPLE\tLOCAL TIME\tSERVER TIME\tOFFSET\tLATENCY")
+
+	best := drifts[0]
+
+	for i, d := range drifts {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", i+1, d.LocalTime.UTC().Format(timeLayout), d.ServerTime.UTC().Format(timeLayout),
+			d.Offset.Round(time.Microsecond), d.Latency.Round(time.Microsecond))
+
+		if d.Latency < best.Latency {
+			best = d
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error writing clock drift: %w", err)
+	}
+
+	direction := "behind"
+	if best.Offset < 0 {
+		direction = "ahead of"
+	}
+
+	fmt.Fprintf(w, "\nthe local clock is %s %s the binance server clock (latency %s)\n", best.Offset.Abs().Round(time.Millisecond), direction,
+		best.Latency.Round(time.Microsecond))
+
+	if best.Rejected() {
+		fmt.Fprintf(w, "signed requests stamped by the local clock are rejected with -1021, as binance accepts timestamps at most %s ahead and %s behind\n",
+			binance.MaxClockAhead, binance.MaxClockBehind)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/account"
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+)
+
+// userDataBufferSize is the number of user data events buffered while they are printed.
+const userDataBufferSize = 100
+
+func newUserStreamCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "user-stream",
+		Short: "Print the live order and balance updates of the account",
+		Long: `The 'user-stream' command connects to the user data stream, loads the balances and open orders of the Binance account once,
+then keeps them current from the updates of the stream and prints every update until it is interrupted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return userStreamRun(cmd.Context(), cmd.OutOrStdout(), v, l)
+		},
+	}
+}
+
+func userStreamRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger) error {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info("running binance user-stream command", zap.Any("config", cfg))
+
+	if err := cfg.Connector.Binance.ValidateCredentials(); err != nil {
+		return fmt.Errorf("error validating binance config: %w", err)
+	}
+
+	e, err := binance.ResolveEndpoints(cfg.Connector.Binance)
+	if err != nil {
+		return fmt.Errorf("error validating binance config: %w", err)
+	}
+
+	s, err := binance.NewBinanceService(cfg)
+	if err != nil {
+		return fmt.Errorf("error creating binance service: %w", err)
+	}
+
+	defer logRateLimitUsage(l, s)
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	KeepTimeSynced(ctx, l, s)
+
+	// The stream is connected before the balances and open orders are loaded, so that the updates made meanwhile are buffered and
+	// applied on top of them instead of being missed.
+	connected := make(chan struct{})
+	streamer := binance.NewStreamer(e.WSBaseURL, binance.WithStreamLogger(l), binance.WithOnConnect(sync.OnceFunc(func() { close(connected) })))
+	stream := binance.NewUserDataStream(s, streamer, binance.WithUserDataLogger(l))
+	events := make(chan binance.UserDataEvent, userDataBufferSize)
+	errc := make(chan error, 1)
+
+	go func() {
+		errc <- stream.Run(ctx, events)
+	}()
+
+	select {
+	case <-connected:
+	case err := <-errc:
+		return userStreamError(err)
+	}
+
+	balances, err := s.GetBalances(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting binance balances: %w", err)
+	}
+
+	orders, err := s.ListOpenOrders(ctx, "")
+	if err != nil {
+		return fmt.Errorf("error getting binance open orders: %w", err)
+	}
+
+	state := account.New(balances, orders)
+
+	if err := printBalances(w, connector.NonZeroBalances(state.Balances())); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "%d open orders\n", len(state.OpenOrders("")))
+
+	for event := range events {
+		event.ApplyTo(state)
+		fmt.Fprintln(w, formatUserDataEvent(event, state))
+	}
+
+	return userStreamError(<-errc)
+}
+
+// userStreamError returns the error of the user data stream, unless it was stopped by an interruption.
+func userStreamError(err error) error {
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("error streaming binance user data: %w", err)
+	}
+
+	return nil
+}
+
+func formatUserDataEvent(e binance.UserDataEvent, state *account.State) string {
+	switch {
+	case e.ExecutionReport != nil:
+		r := e.ExecutionReport
+		o := r.Order
+
+		line := fmt.Sprintf("ORDER    %s  %s  %d  %s %s %s/%s @ %s  %s  %s", r.Time.Format(time.RFC3339Nano), o.Symbol, o.ID,
+			o.Side, o.Type, o.ExecutedQuantity, o.Quantity, o.Price, r.ExecutionType, o.Status)
+		if r.LastQuantity.IsPositive() {
+			line += fmt.Sprintf("  last %s @ %s", r.LastQuantity, r.LastPrice)
+		}
+
+		if r.RejectReason != "" && r.RejectReason != "NONE" {
+			line += "  " + r.RejectReason
+		}
+
+		return fmt.Sprintf("%s  (%d open orders)", line, len(state.OpenOrders("")))
+	case e.AccountPosition != nil:
+		line := "BALANCE  " + e.AccountPosition.Time.Format(time.RFC3339Nano)
+		for _, b := range e.AccountPosition.Balances {
+			line += fmt.Sprintf("  %s free %s locked %s", b.Asset, b.Free, b.Locked)
+		}
+
+		return line
+	default:
+		return ""
+	}
+}
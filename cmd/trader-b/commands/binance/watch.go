@@ -0,0 +1,122 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector/binance"
+)
+
+func newWatchCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "streams", Description: "Comma separated stream types: trade, bookTicker, kline and depth", DefaultValue: "trade,bookTicker"}, MapKey: "watch.streams"},
+		{Flag: config.FlagDetail{Name: "interval", Shorthand: "i", Description: "The interval of the kline stream, e.g. 1m", DefaultValue: "1m"}, MapKey: "watch.interval"},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "watch <symbol>",
+		Short: "Print the live market data of a symbol",
+		Long:  `The 'watch' command subscribes to the Binance websocket streams of a symbol and prints every update until it is interrupted.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return watchRun(cmd.Context(), cmd.OutOrStdout(), v, l, args[0])
+		},
+	}
+
+	if err := v.SetFlagAndBind(cmd, b); err != nil {
+		return nil
+	}
+
+	return cmd
+}
+
+func watchRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger, symbol string) error {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info("running binance watch command", zap.Any("config", cfg))
+
+	streams, err := newStreams(symbol, cfg.Watch)
+	if err != nil {
+		return err
+	}
+
+	e, err := binance.ResolveEndpoints(cfg.Connector.Binance)
+	if err != nil {
+		return fmt.Errorf("error validating binance config: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s := binance.NewStreamer(e.WSBaseURL, binance.WithStreamLogger(l))
+
+	err = s.Run(ctx, streams, func(event binance.StreamEvent) error {
+		fmt.Fprintln(w, formatStreamEvent(event))
+		return nil
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("error watching %s: %w", symbol, err)
+	}
+
+	return nil
+}
+
+func newStreams(symbol string, cfg config.Watch) ([]binance.Stream, error) {
+	var streams []binance.Stream
+
+	for _, name := range strings.Split(cfg.Streams, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+
+		t, err := binance.ParseStreamType(strings.TrimSpace(name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid stream: %w", err)
+		}
+
+		streams = append(streams, binance.Stream{Symbol: symbol, Type: t, Interval: cfg.Interval})
+	}
+
+	return streams, nil
+}
+
+func formatStreamEvent(e binance.StreamEvent) string {
+	switch {
+	case e.Trade != nil:
+		side := "BUY"
+		if e.Trade.IsBuyerMaker {
+			side = "SELL"
+		}
+
+		return fmt.Sprintf("TRADE  %s  %s  %-4s %s @ %s", e.Trade.Symbol, e.Trade.Time.UTC().Format(time.RFC3339Nano), side, e.Trade.Quantity, e.Trade.Price)
+	case e.BookTicker != nil:
+		b := e.BookTicker
+
+		return fmt.Sprintf("BOOK   %s  bid %s @ %s  ask %s @ %s", b.Symbol, b.BidQty, b.BidPrice, b.AskQty, b.AskPrice)
+	case e.Kline != nil:
+		c := e.Kline.Candle
+
+		return fmt.Sprintf("KLINE  %s  %s  %s  O %s  H %s  L %s  C %s  V %s  closed=%t", c.Symbol, c.Interval, c.OpenTime.Format(time.RFC3339),
+			c.Open, c.High, c.Low, c.Close, c.Volume, e.Kline.Closed)
+	case e.Depth != nil:
+		d := e.Depth
+
+		return fmt.Sprintf("DEPTH  %s  updates %d-%d  %d bids  %d asks", d.Symbol, d.FirstUpdateID, d.FinalUpdateID, len(d.Bids), len(d.Asks))
+	default:
+		return e.Stream
+	}
+}
@@ -0,0 +1,52 @@
+// Package data provides the data command for the application. It manages the local candle store.
+package data
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector/binance"
+	"github.com/twk/trader-b/internal/store"
+)
+
+// NewDataCommand creates a new data command.
+func NewDataCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "store", Description: "The path of the candle store file", DefaultValue: "./trader-b.db"}, MapKey: "data.store_path", EnvName: "TRADER_B_STORE"},
+		{Flag: config.FlagDetail{Name: "exchange", Description: "The exchange of the candles", DefaultValue: binance.Name}, MapKey: "data.exchange"},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "data",
+		Short: "Manage the local candle store",
+	}
+
+	if err := v.SetFlagAndBind(cmd, b); err != nil {
+		return nil
+	}
+
+	cmd.AddCommand(newGapsCommand(v, l))
+	cmd.AddCommand(newImportCommand(v, l))
+
+	return cmd
+}
+
+// openStore builds the configuration and opens the candle store.
+func openStore(v *config.Viper, l *zap.Logger, msg string) (*config.Config, *store.Store, error) {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info(msg, zap.Any("config", cfg))
+
+	s, err := store.Open(cfg.Data.StorePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening candle store: %w", err)
+	}
+
+	return cfg, s, nil
+}
@@ -0,0 +1,203 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+	"github.com/twk/trader-b/internal/store"
+)
+
+// seriesGap is a gap of a series of the store.
+type seriesGap struct {
+	series   store.Series
+	interval time.Duration
+	gap      store.Gap
+}
+
+func newGapsCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "symbol", Description: "The symbol of the series, every series of the store when empty", DefaultValue: ""}, MapKey: "data.symbol"},
+		{Flag: config.FlagDetail{Name: "interval", Shorthand: "i", Description: "The interval of the series, e.g. 1h, every interval when empty", DefaultValue: ""}, MapKey: "data.interval"},
+		{Flag: config.FlagDetail{Name: "start", Description: "The start of the checked range as an RFC 3339 time or a date, defaults to the first stored candle", DefaultValue: ""}, MapKey: "data.start"},
+		{Flag: config.FlagDetail{Name: "end", Description: "The end of the checked range as an RFC 3339 time or a date, defaults to the last stored candle", DefaultValue: ""}, MapKey: "data.end"},
+		{Flag: config.FlagDetail{Name: "backfill", Description: "Downloads the missing candles from the exchange", DefaultValue: false}, MapKey: "data.backfill"},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "gaps",
+		Short: "Report the missing candles of the store",
+		Long: `The 'gaps' command lists the ranges of candles missing from the series of the candle store.
+With --backfill, the missing candles are downloaded from the exchange and written to the store.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return gapsRun(cmd.Context(), cmd.OutOrStdout(), v, l)
+		},
+	}
+
+	if err := v.SetFlagAndBind(cmd, b); err != nil {
+		return nil
+	}
+
+	return cmd
+}
+
+func gapsRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger) error {
+	cfg, s, err := openStore(v, l, "running data gaps command")
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	start, end, err := parseRange(cfg.Data)
+	if err != nil {
+		return err
+	}
+
+	series, err := selectSeries(s, cfg.Data)
+	if err != nil {
+		return err
+	}
+
+	var gaps []seriesGap
+
+	for _, sr := range series {
+		d, err := binance.IntervalDuration(sr.Interval)
+		if err != nil {
+			return fmt.Errorf("error checking %s: %w", sr, err)
+		}
+
+		found, err := s.Gaps(sr, d, start, end)
+		if err != nil {
+			return fmt.Errorf("error finding gaps: %w", err)
+		}
+
+		for _, g := range found {
+			gaps = append(gaps, seriesGap{series: sr, interval: d, gap: g})
+		}
+	}
+
+	if err := printGaps(w, gaps); err != nil {
+		return err
+	}
+
+	if !cfg.Data.Backfill || len(gaps) == 0 {
+		return nil
+	}
+
+	return backfill(ctx, cfg, s, gaps, l)
+}
+
+func parseRange(d config.Data) (time.Time, time.Time, error) {
+	var (
+		start, end time.Time
+		err        error
+	)
+
+	if d.Start != "" {
+		if start, err = config.ParseTime(d.Start); err != nil {
+			return start, end, fmt.Errorf("invalid start: %w", err)
+		}
+	}
+
+	if d.End != "" {
+		if end, err = config.ParseTime(d.End); err != nil {
+			return start, end, fmt.Errorf("invalid end: %w", err)
+		}
+	}
+
+	return start, end, nil
+}
+
+// selectSeries returns the series of the configured symbol, or every stored series of the exchange and interval when the symbol is empty.
+func selectSeries(s *store.Store, d config.Data) ([]store.Series, error) {
+	if d.Symbol != "" {
+		if d.Interval == "" {
+			return nil, errors.New("an interval is required with a symbol")
+		}
+
+		return []store.Series{{Exchange: d.Exchange, Symbol: strings.ToUpper(d.Symbol), Interval: d.Interval}}, nil
+	}
+
+	all, err := s.ListSeries()
+	if err != nil {
+		return nil, fmt.Errorf("error listing series: %w", err)
+	}
+
+	series := make([]store.Series, 0, len(all))
+
+	for _, sr := range all {
+		if (d.Exchange == "" || sr.Exchange == d.Exchange) && (d.Interval == "" || sr.Interval == d.Interval) {
+			series = append(series, sr)
+		}
+	}
+
+	return series, nil
+}
+
+// backfill downloads the closed candles of the gaps from Binance and writes them to the store.
+func backfill(ctx context.Context, cfg *config.Config, s *store.Store, gaps []seriesGap, l *zap.Logger) error {
+	b, err := binance.NewBinanceService(cfg)
+	if err != nil {
+		return fmt.Errorf("error creating binance service: %w", err)
+	}
+
+	now := time.Now()
+
+	for _, g := range gaps {
+		if g.series.Exchange != binance.Name {
+			return fmt.Errorf("backfilling %s is not supported, only %s can be backfilled", g.series, binance.Name)
+		}
+
+		filled := 0
+		q := binance.KlinesQuery{Symbol: g.series.Symbol, Interval: g.series.Interval, Start: g.gap.Start, End: g.gap.End}
+
+		err := b.FetchKlines(ctx, q, func(candles []connector.Candle) error {
+			closed := make([]connector.Candle, 0, len(candles))
+
+			for _, c := range candles {
+				if c.Closed(now) {
+					closed = append(closed, c)
+				}
+			}
+
+			filled += len(closed)
+
+			return s.Upsert(g.series.Exchange, closed)
+		})
+		if err != nil {
+			return fmt.Errorf("error backfilling %s: %w", g.series, err)
+		}
+
+		l.Info("backfilled gap", zap.Stringer("series", g.series), zap.Time("start", g.gap.Start), zap.Time("end", g.gap.End),
+			zap.Int("missing", g.gap.Missing(g.interval)), zap.Int("filled", filled))
+	}
+
+	return nil
+}
+
+func printGaps(w io.Writer, gaps []seriesGap) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "EXCHANGE\tSYMBOL\tINTERVAL\tSTART\tEND\tMISSING")
+
+	for _, g := range gaps {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\n", g.series.Exchange, g.series.Symbol, g.series.Interval,
+			g.gap.Start.UTC().Format(time.RFC3339), g.gap.End.UTC().Format(time.RFC3339), g.gap.Missing(g.interval))
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error writing gaps: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package data
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/candlefile"
+	"github.com/twk/trader-b/internal/config"
+)
+
+func newImportCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "format", Shorthand: "f", Description: "The format of the file, csv or jsonl, guessed from the extension when empty", DefaultValue: ""}, MapKey: "data.format"},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import a klines file into the store",
+		Long:  `The 'import' command writes the candles of a file downloaded with 'binance klines' to the candle store. Importing a file twice is harmless.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return importRun(cmd.OutOrStdout(), v, l, args[0])
+		},
+	}
+
+	if err := v.SetFlagAndBind(cmd, b); err != nil {
+		return nil
+	}
+
+	return cmd
+}
+
+func importRun(w io.Writer, v *config.Viper, l *zap.Logger, path string) error {
+	cfg, s, err := openStore(v, l, "running data import command")
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	name := cfg.Data.Format
+	if name == "" {
+		name = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+
+	format, err := candlefile.ParseFormat(name)
+	if err != nil {
+		return fmt.Errorf("invalid import format: %w", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening klines file: %w", err)
+	}
+	defer f.Close()
+
+	candles, err := candlefile.Read(f, format)
+	if err != nil {
+		return fmt.Errorf("error reading klines file: %w", err)
+	}
+
+	if err := s.Upsert(cfg.Data.Exchange, candles); err != nil {
+		return fmt.Errorf("error importing candles: %w", err)
+	}
+
+	fmt.Fprintf(w, "imported %d candles into %s\n", len(candles), cfg.Data.StorePath)
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	binancecmd "github.com/twk/trader-b/cmd/trader-b/commands/binance"
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector/binance"
+	"github.com/twk/trader-b/internal/connector/exchanges"
+	"github.com/twk/trader-b/internal/risk"
+)
+
+// newTradingExchange creates the exchange the orders are placed on. Binance is created as the binance order command creates it,
+// and rounds the price and quantity of the orders to the filters of their symbol. Its time stays synced until the context is done,
+// as the strategies place orders long after it is created.
+func newTradingExchange(ctx context.Context, l *zap.Logger, cfg *config.Config, name string) (risk.Exchange, error) {
+	if name == binance.Name {
+		s, err := binancecmd.NewTradingService(l, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		binancecmd.KeepTimeSynced(ctx, l, s)
+
+		return binance.NewNormalizedService(s, binance.NewCatalog(s, cfg.Connector.Binance.SymbolsTTL)), nil
+	}
+
+	e, err := exchanges.NewRegistry().Get(cfg, name)
+	if err != nil {
+		return nil, fmt.Errorf("error creating exchange: %w", err)
+	}
+
+	t, ok := e.(risk.Exchange)
+	if !ok {
+		return nil, fmt.Errorf("the %s exchange cannot trade", e.Name())
+	}
+
+	return t, nil
+}
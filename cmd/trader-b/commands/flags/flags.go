@@ -0,0 +1,34 @@
+// Package flags provides the flags shared by several commands. As their configuration keys are shared, the commands set them with
+// SetFlags and bind them with Binds when they run, so that the key is read from the flag of the command that runs.
+package flags
+
+import (
+	"github.com/twk/trader-b/internal/config"
+)
+
+// DryRun returns the flag validating the Binance orders with the test order endpoint instead of placing them.
+func DryRun() []config.BindDetail {
+	return []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "dry-run", Description: "Validates the Binance orders with the test order endpoint instead of placing them", DefaultValue: false}, MapKey: "connector.binance.dry_run", EnvName: "BINANCE_DRY_RUN"},
+	}
+}
+
+// Paper returns the flags of the paper exchange, which simulates the orders with virtual balances.
+func Paper() []config.BindDetail {
+	return []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "paper-source", Description: "The exchange whose live order books the paper orders are matched against", DefaultValue: "binance"}, MapKey: "connector.paper.source"},
+		{Flag: config.FlagDetail{Name: "paper-books", Description: "The JSON file of the recorded order books the paper orders are matched against instead of the live ones", DefaultValue: ""}, MapKey: "connector.paper.books_path"},
+		{Flag: config.FlagDetail{Name: "paper-state", Description: "The file keeping the balances and orders of the paper exchange between runs", DefaultValue: "./paper.json"}, MapKey: "connector.paper.state_path", EnvName: "TRADER_B_PAPER_STATE"},
+		{Flag: config.FlagDetail{Name: "paper-balances", Description: "The initial balances of the paper exchange, e.g. USDT=10000,BTC=0.5", DefaultValue: "USDT=10000"}, MapKey: "connector.paper.balances"},
+		{Flag: config.FlagDetail{Name: "paper-maker-fee", Description: "The fee of the paper orders filled once they rest in the book, as a fraction of the traded amount", DefaultValue: "0.001"}, MapKey: "connector.paper.maker_fee"},
+		{Flag: config.FlagDetail{Name: "paper-taker-fee", Description: "The fee of the paper orders filled against the book, as a fraction of the traded amount", DefaultValue: "0.001"}, MapKey: "connector.paper.taker_fee"},
+	}
+}
+
+// Risk returns the flags of the risk engine checking the orders before they are placed.
+func Risk() []config.BindDetail {
+	return []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "kill-switch", Description: "The file whose existence blocks every new order", DefaultValue: "./trader-b.kill"}, MapKey: "risk.kill_switch_path", EnvName: "TRADER_B_KILL_SWITCH"},
+		{Flag: config.FlagDetail{Name: "risk-state", Description: "The file keeping the equity the daily loss of the risk checks is measured from", DefaultValue: "./risk.json"}, MapKey: "risk.state_path", EnvName: "TRADER_B_RISK_STATE"},
+	}
+}
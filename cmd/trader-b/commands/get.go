@@ -52,7 +52,7 @@ func get(v *config.Viper, l *zap.Logger, concurrency int) error {
 	httpClient := &http.Client{
 		Timeout: cfg.Get.Timeout,
 	}
-	hc := client.NewClient(httpClient)
+	hc := client.NewClient(httpClient, client.WithRetry(cfg.Retry), client.WithLogger(l))
 	ps := photos.NewService(hc, l)
 
 	ctx, cancel := context.WithCancel(context.Background())
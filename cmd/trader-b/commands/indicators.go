@@ -0,0 +1,233 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+	"github.com/twk/trader-b/internal/indicators"
+)
+
+// Periods of the indicators printed by the indicators command.
+const (
+	smaPeriod           = 20
+	emaPeriod           = 20
+	rsiPeriod           = 14
+	macdFastPeriod      = 12
+	macdSlowPeriod      = 26
+	macdSignalPeriod    = 9
+	bollingerPeriod     = 20
+	bollingerWidth      = 2
+	atrPeriod           = 14
+	stochasticPeriod    = 14
+	stochasticSmoothing = 3
+)
+
+// Precision of the printed indicator values. The oscillators bounded by 0 and 100 are printed with indicatorPlaces decimal places,
+// and the indicators in the unit of the price with indicatorDigits significant digits, so that they are not rounded to zero
+// for the low-priced symbols.
+const (
+	indicatorPlaces = 4
+	indicatorDigits = 8
+)
+
+// NewIndicatorsCmd creates a new cobra command for the indicators command
+func NewIndicatorsCmd(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "interval", Shorthand: "i", Description: "The interval of the candles, e.g. 1m, 1h or 1d", DefaultValue: "1h"}, MapKey: "indicators.interval"},
+		{Flag: config.FlagDetail{Name: "limit", Description: "The number of closed candles the indicators are computed over, at most 1000", DefaultValue: 200}, MapKey: "indicators.limit"},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "indicators <symbol>",
+		Short: "Show the latest technical indicators of a symbol",
+		Long: `The 'indicators' command gets the last closed candles of a symbol from Binance and shows the latest values of the SMA, EMA,
+RSI, MACD, Bollinger Bands, ATR, VWAP and Stochastic indicators. The VWAP is anchored at the first candle, and an indicator
+needing more candles than were got is shown as '-'.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return indicatorsRun(cmd.Context(), cmd.OutOrStdout(), v, l, args[0])
+		},
+	}
+
+	if err := v.SetFlagAndBind(cmd, b); err != nil {
+		return nil
+	}
+
+	return cmd
+}
+
+func indicatorsRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger, symbol string) error {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info("running indicators command", zap.Any("config", cfg))
+
+	ic := cfg.Indicators
+	if ic.Limit < 1 || ic.Limit > binance.KlinesPageLimit {
+		return fmt.Errorf("the limit must be in [1, %d], got %d", binance.KlinesPageLimit, ic.Limit)
+	}
+
+	s, err := binance.NewBinanceService(cfg)
+	if err != nil {
+		return fmt.Errorf("error creating binance service: %w", err)
+	}
+
+	symbol = strings.ToUpper(symbol)
+
+	// The last kline is still open, one more is requested so that limit closed klines are left.
+	candles, err := s.GetKlines(ctx, symbol, ic.Interval, time.Time{}, time.Time{}, min(ic.Limit+1, binance.KlinesPageLimit))
+	if err != nil {
+		return fmt.Errorf("error getting candles: %w", err)
+	}
+
+	candles = closedCandles(candles, time.Now(), ic.Limit)
+	if len(candles) == 0 {
+		return errors.New("no closed candles found")
+	}
+
+	rows, err := latestIndicators(candles)
+	if err != nil {
+		return err
+	}
+
+	return printIndicators(w, candles, rows)
+}
+
+// closedCandles returns at most the last limit candles closed at now.
+func closedCandles(candles []connector.Candle, now time.Time, limit int) []connector.Candle {
+	for len(candles) > 0 && !candles[len(candles)-1].Closed(now) {
+		candles = candles[:len(candles)-1]
+	}
+
+	if len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+
+	return candles
+}
+
+// indicatorRow is the name and the formatted latest value of an indicator.
+type indicatorRow struct {
+	name  string
+	value string
+}
+
+func latestIndicators(candles []connector.Candle) ([]indicatorRow, error) {
+	closes, bars := indicators.Closes(candles), indicators.Bars(candles)
+
+	sma, err := indicators.SMASeries(closes, smaPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("error computing SMA: %w", err)
+	}
+
+	ema, err := indicators.EMASeries(closes, emaPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("error computing EMA: %w", err)
+	}
+
+	rsi, err := indicators.RSISeries(closes, rsiPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("error computing RSI: %w", err)
+	}
+
+	macd, err := indicators.MACDSeries(closes, macdFastPeriod, macdSlowPeriod, macdSignalPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("error computing MACD: %w", err)
+	}
+
+	bands, err := indicators.BollingerSeries(closes, bollingerPeriod, bollingerWidth)
+	if err != nil {
+		return nil, fmt.Errorf("error computing Bollinger Bands: %w", err)
+	}
+
+	atr, err := indicators.ATRSeries(bars, atrPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("error computing ATR: %w", err)
+	}
+
+	stoch, err := indicators.StochasticSeries(bars, stochasticPeriod, stochasticSmoothing)
+	if err != nil {
+		return nil, fmt.Errorf("error computing Stochastic: %w", err)
+	}
+
+	vwap := indicators.VWAPSeries(bars)
+	m, b, s := last(macd), last(bands), last(stoch)
+
+	return []indicatorRow{
+		{name: fmt.Sprintf("SMA(%d)", smaPeriod), value: priceValue(last(sma))},
+		{name: fmt.Sprintf("EMA(%d)", emaPeriod), value: priceValue(last(ema))},
+		{name: fmt.Sprintf("RSI(%d)", rsiPeriod), value: indicatorValue(last(rsi))},
+		{name: fmt.Sprintf("MACD(%d,%d,%d)", macdFastPeriod, macdSlowPeriod, macdSignalPeriod), value: priceValue(m.MACD)},
+		{name: "MACD signal", value: priceValue(m.Signal)},
+		{name: "MACD histogram", value: priceValue(m.Histogram)},
+		{name: fmt.Sprintf("Bollinger(%d,%d) upper", bollingerPeriod, bollingerWidth), value: priceValue(b.Upper)},
+		{name: fmt.Sprintf("Bollinger(%d,%d) middle", bollingerPeriod, bollingerWidth), value: priceValue(b.Middle)},
+		{name: fmt.Sprintf("Bollinger(%d,%d) lower", bollingerPeriod, bollingerWidth), value: priceValue(b.Lower)},
+		{name: fmt.Sprintf("ATR(%d)", atrPeriod), value: priceValue(last(atr))},
+		{name: "VWAP", value: priceValue(last(vwap))},
+		{name: fmt.Sprintf("Stochastic(%d,%d) %%K", stochasticPeriod, stochasticSmoothing), value: indicatorValue(s.K)},
+		{name: fmt.Sprintf("Stochastic(%d,%d) %%D", stochasticPeriod, stochasticSmoothing), value: indicatorValue(s.D)},
+	}, nil
+}
+
+// last returns the last value of a series, which is never empty.
+func last[T any](series []T) T {
+	return series[len(series)-1]
+}
+
+// indicatorValue formats the value of an oscillator with indicatorPlaces decimal places.
+func indicatorValue(v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+
+	return strconv.FormatFloat(v, 'f', indicatorPlaces, 64)
+}
+
+// priceValue formats the value of an indicator in the unit of the price with indicatorDigits significant digits, without an exponent.
+func priceValue(v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'g', indicatorDigits, 64), 64)
+
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+func printIndicators(w io.Writer, candles []connector.Candle, rows []indicatorRow) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	c := last(candles)
+
+	fmt.Fprintf(tw, "Symbol\t%s\n", c.Symbol)
+	fmt.Fprintf(tw, "Interval\t%s\n", c.Interval)
+	fmt.Fprintf(tw, "Candles\t%d (%s - %s)\n", len(candles), candles[0].OpenTime.Format(time.RFC3339), c.CloseTime.Format(time.RFC3339))
+	fmt.Fprintf(tw, "Close\t%s\n", c.Close)
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "INDICATOR\tVALUE")
+
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s\n", r.name, r.value)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error writing indicators: %w", err)
+	}
+
+	return nil
+}
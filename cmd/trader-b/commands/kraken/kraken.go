@@ -0,0 +1,78 @@
+// Package kraken provides the Kraken command for the application. It contains the NewKrakenCommand function and the krakenRun function.
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/exchanges"
+	"github.com/twk/trader-b/internal/connector/kraken"
+)
+
+// NewKrakenCommand creates a new Kraken command.
+func NewKrakenCommand(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "kraken-api-key", Description: "The API key for the Kraken API", DefaultValue: ""}, MapKey: "connector.kraken.api_key", EnvName: "KRAKEN_API_KEY"},
+		{Flag: config.FlagDetail{Name: "kraken-api-secret", Description: "The base64 encoded private key for the Kraken API", DefaultValue: ""}, MapKey: "connector.kraken.secret_key", EnvName: "KRAKEN_API_SECRET"},
+		{Flag: config.FlagDetail{Name: "kraken-base-url", Description: "Overrides the REST base URL of the Kraken API", DefaultValue: ""}, MapKey: "connector.kraken.base_url", EnvName: "KRAKEN_BASE_URL"},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "kraken",
+		Short: "Interact with the Kraken API",
+		Long:  `The 'kraken' command fetches the balances from the Kraken API and prints the non-zero balances.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return krakenRun(cmd.Context(), cmd.OutOrStdout(), v, l)
+		},
+	}
+
+	if err := v.SetFlagAndBind(cmd, b); err != nil {
+		return nil
+	}
+
+	return cmd
+}
+
+func krakenRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger) error {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info("running kraken command", zap.Any("config", cfg))
+
+	if err := cfg.Connector.Kraken.ValidateCredentials(); err != nil {
+		return fmt.Errorf("error validating kraken config: %w", err)
+	}
+
+	e, err := exchanges.NewRegistry().Get(cfg, kraken.Name)
+	if err != nil {
+		return fmt.Errorf("error creating kraken exchange: %w", err)
+	}
+
+	balances, err := e.GetBalances(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting kraken balances: %w", err)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ASSET\tFREE\tLOCKED")
+
+	for _, b := range connector.NonZeroBalances(balances) {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", b.Asset, b.Free, b.Locked)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error writing balances: %w", err)
+	}
+
+	return nil
+}
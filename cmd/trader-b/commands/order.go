@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	binancecmd "github.com/twk/trader-b/cmd/trader-b/commands/binance"
+	"github.com/twk/trader-b/cmd/trader-b/commands/flags"
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+	"github.com/twk/trader-b/internal/connector/paper"
+	"github.com/twk/trader-b/internal/risk"
+)
+
+// NewOrderCmd creates a new cobra command for the order command. Its flags share the configuration keys of the binance order
+// command, so they are only bound when one of its subcommands runs.
+func NewOrderCmd(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "exchange", Shorthand: "e", Description: "The exchange of the orders, e.g. paper", DefaultValue: paper.Name}, MapKey: "order.exchange"},
+		{Flag: config.FlagDetail{Name: "symbol", Description: "The symbol of the order, e.g. BTCUSDT", DefaultValue: ""}, MapKey: "order.symbol"},
+		{Flag: config.FlagDetail{Name: "order-id", Description: "The ID of the order to cancel or query", DefaultValue: 0}, MapKey: "order.id"},
+		{Flag: config.FlagDetail{Name: "side", Description: "The side of the order, BUY or SELL", DefaultValue: ""}, MapKey: "order.side"},
+		{Flag: config.FlagDetail{Name: "type", Description: "The type of the order, LIMIT or MARKET", DefaultValue: string(connector.OrderTypeLimit)}, MapKey: "order.type"},
+		{Flag: config.FlagDetail{Name: "quantity", Description: "The quantity of the order in the base asset", DefaultValue: ""}, MapKey: "order.quantity"},
+		{Flag: config.FlagDetail{Name: "price", Description: "The limit price of the order, ignored for market orders", DefaultValue: ""}, MapKey: "order.price"},
+		{Flag: config.FlagDetail{Name: "time-in-force", Description: "The time in force of limit orders, GTC, IOC or FOK", DefaultValue: ""}, MapKey: "order.time_in_force"},
+	}
+	b = append(b, flags.DryRun()...)
+	b = append(b, flags.Paper()...)
+	b = append(b, flags.Risk()...)
+
+	cmd := &cobra.Command{
+		Use:   "order",
+		Short: "Place, cancel and query the orders of an exchange",
+		Long: `The 'order' command places, cancels and queries the orders of the exchange given by --exchange.
+With --exchange paper, the orders are simulated with the virtual balances of the paper exchange. With --exchange binance,
+the price and quantity of the orders are rounded to the filters of the symbol, as the binance order command does,
+and --dry-run validates them with the Binance test order endpoint instead of placing them.`,
+	}
+
+	cmd.PersistentPreRunE = func(_ *cobra.Command, _ []string) error {
+		if err := v.Binds(cmd, b); err != nil {
+			return fmt.Errorf("error binding order flags: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := v.SetFlags(cmd, b); err != nil {
+		return nil
+	}
+
+	cmd.AddCommand(newTraderCommand(v, l, "place", "Place a new order", func(ctx context.Context, t connector.Trader, e risk.Exchange, o config.Order) ([]connector.Order, error) {
+		req, err := connector.NewOrderRequest(o)
+		if err != nil {
+			return nil, fmt.Errorf("error creating order request: %w", err)
+		}
+
+		if n, ok := e.(*binance.NormalizedService); ok {
+			order, err := binancecmd.PlaceOrder(ctx, l, t, n, req)
+			if err != nil {
+				return nil, err
+			}
+
+			return []connector.Order{*order}, nil
+		}
+
+		order, err := t.PlaceOrder(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("error placing order: %w", err)
+		}
+
+		return []connector.Order{*order}, nil
+	}))
+	cmd.AddCommand(newTraderCommand(v, l, "cancel", "Cancel an open order", func(ctx context.Context, t connector.Trader, _ risk.Exchange, o config.Order) ([]connector.Order, error) {
+		order, err := t.CancelOrder(ctx, o.Symbol, o.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error cancelling order: %w", err)
+		}
+
+		return []connector.Order{*order}, nil
+	}))
+	cmd.AddCommand(newTraderCommand(v, l, "status", "Show the status of an order", func(ctx context.Context, t connector.Trader, _ risk.Exchange, o config.Order) ([]connector.Order, error) {
+		order, err := t.GetOrder(ctx, o.Symbol, o.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting order: %w", err)
+		}
+
+		return []connector.Order{*order}, nil
+	}))
+	cmd.AddCommand(newTraderCommand(v, l, "open", "List the open orders of the symbol, or of every symbol when none is given",
+		func(ctx context.Context, t connector.Trader, _ risk.Exchange, o config.Order) ([]connector.Order, error) {
+			orders, err := t.ListOpenOrders(ctx, o.Symbol)
+			if err != nil {
+				return nil, fmt.Errorf("error listing open orders: %w", err)
+			}
+
+			return orders, nil
+		}))
+
+	return cmd
+}
+
+func newTraderCommand(v *config.Viper, l *zap.Logger, use, short string, f traderFunc) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return orderRun(cmd.Context(), cmd.OutOrStdout(), v, l, f)
+		},
+	}
+}
+
+// traderFunc runs an order command with the trader checking the orders with the risk engine, and the exchange it places them on.
+type traderFunc func(ctx context.Context, t connector.Trader, e risk.Exchange, o config.Order) ([]connector.Order, error)
+
+func orderRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger, f traderFunc) error {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info("running order command", zap.Any("config", cfg))
+
+	t, err := newTradingExchange(ctx, l, cfg, cfg.Order.Exchange)
+	if err != nil {
+		return err
+	}
+
+	g, err := risk.NewFromConfig(cfg, t, risk.WithLogger(l))
+	if err != nil {
+		return fmt.Errorf("error creating risk engine: %w", err)
+	}
+
+	cfg.Order.Symbol = strings.ToUpper(cfg.Order.Symbol)
+
+	orders, err := f(ctx, g, t, cfg.Order)
+	if err != nil {
+		return err
+	}
+
+	return binancecmd.PrintOrders(w, orders)
+}
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector/exchanges"
+	"github.com/twk/trader-b/internal/portfolio"
+)
+
+// Decimal places of the printed prices, values and allocations.
+const (
+	pricePlaces      = 8
+	valuePlaces      = 2
+	allocationPlaces = 2
+)
+
+// NewPortfolioCmd creates a new cobra command for the portfolio command
+func NewPortfolioCmd(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "quote", Shorthand: "q", Description: "The asset the holdings are valued in", DefaultValue: "USDT"}, MapKey: "portfolio.quote"},
+		{Flag: config.FlagDetail{Name: "exchanges", Description: "Comma separated exchanges to include, e.g. binance,kraken; binance when empty", DefaultValue: ""}, MapKey: "connector.exchanges", EnvName: "TRADER_B_EXCHANGES"},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "portfolio",
+		Short: "Show the holdings of every configured exchange",
+		Long: `The 'portfolio' command collects the balances of every enabled exchange, values them in the quote asset using the ticker prices
+of the exchanges, and shows the holdings per exchange and per asset with their allocation of the total.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return portfolioRun(cmd.Context(), cmd.OutOrStdout(), v, l)
+		},
+	}
+
+	if err := v.SetFlagAndBind(cmd, b); err != nil {
+		return nil
+	}
+
+	return cmd
+}
+
+func portfolioRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger) error {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info("running portfolio command", zap.Any("config", cfg))
+
+	e, err := exchanges.NewRegistry().Exchanges(cfg)
+	if err != nil {
+		return fmt.Errorf("error creating exchanges: %w", err)
+	}
+
+	p, err := portfolio.NewService(e...).Build(ctx, cfg.Portfolio.Quote)
+	if err != nil {
+		return fmt.Errorf("error building portfolio: %w", err)
+	}
+
+	return printPortfolio(w, p)
+}
+
+func printPortfolio(w io.Writer, p *portfolio.Portfolio) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "EXCHANGE\tASSET\tAMOUNT\tPRICE (%s)\tVALUE (%s)\n", p.Quote, p.Quote)
+
+	for _, h := range p.Holdings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", h.Exchange, h.Asset, h.Amount, price(h.Price, h.Priced), value(h.Value, h.Priced))
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "EXCHANGE\tVALUE (%s)\tALLOCATION\n", p.Quote)
+
+	for _, e := range p.Exchanges {
+		fmt.Fprintf(tw, "%s\t%s\t%s%%\n", e.Exchange, e.Value.StringFixed(valuePlaces), e.Allocation.StringFixed(allocationPlaces))
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "ASSET\tAMOUNT\tVALUE (%s)\tALLOCATION\n", p.Quote)
+
+	for _, a := range p.Assets {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s%%\n", a.Asset, a.Amount, value(a.Value, a.Priced), a.Allocation.StringFixed(allocationPlaces))
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "TOTAL\t%s %s\n", p.Total.StringFixed(valuePlaces), p.Quote)
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error writing portfolio: %w", err)
+	}
+
+	if unpriced := p.Unpriced(); len(unpriced) > 0 {
+		fmt.Fprintf(w, "\nno %s price found for %s, these assets are left out of the total\n", p.Quote, strings.Join(unpriced, ", "))
+	}
+
+	return nil
+}
+
+func price(d decimal.Decimal, priced bool) string {
+	if !priced {
+		return "-"
+	}
+
+	return d.Round(pricePlaces).String()
+}
+
+func value(d decimal.Decimal, priced bool) string {
+	if !priced {
+		return "-"
+	}
+
+	return d.StringFixed(valuePlaces)
+}
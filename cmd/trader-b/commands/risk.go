@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/cmd/trader-b/commands/flags"
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/risk"
+)
+
+// NewRiskCmd creates a new cobra command for the risk command. Its flags share the configuration keys of the order commands,
+// so they are only bound when one of its subcommands runs.
+func NewRiskCmd(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := flags.Risk()
+
+	cmd := &cobra.Command{
+		Use:   "risk",
+		Short: "Manage the kill switch and show the limits of the risk checks",
+		Long: `The 'risk' command manages the kill switch blocking every new order, and shows the limits the orders are checked against
+before they are placed. The limits are set in the risk section of the configuration file. The kill switch is the file given by
+--kill-switch: the running strategies stop placing orders as soon as it exists.`,
+	}
+
+	cmd.PersistentPreRunE = func(_ *cobra.Command, _ []string) error {
+		if err := v.Binds(cmd, b); err != nil {
+			return fmt.Errorf("error binding risk flags: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := v.SetFlags(cmd, b); err != nil {
+		return nil
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "kill [reason]",
+		Short: "Engage the kill switch, blocking every new order",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return riskRun(cmd.OutOrStdout(), v, l, func(w io.Writer, cfg *config.Config) error {
+				k := risk.NewKillSwitch(cfg.Risk.KillSwitchPath)
+				if err := k.Engage(strings.Join(args, " "), time.Now()); err != nil {
+					return err
+				}
+
+				l.Warn("kill switch engaged, every new order is blocked", zap.String("path", k.Path()))
+				fmt.Fprintf(w, "kill switch engaged: %s\n", k.Path())
+
+				return nil
+			})
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "resume",
+		Short: "Release the kill switch, allowing new orders again",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return riskRun(cmd.OutOrStdout(), v, l, func(w io.Writer, cfg *config.Config) error {
+				k := risk.NewKillSwitch(cfg.Risk.KillSwitchPath)
+				if err := k.Release(); err != nil {
+					return err
+				}
+
+				fmt.Fprintf(w, "kill switch released: %s\n", k.Path())
+
+				return nil
+			})
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show the kill switch and the limits of the risk checks",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return riskRun(cmd.OutOrStdout(), v, l, printRiskStatus)
+		},
+	})
+
+	return cmd
+}
+
+func riskRun(w io.Writer, v *config.Viper, l *zap.Logger, f func(w io.Writer, cfg *config.Config) error) error {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info("running risk command", zap.Any("config", cfg))
+
+	return f(w, cfg)
+}
+
+func printRiskStatus(w io.Writer, cfg *config.Config) error {
+	limits, err := risk.NewLimits(cfg.Risk)
+	if err != nil {
+		return fmt.Errorf("error validating risk config: %w", err)
+	}
+
+	k := risk.NewKillSwitch(cfg.Risk.KillSwitchPath)
+
+	engaged, reason, err := k.Engaged()
+	if err != nil {
+		return err
+	}
+
+	status := "released"
+	if engaged {
+		status = "ENGAGED " + reason
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "KILL SWITCH\t%s (%s)\n", status, k.Path())
+	fmt.Fprintf(tw, "ALLOWED SYMBOLS\t%s\n", allowedSymbols(limits.AllowedSymbols))
+	fmt.Fprintf(tw, "MAX ORDER NOTIONAL\t%s\n", limit(limits.MaxOrderNotional.String()+" "+limits.Quote, limits.MaxOrderNotional.IsPositive()))
+	fmt.Fprintf(tw, "MAX POSITIONS\t%s\n", maxPositions(limits.MaxPositions))
+	fmt.Fprintf(tw, "MAX OPEN ORDERS\t%s\n", limit(fmt.Sprint(limits.MaxOpenOrders), limits.MaxOpenOrders > 0))
+	fmt.Fprintf(tw, "DAILY LOSS LIMIT\t%s\n", limit(limits.DailyLossLimit.String()+" "+limits.Quote, limits.DailyLossLimit.IsPositive()))
+	fmt.Fprintf(tw, "PRICE COLLAR\t%s\n", limit(limits.PriceCollar.Mul(decimal.NewFromInt(percent)).String()+"%", limits.PriceCollar.IsPositive()))
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error writing risk status: %w", err)
+	}
+
+	return nil
+}
+
+func allowedSymbols(symbols map[string]bool) string {
+	if len(symbols) == 0 {
+		return "any"
+	}
+
+	res := make([]string, 0, len(symbols))
+	for s := range symbols {
+		res = append(res, s)
+	}
+
+	sort.Strings(res)
+
+	return strings.Join(res, ", ")
+}
+
+func maxPositions(positions map[string]decimal.Decimal) string {
+	if len(positions) == 0 {
+		return "-"
+	}
+
+	res := make([]string, 0, len(positions))
+	for asset, position := range positions {
+		res = append(res, asset+"="+position.String())
+	}
+
+	sort.Strings(res)
+
+	return strings.Join(res, ", ")
+}
+
+func limit(s string, enabled bool) string {
+	if !enabled {
+		return "-"
+	}
+
+	return s
+}
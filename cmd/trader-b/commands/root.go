@@ -3,11 +3,14 @@ package commands
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
 	"github.com/twk/trader-b/cmd/trader-b/commands/binance"
+	"github.com/twk/trader-b/cmd/trader-b/commands/data"
+	"github.com/twk/trader-b/cmd/trader-b/commands/kraken"
 	"github.com/twk/trader-b/internal/config"
 )
 
@@ -21,6 +24,10 @@ func NewRootCommand(logger *zap.Logger) (*cobra.Command, error) {
 		{Flag: config.FlagDetail{Name: "config", Description: fmt.Sprintf("Specifies the path to the configuration file for %s.", appName), DefaultValue: "./config.yaml"}, MapKey: "config_path"},
 		{Flag: config.FlagDetail{Name: "log-level", Description: "Determines the logging verbosity level for the application. Available options are 'debug', 'info', 'warn', and 'error'.", DefaultValue: ""}, EnvName: "LOG_LEVEL", MapKey: "log_level"},
 		{Flag: config.FlagDetail{Name: "stacktrace", Description: "Enables or disables the inclusion of stack traces in the log output.", DefaultValue: false}, EnvName: "STACKTRACE", MapKey: "stacktrace"},
+		{Flag: config.FlagDetail{Name: "retry-max-attempts", Description: "The maximum number of attempts of an HTTP request failing with a network error, a 5xx or a 429 response. 1 disables the retries.", DefaultValue: 3}, MapKey: "retry.max_attempts"},
+		{Flag: config.FlagDetail{Name: "retry-initial-interval", Description: "The backoff before the first retry of an HTTP request, doubled after every attempt.", DefaultValue: 250 * time.Millisecond}, MapKey: "retry.initial_interval"},
+		{Flag: config.FlagDetail{Name: "retry-max-interval", Description: "The maximum backoff between the attempts of an HTTP request.", DefaultValue: 5 * time.Second}, MapKey: "retry.max_interval"},
+		{Flag: config.FlagDetail{Name: "retry-max-elapsed-time", Description: "The time after the first attempt of an HTTP request after which it is not retried anymore.", DefaultValue: 30 * time.Second}, MapKey: "retry.max_elapsed_time"},
 	}
 
 	rootCmd := &cobra.Command{
@@ -40,6 +47,14 @@ This CLI is used to interact with the trader-b application.`,
 
 	rootCmd.AddCommand(NewGetCmd(v, logger))
 	rootCmd.AddCommand(binance.NewBinanceCommand(v, logger))
+	rootCmd.AddCommand(kraken.NewKrakenCommand(v, logger))
+	rootCmd.AddCommand(data.NewDataCommand(v, logger))
+	rootCmd.AddCommand(NewPortfolioCmd(v, logger))
+	rootCmd.AddCommand(NewOrderCmd(v, logger))
+	rootCmd.AddCommand(NewBacktestCmd(v, logger))
+	rootCmd.AddCommand(NewStrategyCmd(v, logger))
+	rootCmd.AddCommand(NewIndicatorsCmd(v, logger))
+	rootCmd.AddCommand(NewRiskCmd(v, logger))
 
 	return rootCmd, nil
 }
@@ -0,0 +1,206 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/cmd/trader-b/commands/flags"
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector/binance"
+	"github.com/twk/trader-b/internal/connector/paper"
+	"github.com/twk/trader-b/internal/risk"
+	"github.com/twk/trader-b/internal/strategy"
+)
+
+// strategyEventsBufferSize is the number of market events buffered while the strategy handles an event.
+const strategyEventsBufferSize = 256
+
+// NewStrategyCmd creates a new cobra command for the strategy command.
+func NewStrategyCmd(v *config.Viper, l *zap.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "strategy",
+		Short: "List and run the trading strategies",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the registered strategies",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			for _, name := range strategy.NewRegistry().Names() {
+				fmt.Fprintln(cmd.OutOrStdout(), name)
+			}
+
+			return nil
+		},
+	})
+	cmd.AddCommand(newStrategyRunCmd(v, l))
+
+	return cmd
+}
+
+func newStrategyRunCmd(v *config.Viper, l *zap.Logger) *cobra.Command {
+	b := []config.BindDetail{
+		{Flag: config.FlagDetail{Name: "strategy", Shorthand: "s", Description: "The strategy to run, e.g. sma", DefaultValue: strategy.SMACrossName}, MapKey: "strategy.name"},
+		{Flag: config.FlagDetail{Name: "params", Shorthand: "p", Description: "The comma separated name=value parameters of the strategy, e.g. fast=10,slow=30", DefaultValue: ""}, MapKey: "strategy.params"},
+		{Flag: config.FlagDetail{Name: "exchange", Shorthand: "e", Description: "The exchange the strategy trades on, e.g. paper", DefaultValue: paper.Name}, MapKey: "strategy.exchange"},
+		{Flag: config.FlagDetail{Name: "symbol", Description: "The symbol the strategy trades, e.g. BTCUSDT", DefaultValue: ""}, MapKey: "strategy.symbol"},
+		{Flag: config.FlagDetail{Name: "interval", Shorthand: "i", Description: "The interval of the candles fed to the strategy, e.g. 1m", DefaultValue: "1m"}, MapKey: "strategy.interval"},
+		{Flag: config.FlagDetail{Name: "quote", Shorthand: "q", Description: "The quote asset of the symbol", DefaultValue: "USDT"}, MapKey: "strategy.quote"},
+		{Flag: config.FlagDetail{Name: "timer", Description: "The interval at which the orders of the strategy are polled and its timer is called", DefaultValue: 10 * time.Second}, MapKey: "strategy.timer"},
+	}
+	b = append(b, flags.DryRun()...)
+	b = append(b, flags.Paper()...)
+	b = append(b, flags.Risk()...)
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run a strategy on the live market",
+		Long: `The 'run' command feeds a strategy with the closed candles and the trades of a symbol from the Binance streams until it is interrupted.
+The strategy trades on the exchange given by --exchange: the orders are simulated with --exchange paper, and live on binance,
+where their price and quantity are rounded to the filters of the symbol, or only validated by Binance with --dry-run.
+Every order is checked by the risk engine first, and the strategy stops at the first order it rejects.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return strategyRun(cmd.Context(), cmd.OutOrStdout(), v, l)
+		},
+	}
+
+	cmd.PersistentPreRunE = func(_ *cobra.Command, _ []string) error {
+		if err := v.Binds(cmd, b); err != nil {
+			return fmt.Errorf("error binding strategy run flags: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := v.SetFlags(cmd, b); err != nil {
+		return nil
+	}
+
+	return cmd
+}
+
+func strategyRun(ctx context.Context, w io.Writer, v *config.Viper, l *zap.Logger) error {
+	cfg, err := v.BuildConfig()
+	if err != nil {
+		return fmt.Errorf("error building config: %w", err)
+	}
+
+	l.Info("running strategy run command", zap.Any("config", cfg))
+
+	sc := cfg.Strategy
+	sc.Symbol, sc.Quote = strings.ToUpper(sc.Symbol), strings.ToUpper(sc.Quote)
+
+	s, err := newRegisteredStrategy(strings.ToLower(sc.Name), sc.Params, sc.Symbol, sc.Quote)
+	if err != nil {
+		return err
+	}
+
+	t, err := newTradingExchange(ctx, l, cfg, sc.Exchange)
+	if err != nil {
+		return err
+	}
+
+	a, err := risk.NewFromConfig(cfg, t, risk.WithLogger(l))
+	if err != nil {
+		return fmt.Errorf("error creating risk engine: %w", err)
+	}
+
+	endpoints, err := binance.ResolveEndpoints(cfg.Connector.Binance)
+	if err != nil {
+		return fmt.Errorf("error validating binance config: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(w, "running %s on %s %s, trading on %s\n", sc.Name, sc.Symbol, sc.Interval, t.Name())
+
+	events := make(chan strategy.Event, strategyEventsBufferSize)
+	errc := make(chan error, 1)
+	streamer := binance.NewStreamer(endpoints.WSBaseURL, binance.WithStreamLogger(l))
+	streams := []binance.Stream{
+		{Symbol: sc.Symbol, Type: binance.StreamKline, Interval: sc.Interval},
+		{Symbol: sc.Symbol, Type: binance.StreamTrade},
+	}
+
+	go func() {
+		defer close(events)
+
+		errc <- streamer.Run(ctx, streams, func(event binance.StreamEvent) error {
+			return sendStrategyEvent(ctx, events, event)
+		})
+	}()
+
+	runner := strategy.NewRunner(s, a, strategy.WithTimer(sc.Timer), strategy.WithRunnerLogger(l))
+
+	err = runner.Run(ctx, events)
+	stop()
+
+	if streamErr := <-errc; err == nil {
+		err = streamErr
+	}
+
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("error running strategy %s: %w", sc.Name, err)
+	}
+
+	return nil
+}
+
+// sendStrategyEvent sends the closed candles and the trades of the stream to the strategy.
+func sendStrategyEvent(ctx context.Context, events chan<- strategy.Event, event binance.StreamEvent) error {
+	var e strategy.Event
+
+	switch {
+	case event.Kline != nil && event.Kline.Closed:
+		e.Candle = &event.Kline.Candle
+	case event.Trade != nil:
+		e.Trade = event.Trade
+	default:
+		return nil
+	}
+
+	select {
+	case events <- e:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newRegisteredStrategy creates the registered strategy trading the symbol quoted in the quote asset.
+func newRegisteredStrategy(name, params, symbol, quote string) (strategy.Strategy, error) {
+	if symbol == "" {
+		return nil, errors.New("a symbol is required")
+	}
+
+	if !strings.HasSuffix(symbol, quote) || symbol == quote {
+		return nil, fmt.Errorf("the symbol %s is not quoted in %s", symbol, quote)
+	}
+
+	values, err := strategy.ParseParams(params)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing strategy parameters: %w", err)
+	}
+
+	s, err := strategy.NewRegistry().Get(name, strategy.Params{
+		Symbol: symbol, Base: strings.TrimSuffix(symbol, quote), Quote: quote, Values: values,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating strategy: %w", err)
+	}
+
+	return s, nil
+}
@@ -3,19 +3,21 @@ module github.com/twk/trader-b
 go 1.22.1
 
 require (
+	github.com/binance/binance-connector-go v0.5.2
 	github.com/golang/mock v1.6.0
+	github.com/gorilla/websocket v1.5.0
+	github.com/shopspring/decimal v1.4.0
 	github.com/spf13/cobra v1.8.0
 	github.com/spf13/viper v1.18.2
 	github.com/stretchr/testify v1.9.0
+	go.etcd.io/bbolt v1.3.10
 	go.uber.org/zap v1.27.0
 )
 
 require (
-	github.com/binance/binance-connector-go v0.5.2 // indirect
 	github.com/bitly/go-simplejson v0.5.0 // indirect
 	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
 	github.com/fsnotify/fsnotify v1.7.0 // indirect
-	github.com/gorilla/websocket v1.5.0 // indirect
 	github.com/hashicorp/hcl v1.0.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/magiconair/properties v1.8.7 // indirect
@@ -0,0 +1,138 @@
+// Package account provides an in-memory state of an exchange account, its balances and open orders.
+// The state is loaded once over REST and then kept current by the updates of a user data stream,
+// and every method is safe for concurrent use so that it can be read while the stream updates it.
+package account
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// orderKey identifies an order. Order IDs are only unique per symbol.
+type orderKey struct {
+	symbol string
+	id     int64
+}
+
+// State is the state of an account.
+type State struct {
+	mu       sync.RWMutex
+	balances map[string]connector.Balance
+	orders   map[orderKey]connector.Order
+	updated  time.Time
+}
+
+// New creates a state holding the balances and open orders.
+func New(balances []connector.Balance, openOrders []connector.Order) *State {
+	s := &State{
+		balances: make(map[string]connector.Balance, len(balances)),
+		orders:   make(map[orderKey]connector.Order, len(openOrders)),
+	}
+
+	for _, b := range balances {
+		s.balances[b.Asset] = b
+	}
+
+	for _, o := range openOrders {
+		s.orders[orderKey{symbol: o.Symbol, id: o.ID}] = o
+	}
+
+	return s
+}
+
+// UpdateBalances replaces the balances of the given assets, leaving the other assets unchanged.
+func (s *State) UpdateBalances(at time.Time, balances []connector.Balance) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, b := range balances {
+		s.balances[b.Asset] = b
+	}
+
+	s.touch(at)
+}
+
+// UpsertOrder adds the open order or replaces it.
+func (s *State) UpsertOrder(at time.Time, o connector.Order) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.orders[orderKey{symbol: o.Symbol, id: o.ID}] = o
+	s.touch(at)
+}
+
+// RemoveOrder removes an order that is no longer open. Removing an unknown order only records the update time.
+func (s *State) RemoveOrder(at time.Time, symbol string, orderID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.orders, orderKey{symbol: symbol, id: orderID})
+	s.touch(at)
+}
+
+// Balance returns the balance of the asset. It returns false when the account has no balance of the asset.
+func (s *State) Balance(asset string) (connector.Balance, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.balances[asset]
+
+	return b, ok
+}
+
+// Balances returns the balances sorted by asset.
+func (s *State) Balances() []connector.Balance {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := make([]connector.Balance, 0, len(s.balances))
+	for _, b := range s.balances {
+		res = append(res, b)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Asset < res[j].Asset })
+
+	return res
+}
+
+// OpenOrders returns the open orders of the symbol, or of every symbol when it is empty, sorted by symbol and ID.
+func (s *State) OpenOrders(symbol string) []connector.Order {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := make([]connector.Order, 0, len(s.orders))
+
+	for k, o := range s.orders {
+		if symbol == "" || k.symbol == symbol {
+			res = append(res, o)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Symbol != res[j].Symbol {
+			return res[i].Symbol < res[j].Symbol
+		}
+
+		return res[i].ID < res[j].ID
+	})
+
+	return res
+}
+
+// Updated returns the time of the last update applied to the state, or the zero time when none was applied.
+func (s *State) Updated() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.updated
+}
+
+// touch records the time of an update. Updates received out of order do not move the time back.
+func (s *State) touch(at time.Time) {
+	if at.After(s.updated) {
+		s.updated = at
+	}
+}
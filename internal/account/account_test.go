@@ -0,0 +1,65 @@
+package account_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/account"
+	"github.com/twk/trader-b/internal/connector"
+)
+
+func balance(asset, free, locked string) connector.Balance {
+	return connector.Balance{Asset: asset, Free: decimal.RequireFromString(free), Locked: decimal.RequireFromString(locked)}
+}
+
+func TestState_Balances(t *testing.T) {
+	t.Parallel()
+
+	s := account.New([]connector.Balance{balance("USDT", "1000", "0"), balance("BTC", "1", "0")}, nil)
+	assert.True(t, s.Updated().IsZero())
+
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.UpdateBalances(at, []connector.Balance{balance("USDT", "900", "100"), balance("ETH", "2", "0")})
+
+	assert.Equal(t, []connector.Balance{balance("BTC", "1", "0"), balance("ETH", "2", "0"), balance("USDT", "900", "100")}, s.Balances())
+
+	b, ok := s.Balance("USDT")
+	assert.True(t, ok)
+	assert.Equal(t, "1000", b.Total().String())
+
+	_, ok = s.Balance("BNB")
+	assert.False(t, ok)
+
+	s.UpdateBalances(at.Add(-time.Minute), nil)
+	assert.Equal(t, at, s.Updated())
+}
+
+func TestState_Orders(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := account.New(nil, []connector.Order{
+		{ID: 2, Symbol: "ETHUSDT", Status: "NEW"},
+		{ID: 3, Symbol: "BTCUSDT", Status: "NEW"},
+	})
+
+	s.UpsertOrder(at, connector.Order{ID: 1, Symbol: "BTCUSDT", Status: "NEW"})
+	s.UpsertOrder(at, connector.Order{ID: 3, Symbol: "BTCUSDT", Status: "PARTIALLY_FILLED"})
+
+	assert.Equal(t, []connector.Order{
+		{ID: 1, Symbol: "BTCUSDT", Status: "NEW"},
+		{ID: 3, Symbol: "BTCUSDT", Status: "PARTIALLY_FILLED"},
+		{ID: 2, Symbol: "ETHUSDT", Status: "NEW"},
+	}, s.OpenOrders(""))
+
+	s.RemoveOrder(at.Add(time.Second), "BTCUSDT", 1)
+	s.RemoveOrder(at.Add(time.Second), "BTCUSDT", 42)
+
+	assert.Equal(t, []connector.Order{{ID: 3, Symbol: "BTCUSDT", Status: "PARTIALLY_FILLED"}}, s.OpenOrders("BTCUSDT"))
+	assert.Empty(t, s.OpenOrders("BNBUSDT"))
+	assert.Equal(t, at.Add(time.Second), s.Updated())
+}
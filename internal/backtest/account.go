@@ -0,0 +1,263 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// quantityPlaces is the number of decimal places a market buy reduced to the free quote balance is rounded down to.
+const quantityPlaces = 8
+
+var _ Account = (*account)(nil)
+
+// account is the simulated account of a backtest. The orders placed on a candle are executed against the following candles:
+// market orders at the open price moved by the slippage, and limit orders at their price once the candle trades through it,
+// or at the open price when the candle opens through it. The fees are charged in the quote asset.
+type account struct {
+	symbol   string
+	base     connector.Balance
+	quote    connector.Balance
+	fees     Fees
+	slippage decimal.Decimal
+	orders   []*connector.Order
+	// locks holds the amount locked by every open order.
+	locks  map[int64]decimal.Decimal
+	trades []Trade
+	// cost is the cost of the base balance in the quote asset, fees included, which sets the profit of the sells.
+	cost decimal.Decimal
+	now  time.Time
+}
+
+func newAccount(symbol, base, quote string, capital decimal.Decimal, fees Fees, slippage decimal.Decimal) *account {
+	return &account{
+		symbol:   symbol,
+		base:     connector.Balance{Asset: base},
+		quote:    connector.Balance{Asset: quote, Free: capital},
+		fees:     fees,
+		slippage: slippage,
+		locks:    make(map[int64]decimal.Decimal),
+	}
+}
+
+// GetBalances gets the balances of the base and quote assets.
+func (a *account) GetBalances(_ context.Context) ([]connector.Balance, error) {
+	return []connector.Balance{a.base, a.quote}, nil
+}
+
+// PlaceOrder places an order executed from the next candle. A limit order locks its amount, fees included for a buy,
+// and a sell locks its quantity.
+func (a *account) PlaceOrder(_ context.Context, req connector.OrderRequest) (*connector.Order, error) {
+	if req.Symbol != a.symbol {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSymbol, req.Symbol)
+	}
+
+	if req.Side != connector.SideBuy && req.Side != connector.SideSell {
+		return nil, fmt.Errorf("%w: invalid side %q", ErrInvalidOrder, req.Side)
+	}
+
+	if !req.Quantity.IsPositive() {
+		return nil, fmt.Errorf("%w: the quantity must be positive", ErrInvalidOrder)
+	}
+
+	o := &connector.Order{
+		ID: int64(len(a.orders) + 1), ClientOrderID: req.ClientOrderID, Symbol: req.Symbol, Side: req.Side, Type: req.Type,
+		Status: connector.OrderStatusNew, Quantity: req.Quantity, Time: a.now,
+	}
+
+	switch req.Type {
+	case connector.OrderTypeMarket:
+	case connector.OrderTypeLimit:
+		if !req.Price.IsPositive() {
+			return nil, fmt.Errorf("%w: the price of a limit order must be positive", ErrInvalidOrder)
+		}
+
+		o.Price = req.Price
+	default:
+		return nil, fmt.Errorf("%w: invalid type %q", ErrInvalidOrder, req.Type)
+	}
+
+	balance, amount := a.lock(o)
+	if balance.Free.LessThan(amount) {
+		return nil, fmt.Errorf("%w: the order requires %s %s, %s is free", ErrInsufficientBalance, amount, balance.Asset, balance.Free)
+	}
+
+	balance.Free = balance.Free.Sub(amount)
+	balance.Locked = balance.Locked.Add(amount)
+	a.locks[o.ID] = amount
+	a.orders = append(a.orders, o)
+
+	res := *o
+
+	return &res, nil
+}
+
+// CancelOrder cancels an open order and unlocks its amount.
+func (a *account) CancelOrder(_ context.Context, symbol string, orderID int64) (*connector.Order, error) {
+	o, err := a.order(symbol, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Status != connector.OrderStatusNew {
+		return nil, fmt.Errorf("%w: order %d is %s", ErrOrderNotOpen, o.ID, o.Status)
+	}
+
+	a.unlock(o)
+	o.Status = connector.OrderStatusCanceled
+
+	res := *o
+
+	return &res, nil
+}
+
+// GetOrder gets an order.
+func (a *account) GetOrder(_ context.Context, symbol string, orderID int64) (*connector.Order, error) {
+	o, err := a.order(symbol, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := *o
+
+	return &res, nil
+}
+
+// ListOpenOrders lists the open orders of the symbol, or of every symbol when it is empty.
+func (a *account) ListOpenOrders(_ context.Context, symbol string) ([]connector.Order, error) {
+	res := make([]connector.Order, 0)
+
+	for _, o := range a.orders {
+		if o.Status == connector.OrderStatusNew && (symbol == "" || symbol == o.Symbol) {
+			res = append(res, *o)
+		}
+	}
+
+	return res, nil
+}
+
+func (a *account) order(symbol string, orderID int64) (*connector.Order, error) {
+	if symbol != a.symbol || orderID < 1 || orderID > int64(len(a.orders)) {
+		return nil, fmt.Errorf("%w: %s %d", ErrUnknownOrder, symbol, orderID)
+	}
+
+	return a.orders[orderID-1], nil
+}
+
+// lock returns the balance and the amount locked by the order. A market buy locks nothing, as its price is not known.
+func (a *account) lock(o *connector.Order) (*connector.Balance, decimal.Decimal) {
+	if o.Side == connector.SideSell {
+		return &a.base, o.Quantity
+	}
+
+	if o.Type == connector.OrderTypeMarket {
+		return &a.quote, decimal.Zero
+	}
+
+	return &a.quote, o.Price.Mul(o.Quantity).Mul(decimal.NewFromInt(1).Add(a.fees.Maker))
+}
+
+func (a *account) unlock(o *connector.Order) {
+	b := &a.quote
+	if o.Side == connector.SideSell {
+		b = &a.base
+	}
+
+	amount := a.locks[o.ID]
+	b.Locked = b.Locked.Sub(amount)
+	b.Free = b.Free.Add(amount)
+
+	delete(a.locks, o.ID)
+}
+
+// execute executes the open orders crossed by the candle, in the order they were placed.
+func (a *account) execute(c connector.Candle) {
+	for _, o := range a.orders {
+		if o.Status != connector.OrderStatusNew {
+			continue
+		}
+
+		if price, ok := a.fillPrice(o, c); ok {
+			a.fill(o, price, c.OpenTime)
+		}
+	}
+}
+
+// fillPrice returns the price the order is executed at during the candle, and false when the candle does not reach its price.
+func (a *account) fillPrice(o *connector.Order, c connector.Candle) (decimal.Decimal, bool) {
+	one := decimal.NewFromInt(1)
+
+	switch {
+	case o.Type == connector.OrderTypeMarket && o.Side == connector.SideBuy:
+		return c.Open.Mul(one.Add(a.slippage)), true
+	case o.Type == connector.OrderTypeMarket:
+		return c.Open.Mul(one.Sub(a.slippage)), true
+	case o.Side == connector.SideBuy && c.Low.LessThanOrEqual(o.Price):
+		return decimal.Min(c.Open, o.Price), true
+	case o.Side == connector.SideSell && c.High.GreaterThanOrEqual(o.Price):
+		return decimal.Max(c.Open, o.Price), true
+	default:
+		return decimal.Zero, false
+	}
+}
+
+// fill executes the order at the price. A market buy larger than the free quote balance is reduced to the quantity it can pay for,
+// and rejected when it cannot pay for any.
+func (a *account) fill(o *connector.Order, price decimal.Decimal, at time.Time) {
+	rate := a.fees.Maker
+	if o.Type == connector.OrderTypeMarket {
+		rate = a.fees.Taker
+	}
+
+	a.unlock(o)
+
+	qty := o.Quantity
+	one := decimal.NewFromInt(1)
+
+	if o.Side == connector.SideBuy && o.Type == connector.OrderTypeMarket {
+		affordable := a.quote.Free.Div(price.Mul(one.Add(rate))).RoundDown(quantityPlaces)
+		if affordable.LessThan(qty) {
+			qty = affordable
+		}
+
+		if !qty.IsPositive() {
+			o.Status = connector.OrderStatusRejected
+			return
+		}
+
+		o.Quantity = qty
+	}
+
+	amount := price.Mul(qty)
+	fee := amount.Mul(rate)
+	t := Trade{Time: at, OrderID: o.ID, Side: o.Side, Type: o.Type, Price: price, Quantity: qty, Fee: fee}
+
+	switch o.Side {
+	case connector.SideBuy:
+		a.quote.Free = a.quote.Free.Sub(amount.Add(fee))
+		a.base.Free = a.base.Free.Add(qty)
+		a.cost = a.cost.Add(amount.Add(fee))
+	case connector.SideSell:
+		cost := a.cost.Mul(qty).Div(a.base.Total())
+		proceeds := amount.Sub(fee)
+
+		a.base.Free = a.base.Free.Sub(qty)
+		a.quote.Free = a.quote.Free.Add(proceeds)
+		a.cost = a.cost.Sub(cost)
+		t.Profit = proceeds.Sub(cost)
+		t.Closing = true
+	}
+
+	o.ExecutedQuantity = qty
+	o.Status = connector.OrderStatusFilled
+	a.trades = append(a.trades, t)
+}
+
+// equity returns the value of the balances in the quote asset at the price.
+func (a *account) equity(price decimal.Decimal) decimal.Decimal {
+	return a.quote.Total().Add(a.base.Total().Mul(price))
+}
@@ -0,0 +1,158 @@
+// Package backtest replays historical candles in time order through a strategy and reports the performance of its simulated trades.
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// defaultCapital is the initial quote balance of a backtest.
+const defaultCapital = 10000
+
+var (
+	// ErrNoCandles is returned when a backtest is run without candles.
+	ErrNoCandles = errors.New("no candles to replay")
+	// ErrUnorderedCandles is returned when the candles are not of one symbol and interval in increasing time order.
+	ErrUnorderedCandles = errors.New("candles are not of one series in time order")
+	// ErrInvalidOrder is returned when an order request is invalid.
+	ErrInvalidOrder = errors.New("invalid order")
+	// ErrInsufficientBalance is returned when the free balance cannot pay for an order.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrUnknownOrder is returned when an order does not exist.
+	ErrUnknownOrder = errors.New("unknown order")
+	// ErrOrderNotOpen is returned when cancelling an order which is not open.
+	ErrOrderNotOpen = errors.New("order is not open")
+	// ErrUnknownSymbol is returned when an order is placed on another symbol than the replayed one.
+	ErrUnknownSymbol = errors.New("unknown symbol")
+)
+
+// Account is the simulated account a strategy trades with.
+type Account interface {
+	connector.Trader
+	GetBalances(ctx context.Context) ([]connector.Balance, error)
+}
+
+// Strategy is a trading strategy replayed by a backtest. OnCandle is called once each candle has closed, and the orders it places
+// are executed from the next candle, so the strategy never trades on prices it could not have seen.
+type Strategy interface {
+	OnCandle(ctx context.Context, c connector.Candle, a Account) error
+}
+
+// Fees are the fees charged on the traded amounts, as fractions of them.
+type Fees struct {
+	Maker decimal.Decimal
+	Taker decimal.Decimal
+}
+
+// Backtest replays candles through a strategy with a simulated account.
+type Backtest struct {
+	capital  decimal.Decimal
+	fees     Fees
+	slippage decimal.Decimal
+	base     string
+	quote    string
+}
+
+// Option configures a Backtest.
+type Option func(*Backtest)
+
+// WithCapital sets the initial quote balance.
+func WithCapital(capital decimal.Decimal) Option {
+	return func(b *Backtest) {
+		b.capital = capital
+	}
+}
+
+// WithFees sets the fees charged on the fills.
+func WithFees(fees Fees) Option {
+	return func(b *Backtest) {
+		b.fees = fees
+	}
+}
+
+// WithSlippage sets the fraction of the price market orders are filled worse than the open price.
+func WithSlippage(slippage decimal.Decimal) Option {
+	return func(b *Backtest) {
+		b.slippage = slippage
+	}
+}
+
+// WithAssets sets the base and quote assets of the replayed symbol. The base asset defaults to the symbol without the quote asset.
+func WithAssets(base, quote string) Option {
+	return func(b *Backtest) {
+		b.base = base
+		b.quote = quote
+	}
+}
+
+// New creates a new Backtest, with a capital of 10000 USDT and no fee or slippage unless configured.
+func New(opts ...Option) *Backtest {
+	b := &Backtest{capital: decimal.NewFromInt(defaultCapital), quote: "USDT"}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// Run replays the candles through the strategy. The open orders are executed against each candle first, then the equity is marked
+// at its close price before the strategy is called.
+func (b *Backtest) Run(ctx context.Context, s Strategy, candles []connector.Candle) (*Report, error) {
+	if len(candles) == 0 {
+		return nil, ErrNoCandles
+	}
+
+	if err := validate(candles); err != nil {
+		return nil, err
+	}
+
+	symbol := candles[0].Symbol
+
+	base := b.base
+	if base == "" {
+		base = strings.TrimSuffix(symbol, b.quote)
+	}
+
+	a := newAccount(symbol, base, b.quote, b.capital, b.fees, b.slippage)
+	equity := make([]EquityPoint, 0, len(candles))
+	held := 0
+
+	for _, c := range candles {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("error running backtest: %w", err)
+		}
+
+		a.execute(c)
+
+		if a.base.Total().IsPositive() {
+			held++
+		}
+
+		equity = append(equity, EquityPoint{Time: c.CloseTime, Equity: a.equity(c.Close)})
+		a.now = c.CloseTime
+
+		if err := s.OnCandle(ctx, c, a); err != nil {
+			return nil, fmt.Errorf("error running strategy on candle opened at %s: %w", c.OpenTime.Format(time.RFC3339), err)
+		}
+	}
+
+	return newReport(candles, b.capital, equity, a.trades, held), nil
+}
+
+func validate(candles []connector.Candle) error {
+	for i := 1; i < len(candles); i++ {
+		prev, c := candles[i-1], candles[i]
+		if c.Symbol != prev.Symbol || c.Interval != prev.Interval || !c.OpenTime.After(prev.OpenTime) {
+			return fmt.Errorf("%w: candle %d opened at %s", ErrUnorderedCandles, i, c.OpenTime.Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
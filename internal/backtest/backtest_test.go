@@ -0,0 +1,296 @@
+package backtest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/backtest"
+	"github.com/twk/trader-b/internal/connector"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func at(hour int) time.Time {
+	return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(hour) * time.Hour)
+}
+
+// candles returns consecutive one hour candles of BTCUSDT from [open, high, low, close] prices.
+func candles(prices ...[4]string) []connector.Candle {
+	res := make([]connector.Candle, 0, len(prices))
+
+	for i, p := range prices {
+		res = append(res, connector.Candle{
+			Symbol: "BTCUSDT", Interval: "1h", OpenTime: at(i), CloseTime: at(i + 1).Add(-time.Millisecond),
+			Open: d(p[0]), High: d(p[1]), Low: d(p[2]), Close: d(p[3]),
+		})
+	}
+
+	return res
+}
+
+// scripted runs the step of every candle, keyed by the index of the candle.
+type scripted struct {
+	steps map[int]func(ctx context.Context, a backtest.Account) error
+	i     int
+}
+
+func (s *scripted) OnCandle(ctx context.Context, _ connector.Candle, a backtest.Account) error {
+	defer func() { s.i++ }()
+
+	if step, ok := s.steps[s.i]; ok {
+		return step(ctx, a)
+	}
+
+	return nil
+}
+
+func place(req connector.OrderRequest) func(ctx context.Context, a backtest.Account) error {
+	return func(ctx context.Context, a backtest.Account) error {
+		_, err := a.PlaceOrder(ctx, req)
+		return err
+	}
+}
+
+func market(side connector.Side, qty string) connector.OrderRequest {
+	return connector.OrderRequest{Symbol: "BTCUSDT", Side: side, Type: connector.OrderTypeMarket, Quantity: d(qty)}
+}
+
+func limit(side connector.Side, qty, price string) connector.OrderRequest {
+	return connector.OrderRequest{Symbol: "BTCUSDT", Side: side, Type: connector.OrderTypeLimit, Quantity: d(qty), Price: d(price)}
+}
+
+func TestBacktest_Run(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		trades      []backtest.Trade
+		finalEquity string
+		winRate     string
+		err         error
+	}
+
+	tests := map[string]struct {
+		opts    []backtest.Option
+		candles []connector.Candle
+		steps   map[int]func(ctx context.Context, a backtest.Account) error
+		want    want
+	}{
+		"Market orders fill at the next open with slippage and fees": {
+			opts: []backtest.Option{
+				backtest.WithCapital(d("1000")), backtest.WithSlippage(d("0.01")), backtest.WithFees(backtest.Fees{Taker: d("0.01")}),
+			},
+			candles: candles([4]string{"100", "100", "100", "100"}, [4]string{"100", "125", "95", "120"}, [4]string{"120", "125", "115", "118"}),
+			steps: map[int]func(ctx context.Context, a backtest.Account) error{
+				0: place(market(connector.SideBuy, "5")),
+				1: place(market(connector.SideSell, "5")),
+			},
+			want: want{
+				trades: []backtest.Trade{
+					{Time: at(1), OrderID: 1, Side: connector.SideBuy, Type: connector.OrderTypeMarket, Price: d("101"), Quantity: d("5"), Fee: d("5.05")},
+					{
+						Time: at(2), OrderID: 2, Side: connector.SideSell, Type: connector.OrderTypeMarket, Price: d("118.8"), Quantity: d("5"),
+						Fee: d("5.94"), Profit: d("78.01"), Closing: true,
+					},
+				},
+				finalEquity: "1078.01",
+				winRate:     "1",
+			},
+		},
+		"Market buy is reduced to the free balance": {
+			opts:    []backtest.Option{backtest.WithCapital(d("1000"))},
+			candles: candles([4]string{"100", "100", "100", "100"}, [4]string{"125", "125", "125", "125"}),
+			steps:   map[int]func(ctx context.Context, a backtest.Account) error{0: place(market(connector.SideBuy, "10"))},
+			want: want{
+				trades: []backtest.Trade{
+					{Time: at(1), OrderID: 1, Side: connector.SideBuy, Type: connector.OrderTypeMarket, Price: d("125"), Quantity: d("8"), Fee: d("0")},
+				},
+				finalEquity: "1000",
+				winRate:     "0",
+			},
+		},
+		"Limit buy fills once the low reaches its price": {
+			opts: []backtest.Option{backtest.WithCapital(d("1000")), backtest.WithFees(backtest.Fees{Maker: d("0.001"), Taker: d("0.01")})},
+			candles: candles(
+				[4]string{"100", "100", "100", "100"}, [4]string{"100", "105", "95", "100"}, [4]string{"100", "100", "85", "90"},
+			),
+			steps: map[int]func(ctx context.Context, a backtest.Account) error{0: place(limit(connector.SideBuy, "1", "90"))},
+			want: want{
+				trades: []backtest.Trade{
+					{Time: at(2), OrderID: 1, Side: connector.SideBuy, Type: connector.OrderTypeLimit, Price: d("90"), Quantity: d("1"), Fee: d("0.09")},
+				},
+				finalEquity: "999.91",
+				winRate:     "0",
+			},
+		},
+		"Limit sell opened through fills at the open": {
+			opts: []backtest.Option{backtest.WithCapital(d("1000"))},
+			candles: candles(
+				[4]string{"100", "100", "100", "100"}, [4]string{"100", "100", "100", "100"}, [4]string{"120", "130", "115", "125"},
+			),
+			steps: map[int]func(ctx context.Context, a backtest.Account) error{
+				0: place(market(connector.SideBuy, "2")),
+				1: place(limit(connector.SideSell, "2", "110")),
+			},
+			want: want{
+				trades: []backtest.Trade{
+					{Time: at(1), OrderID: 1, Side: connector.SideBuy, Type: connector.OrderTypeMarket, Price: d("100"), Quantity: d("2"), Fee: d("0")},
+					{
+						Time: at(2), OrderID: 2, Side: connector.SideSell, Type: connector.OrderTypeLimit, Price: d("120"), Quantity: d("2"),
+						Fee: d("0"), Profit: d("40"), Closing: true,
+					},
+				},
+				finalEquity: "1040",
+				winRate:     "1",
+			},
+		},
+		"Sell without a base balance": {
+			candles: candles([4]string{"100", "100", "100", "100"}),
+			steps:   map[int]func(ctx context.Context, a backtest.Account) error{0: place(market(connector.SideSell, "1"))},
+			want:    want{err: backtest.ErrInsufficientBalance},
+		},
+		"Order on another symbol": {
+			candles: candles([4]string{"100", "100", "100", "100"}),
+			steps: map[int]func(ctx context.Context, a backtest.Account) error{
+				0: place(connector.OrderRequest{Symbol: "ETHUSDT", Side: connector.SideBuy, Type: connector.OrderTypeMarket, Quantity: d("1")}),
+			},
+			want: want{err: backtest.ErrUnknownSymbol},
+		},
+		"No candles": {
+			want: want{err: backtest.ErrNoCandles},
+		},
+		"Unordered candles": {
+			candles: append(candles([4]string{"100", "100", "100", "100"}), candles([4]string{"100", "100", "100", "100"})...),
+			want:    want{err: backtest.ErrUnorderedCandles},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := backtest.New(tt.opts...).Run(context.Background(), &scripted{steps: tt.steps}, tt.candles)
+			if tt.want.err != nil {
+				assert.True(t, errors.Is(err, tt.want.err), "got %v", err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, r.Trades, len(tt.want.trades))
+
+			for i, trade := range tt.want.trades {
+				got := r.Trades[i]
+				assert.Equal(t, trade.Time, got.Time)
+				assert.Equal(t, trade.OrderID, got.OrderID)
+				assert.Equal(t, trade.Side, got.Side)
+				assert.Equal(t, trade.Type, got.Type)
+				assert.Equal(t, trade.Price.String(), got.Price.String())
+				assert.Equal(t, trade.Quantity.String(), got.Quantity.String())
+				assert.Equal(t, trade.Fee.String(), got.Fee.String())
+				assert.Equal(t, trade.Profit.String(), got.Profit.String())
+				assert.Equal(t, trade.Closing, got.Closing)
+			}
+
+			assert.Equal(t, tt.want.finalEquity, r.FinalEquity.String())
+			assert.Equal(t, tt.want.winRate, r.WinRate.String())
+		})
+	}
+}
+
+func TestBacktest_Run_Metrics(t *testing.T) {
+	t.Parallel()
+
+	cs := candles(
+		[4]string{"100", "100", "100", "100"}, [4]string{"100", "200", "100", "200"},
+		[4]string{"200", "200", "100", "100"}, [4]string{"100", "150", "100", "150"},
+	)
+
+	r, err := backtest.New(backtest.WithCapital(d("1000"))).Run(context.Background(), &backtest.BuyAndHold{Quote: "USDT"}, cs)
+	assert.NoError(t, err)
+
+	equity := make([]string, 0, len(r.Equity))
+	for _, p := range r.Equity {
+		equity = append(equity, p.Equity.String())
+	}
+
+	// The returns of the candles are 0, 1, -0.5 and 0.5, over 8760 one hour candles a year.
+	periods := math.Sqrt(8760)
+
+	assert.Equal(t, []string{"1000", "2000", "1000", "1500"}, equity)
+	assert.Equal(t, at(0), r.Start)
+	assert.Equal(t, at(4).Add(-time.Millisecond), r.End)
+	assert.Equal(t, 4, r.Candles)
+	assert.Equal(t, "0.5", r.TotalReturn.String())
+	assert.Equal(t, "0.5", r.MaxDrawdown.String())
+	assert.Equal(t, "0.75", r.Exposure.String())
+	assert.Equal(t, 0, r.ClosedTrades)
+	assert.InDelta(t, 0.25/math.Sqrt(1.25/3)*periods, r.Sharpe, 1e-9)
+	assert.InDelta(t, 0.25/0.25*periods, r.Sortino, 1e-9)
+}
+
+func TestBacktest_Run_CancelOrder(t *testing.T) {
+	t.Parallel()
+
+	var got []connector.Balance
+
+	steps := map[int]func(ctx context.Context, a backtest.Account) error{
+		0: place(limit(connector.SideBuy, "1", "50")),
+		1: func(ctx context.Context, a backtest.Account) error {
+			if _, err := a.CancelOrder(ctx, "BTCUSDT", 1); err != nil {
+				return err
+			}
+
+			if _, err := a.CancelOrder(ctx, "BTCUSDT", 1); !errors.Is(err, backtest.ErrOrderNotOpen) {
+				return errors.New("cancelled order cancelled again")
+			}
+
+			if _, err := a.GetOrder(ctx, "BTCUSDT", 2); !errors.Is(err, backtest.ErrUnknownOrder) {
+				return errors.New("unknown order found")
+			}
+
+			var err error
+			got, err = a.GetBalances(ctx)
+
+			return err
+		},
+	}
+
+	_, err := backtest.New(backtest.WithCapital(d("1000"))).Run(context.Background(), &scripted{steps: steps}, candles(
+		[4]string{"100", "100", "100", "100"}, [4]string{"100", "100", "100", "100"},
+	))
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "USDT", got[1].Asset)
+	assert.Equal(t, "1000", got[1].Free.String())
+	assert.Equal(t, "0", got[1].Locked.String())
+}
+
+func TestWriteTrades(t *testing.T) {
+	t.Parallel()
+
+	trades := []backtest.Trade{
+		{Time: at(1), OrderID: 1, Side: connector.SideBuy, Type: connector.OrderTypeMarket, Price: d("101"), Quantity: d("5"), Fee: d("5.05")},
+		{
+			Time: at(2), OrderID: 2, Side: connector.SideSell, Type: connector.OrderTypeLimit, Price: d("118.8"), Quantity: d("5"),
+			Fee: d("5.94"), Profit: d("78.01"), Closing: true,
+		},
+	}
+
+	var b bytes.Buffer
+
+	assert.NoError(t, backtest.WriteTrades(&b, trades))
+	assert.Equal(t, `time,order_id,side,type,price,quantity,fee,profit
+2024-01-01T01:00:00Z,1,BUY,MARKET,101,5,5.05,
+2024-01-01T02:00:00Z,2,SELL,LIMIT,118.8,5,5.94,78.01
+`, b.String())
+}
@@ -0,0 +1,41 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// WriteTrades writes the trade log as CSV, with a header row. The profit is only written for the sells.
+func WriteTrades(w io.Writer, trades []Trade) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"time", "order_id", "side", "type", "price", "quantity", "fee", "profit"}); err != nil {
+		return fmt.Errorf("error writing trade log: %w", err)
+	}
+
+	for _, t := range trades {
+		profit := ""
+		if t.Closing {
+			profit = t.Profit.String()
+		}
+
+		row := []string{
+			t.Time.UTC().Format(time.RFC3339), strconv.FormatInt(t.OrderID, 10), string(t.Side), string(t.Type),
+			t.Price.String(), t.Quantity.String(), t.Fee.String(), profit,
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("error writing trade log: %w", err)
+		}
+	}
+
+	cw.Flush()
+
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("error writing trade log: %w", err)
+	}
+
+	return nil
+}
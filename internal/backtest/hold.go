@@ -0,0 +1,46 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// BuyAndHold is the baseline strategy, buying with the whole quote balance on the first candle and holding until the end.
+type BuyAndHold struct {
+	Quote  string
+	bought bool
+}
+
+var _ Strategy = (*BuyAndHold)(nil)
+
+// OnCandle buys on the first candle with a market order, whose quantity is reduced to the balance once its price is known.
+func (s *BuyAndHold) OnCandle(ctx context.Context, c connector.Candle, a Account) error {
+	if s.bought || !c.Close.IsPositive() {
+		return nil
+	}
+
+	balances, err := a.GetBalances(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting balances: %w", err)
+	}
+
+	for _, b := range balances {
+		if b.Asset != s.Quote || !b.Free.IsPositive() {
+			continue
+		}
+
+		req := connector.OrderRequest{
+			Symbol: c.Symbol, Side: connector.SideBuy, Type: connector.OrderTypeMarket,
+			Quantity: b.Free.Div(c.Close).RoundDown(quantityPlaces),
+		}
+		if _, err := a.PlaceOrder(ctx, req); err != nil {
+			return fmt.Errorf("error placing order: %w", err)
+		}
+
+		s.bought = true
+	}
+
+	return nil
+}
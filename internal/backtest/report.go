@@ -0,0 +1,204 @@
+package backtest
+
+import (
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// year is the duration the Sharpe and Sortino ratios are annualized over, as crypto markets trade every day.
+const year = 365 * 24 * time.Hour
+
+// Trade is a fill of a simulated order. Profit is the realized profit of a sell against the average cost of the base balance,
+// fees included, and Closing marks the sells, which close part of the position.
+type Trade struct {
+	Time     time.Time
+	OrderID  int64
+	Side     connector.Side
+	Type     connector.OrderType
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+	Fee      decimal.Decimal
+	Profit   decimal.Decimal
+	Closing  bool
+}
+
+// EquityPoint is the value of the account in the quote asset at the close of a candle.
+type EquityPoint struct {
+	Time   time.Time
+	Equity decimal.Decimal
+}
+
+// Report is the performance of a backtest. The returns, drawdown, win rate and exposure are fractions, the Sharpe and Sortino ratios
+// are annualized from the returns of the candles with a zero risk-free rate, and the exposure is the fraction of the candles
+// closed with a base balance.
+type Report struct {
+	Symbol        string
+	Interval      string
+	Start         time.Time
+	End           time.Time
+	Candles       int
+	InitialEquity decimal.Decimal
+	FinalEquity   decimal.Decimal
+	TotalReturn   decimal.Decimal
+	MaxDrawdown   decimal.Decimal
+	Sharpe        float64
+	Sortino       float64
+	ClosedTrades  int
+	WinRate       decimal.Decimal
+	Exposure      decimal.Decimal
+	Fees          decimal.Decimal
+	Equity        []EquityPoint
+	Trades        []Trade
+}
+
+func newReport(candles []connector.Candle, capital decimal.Decimal, equity []EquityPoint, trades []Trade, held int) *Report {
+	first, last := candles[0], candles[len(candles)-1]
+	r := &Report{
+		Symbol:        first.Symbol,
+		Interval:      first.Interval,
+		Start:         first.OpenTime,
+		End:           last.CloseTime,
+		Candles:       len(candles),
+		InitialEquity: capital,
+		FinalEquity:   equity[len(equity)-1].Equity,
+		MaxDrawdown:   maxDrawdown(equity),
+		Exposure:      decimal.NewFromInt(int64(held)).Div(decimal.NewFromInt(int64(len(candles)))),
+		Equity:        equity,
+		Trades:        trades,
+	}
+
+	if capital.IsPositive() {
+		r.TotalReturn = r.FinalEquity.Div(capital).Sub(decimal.NewFromInt(1))
+	}
+
+	wins := 0
+
+	for _, t := range trades {
+		r.Fees = r.Fees.Add(t.Fee)
+
+		if t.Closing {
+			r.ClosedTrades++
+
+			if t.Profit.IsPositive() {
+				wins++
+			}
+		}
+	}
+
+	if r.ClosedTrades > 0 {
+		r.WinRate = decimal.NewFromInt(int64(wins)).Div(decimal.NewFromInt(int64(r.ClosedTrades)))
+	}
+
+	returns := periodReturns(capital, equity)
+	periods := periodsPerYear(first)
+	r.Sharpe = sharpe(returns, periods)
+	r.Sortino = sortino(returns, periods)
+
+	return r
+}
+
+// maxDrawdown returns the largest fall of the equity from its previous peak, as a fraction of the peak.
+func maxDrawdown(equity []EquityPoint) decimal.Decimal {
+	var peak, res decimal.Decimal
+
+	for _, p := range equity {
+		if p.Equity.GreaterThan(peak) {
+			peak = p.Equity
+		}
+
+		if !peak.IsPositive() {
+			continue
+		}
+
+		if dd := peak.Sub(p.Equity).Div(peak); dd.GreaterThan(res) {
+			res = dd
+		}
+	}
+
+	return res
+}
+
+// periodReturns returns the return of every candle, starting from the initial capital.
+func periodReturns(capital decimal.Decimal, equity []EquityPoint) []float64 {
+	res := make([]float64, 0, len(equity))
+	prev := capital
+
+	for _, p := range equity {
+		if prev.IsPositive() {
+			res = append(res, p.Equity.Div(prev).Sub(decimal.NewFromInt(1)).InexactFloat64())
+		}
+
+		prev = p.Equity
+	}
+
+	return res
+}
+
+// periodsPerYear returns the number of candles in a year, from the interval of the candle, or zero when it is unknown.
+func periodsPerYear(c connector.Candle) float64 {
+	interval := c.CloseTime.Sub(c.OpenTime).Round(time.Second)
+	if interval <= 0 {
+		return 0
+	}
+
+	return float64(year) / float64(interval)
+}
+
+func sharpe(returns []float64, periods float64) float64 {
+	mean, n := average(returns), float64(len(returns))
+	if n < 2 {
+		return 0
+	}
+
+	var sum float64
+	for _, r := range returns {
+		sum += (r - mean) * (r - mean)
+	}
+
+	sd := math.Sqrt(sum / (n - 1))
+	if sd == 0 {
+		return 0
+	}
+
+	return mean / sd * math.Sqrt(periods)
+}
+
+// sortino is the Sharpe ratio penalizing only the returns below zero, by their downside deviation.
+func sortino(returns []float64, periods float64) float64 {
+	mean, n := average(returns), float64(len(returns))
+	if n < 2 {
+		return 0
+	}
+
+	var sum float64
+
+	for _, r := range returns {
+		if r < 0 {
+			sum += r * r
+		}
+	}
+
+	dd := math.Sqrt(sum / n)
+	if dd == 0 {
+		return 0
+	}
+
+	return mean / dd * math.Sqrt(periods)
+}
+
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
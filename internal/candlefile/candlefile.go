@@ -0,0 +1,254 @@
+// Package candlefile provides the reading and writing of candles as CSV or JSON lines files. Files are append only, so downloads can be resumed after the last written candle.
+package candlefile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// Format is the format of a candle file.
+type Format string
+
+// The supported candle file formats.
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+// ErrUnknownFormat is returned when a candle file format is not supported.
+var ErrUnknownFormat = errors.New("unknown candle file format")
+
+// ErrInvalidRecord is returned when a line of a candle file cannot be decoded.
+var ErrInvalidRecord = errors.New("invalid candle record")
+
+// timeLayout is the layout of the open and close times. Binance times have a millisecond precision.
+const timeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// csvHeader returns the header of CSV files. It matches the fields of record.
+func csvHeader() []string {
+	return []string{"symbol", "interval", "open_time", "close_time", "open", "high", "low", "close", "volume", "quote_volume", "trades"}
+}
+
+// record is the JSON representation of a candle.
+type record struct {
+	Symbol      string          `json:"symbol"`
+	Interval    string          `json:"interval"`
+	OpenTime    time.Time       `json:"open_time"`
+	CloseTime   time.Time       `json:"close_time"`
+	Open        decimal.Decimal `json:"open"`
+	High        decimal.Decimal `json:"high"`
+	Low         decimal.Decimal `json:"low"`
+	Close       decimal.Decimal `json:"close"`
+	Volume      decimal.Decimal `json:"volume"`
+	QuoteVolume decimal.Decimal `json:"quote_volume"`
+	Trades      uint64          `json:"trades"`
+}
+
+// ParseFormat parses a format name, case insensitive.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatCSV, FormatJSONL:
+		return f, nil
+	default:
+		return "", fmt.Errorf("%w: %q, expected %s or %s", ErrUnknownFormat, s, FormatCSV, FormatJSONL)
+	}
+}
+
+// Writer writes candles to an underlying writer in the given format.
+type Writer struct {
+	w      io.Writer
+	format Format
+	header bool
+}
+
+// NewWriter creates a new writer. The CSV header is written before the first candles when header is true,
+// which should only be the case for empty files.
+func NewWriter(w io.Writer, format Format, header bool) *Writer {
+	return &Writer{w: w, format: format, header: header && format == FormatCSV}
+}
+
+// Write writes the candles, one per line.
+func (w *Writer) Write(candles []connector.Candle) error {
+	var buf bytes.Buffer
+
+	switch w.format {
+	case FormatCSV:
+		cw := csv.NewWriter(&buf)
+
+		if w.header {
+			if err := cw.Write(csvHeader()); err != nil {
+				return fmt.Errorf("error encoding csv header: %w", err)
+			}
+		}
+
+		for _, c := range candles {
+			if err := cw.Write(toCSVRow(c)); err != nil {
+				return fmt.Errorf("error encoding candle: %w", err)
+			}
+		}
+
+		cw.Flush()
+
+		if err := cw.Error(); err != nil {
+			return fmt.Errorf("error encoding candles: %w", err)
+		}
+	case FormatJSONL:
+		enc := json.NewEncoder(&buf)
+
+		for _, c := range candles {
+			if err := enc.Encode(toRecord(c)); err != nil {
+				return fmt.Errorf("error encoding candle: %w", err)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, w.format)
+	}
+
+	if _, err := w.w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("error writing candles: %w", err)
+	}
+
+	w.header = false
+
+	return nil
+}
+
+// Read reads every candle of a file in the given format.
+func Read(r io.Reader, format Format) ([]connector.Candle, error) {
+	var candles []connector.Candle
+
+	err := scan(r, format, func(c connector.Candle) {
+		candles = append(candles, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return candles, nil
+}
+
+// Last returns the last candle of a file in the given format. It returns false when the file holds no candle.
+func Last(r io.Reader, format Format) (connector.Candle, bool, error) {
+	var (
+		last  connector.Candle
+		found bool
+	)
+
+	err := scan(r, format, func(c connector.Candle) {
+		last, found = c, true
+	})
+	if err != nil {
+		return connector.Candle{}, false, err
+	}
+
+	return last, found, nil
+}
+
+func scan(r io.Reader, format Format, fn func(connector.Candle)) error {
+	if _, err := ParseFormat(string(format)); err != nil {
+		return err
+	}
+
+	s := bufio.NewScanner(r)
+
+	for n := 1; s.Scan(); n++ {
+		line := s.Text()
+		if strings.TrimSpace(line) == "" || (format == FormatCSV && strings.HasPrefix(line, csvHeader()[0]+",")) {
+			continue
+		}
+
+		c, err := decode(line, format)
+		if err != nil {
+			return fmt.Errorf("error decoding line %d: %w", n, err)
+		}
+
+		fn(c)
+	}
+
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("error reading candles: %w", err)
+	}
+
+	return nil
+}
+
+func decode(line string, format Format) (connector.Candle, error) {
+	if format == FormatJSONL {
+		var rec record
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			return connector.Candle{}, fmt.Errorf("%w: %w", ErrInvalidRecord, err)
+		}
+
+		return connector.Candle(rec), nil
+	}
+
+	row, err := csv.NewReader(strings.NewReader(line)).Read()
+	if err != nil {
+		return connector.Candle{}, fmt.Errorf("%w: %w", ErrInvalidRecord, err)
+	}
+
+	return fromCSVRow(row)
+}
+
+func toRecord(c connector.Candle) record {
+	rec := record(c)
+	rec.OpenTime, rec.CloseTime = c.OpenTime.UTC(), c.CloseTime.UTC()
+
+	return rec
+}
+
+func toCSVRow(c connector.Candle) []string {
+	return []string{
+		c.Symbol, c.Interval, c.OpenTime.UTC().Format(timeLayout), c.CloseTime.UTC().Format(timeLayout),
+		c.Open.String(), c.High.String(), c.Low.String(), c.Close.String(), c.Volume.String(), c.QuoteVolume.String(),
+		strconv.FormatUint(c.Trades, 10),
+	}
+}
+
+func fromCSVRow(row []string) (connector.Candle, error) {
+	if n := len(csvHeader()); len(row) != n {
+		return connector.Candle{}, fmt.Errorf("%w: expected %d fields, got %d", ErrInvalidRecord, n, len(row))
+	}
+
+	c := connector.Candle{Symbol: row[0], Interval: row[1]}
+
+	for dst, v := range map[*time.Time]string{&c.OpenTime: row[2], &c.CloseTime: row[3]} {
+		t, err := time.Parse(timeLayout, v)
+		if err != nil {
+			return connector.Candle{}, fmt.Errorf("%w: %w", ErrInvalidRecord, err)
+		}
+
+		*dst = t
+	}
+
+	fields := map[*decimal.Decimal]string{&c.Open: row[4], &c.High: row[5], &c.Low: row[6], &c.Close: row[7], &c.Volume: row[8], &c.QuoteVolume: row[9]}
+	for dst, v := range fields {
+		d, err := decimal.NewFromString(v)
+		if err != nil {
+			return connector.Candle{}, fmt.Errorf("%w: %w", ErrInvalidRecord, err)
+		}
+
+		*dst = d
+	}
+
+	trades, err := strconv.ParseUint(row[10], 10, 64)
+	if err != nil {
+		return connector.Candle{}, fmt.Errorf("%w: %w", ErrInvalidRecord, err)
+	}
+
+	c.Trades = trades
+
+	return c, nil
+}
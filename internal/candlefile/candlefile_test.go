@@ -0,0 +1,183 @@
+package candlefile_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/candlefile"
+	"github.com/twk/trader-b/internal/connector"
+)
+
+func candle(open time.Time) connector.Candle {
+	return connector.Candle{
+		Symbol: "BTCUSDT", Interval: "1m", OpenTime: open, CloseTime: open.Add(time.Minute - time.Millisecond),
+		Open: decimal.RequireFromString("100.1"), High: decimal.RequireFromString("101"), Low: decimal.RequireFromString("99"),
+		Close: decimal.RequireFromString("100.5"), Volume: decimal.RequireFromString("1.5"), QuoteVolume: decimal.RequireFromString("150.75"), Trades: 3,
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		format candlefile.Format
+		err    error
+	}
+
+	tests := map[string]struct {
+		name string
+		want want
+	}{
+		"csv":     {name: "csv", want: want{format: candlefile.FormatCSV}},
+		"jsonl":   {name: "JSONL", want: want{format: candlefile.FormatJSONL}},
+		"parquet": {name: "parquet", want: want{err: errors.New(`unknown candle file format: "parquet", expected csv or jsonl`)}},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := candlefile.ParseFormat(tt.name)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				assert.ErrorIs(t, err, candlefile.ErrUnknownFormat)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.format, f)
+		})
+	}
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	open := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		format candlefile.Format
+		want   string
+	}{
+		"csv": {
+			format: candlefile.FormatCSV,
+			want: "symbol,interval,open_time,close_time,open,high,low,close,volume,quote_volume,trades\n" +
+				"BTCUSDT,1m,2024-01-01T00:00:00.000Z,2024-01-01T00:00:59.999Z,100.1,101,99,100.5,1.5,150.75,3\n" +
+				"BTCUSDT,1m,2024-01-01T00:01:00.000Z,2024-01-01T00:01:59.999Z,100.1,101,99,100.5,1.5,150.75,3\n",
+		},
+		"jsonl": {
+			format: candlefile.FormatJSONL,
+			want: `{"symbol":"BTCUSDT","interval":"1m","open_time":"2024-01-01T00:00:00Z","close_time":"2024-01-01T00:00:59.999Z",` +
+				`"open":"100.1","high":"101","low":"99","close":"100.5","volume":"1.5","quote_volume":"150.75","trades":3}` + "\n" +
+				`{"symbol":"BTCUSDT","interval":"1m","open_time":"2024-01-01T00:01:00Z","close_time":"2024-01-01T00:01:59.999Z",` +
+				`"open":"100.1","high":"101","low":"99","close":"100.5","volume":"1.5","quote_volume":"150.75","trades":3}` + "\n",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			w := candlefile.NewWriter(&buf, tt.format, true)
+			assert.NoError(t, w.Write([]connector.Candle{candle(open)}))
+			assert.NoError(t, w.Write([]connector.Candle{candle(open.Add(time.Minute))}))
+			assert.Equal(t, tt.want, buf.String())
+
+			candles, err := candlefile.Read(strings.NewReader(buf.String()), tt.format)
+			assert.NoError(t, err)
+			assert.Len(t, candles, 2)
+			assert.True(t, candles[1].OpenTime.Equal(open.Add(time.Minute)))
+			assert.True(t, candles[1].Close.Equal(decimal.RequireFromString("100.5")))
+
+			last, ok, err := candlefile.Last(strings.NewReader(buf.String()), tt.format)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, candles[1], last)
+		})
+	}
+}
+
+func TestLast(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		ok  bool
+		err error
+	}
+
+	tests := map[string]struct {
+		format  candlefile.Format
+		content string
+		want    want
+	}{
+		"empty file": {
+			format: candlefile.FormatCSV,
+		},
+		"header only": {
+			format:  candlefile.FormatCSV,
+			content: "symbol,interval,open_time,close_time,open,high,low,close,volume,quote_volume,trades\n",
+		},
+		"truncated csv line": {
+			format:  candlefile.FormatCSV,
+			content: "BTCUSDT,1m,2024-01-01T00:00:00.000Z,2024-01-01T00:00:59.999Z,100.1\n",
+			want:    want{err: errors.New("error decoding line 1: invalid candle record: expected 11 fields, got 5")},
+		},
+		"invalid csv decimal": {
+			format:  candlefile.FormatCSV,
+			content: "BTCUSDT,1m,2024-01-01T00:00:00.000Z,2024-01-01T00:00:59.999Z,abc,101,99,100.5,1.5,150.75,3\n",
+			want:    want{err: errors.New("error decoding line 1: invalid candle record: can't convert abc to decimal")},
+		},
+		"invalid csv time": {
+			format:  candlefile.FormatCSV,
+			content: "BTCUSDT,1m,yesterday,2024-01-01T00:00:59.999Z,100.1,101,99,100.5,1.5,150.75,3\n",
+			want:    want{err: errors.New(`error decoding line 1: invalid candle record: parsing time "yesterday" as "2006-01-02T15:04:05.000Z07:00": cannot parse "yesterday" as "2006"`)},
+		},
+		"invalid jsonl line": {
+			format:  candlefile.FormatJSONL,
+			content: "\n{\"symbol\":",
+			want:    want{err: errors.New("error decoding line 2: invalid candle record: unexpected end of JSON input")},
+		},
+		"unknown format": {
+			format: "parquet",
+			want:   want{err: errors.New(`unknown candle file format: "parquet", expected csv or jsonl`)},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, ok, err := candlefile.Last(strings.NewReader(tt.content), tt.format)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.ok, ok)
+		})
+	}
+}
+
+func TestWriter_UnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	err := candlefile.NewWriter(&bytes.Buffer{}, "parquet", false).Write(nil)
+
+	assert.ErrorIs(t, err, candlefile.ErrUnknownFormat)
+}
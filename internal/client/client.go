@@ -4,34 +4,189 @@ package client
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/config"
 )
 
 type httpClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// Request is an encoded request, given to the Signer of the client before it is sent.
+// Signers may add parameters to the query or the body and set headers.
+type Request struct {
+	Method string
+	URL    *url.URL
+	Body   string
+	Header http.Header
+}
+
+// Signer signs the requests of a client, e.g. by adding a timestamp and a signature and setting an API key header.
+type Signer interface {
+	Sign(r *Request) error
+}
+
 // Client is a wrapper around the http client.
 type Client struct {
 	httpClient httpClient
+	signer     Signer
+	retry      retryPolicy
+	log        *zap.Logger
+}
+
+// Option configures the client.
+type Option func(*Client)
+
+// WithRetry retries the requests failing with a network error, a 5xx or a 429 response as configured.
+// Requests are not retried when it is not set.
+func WithRetry(cfg config.Retry) Option {
+	return func(c *Client) {
+		c.retry = newRetryPolicy(cfg)
+	}
+}
+
+// WithLogger sets the logger of the client, which logs the retried requests.
+func WithLogger(l *zap.Logger) Option {
+	return func(c *Client) {
+		c.log = l
+	}
 }
 
 // NewClient creates a new Client.
-func NewClient(httpClient httpClient) *Client {
-	return &Client{httpClient: httpClient}
+func NewClient(httpClient httpClient, opts ...Option) *Client {
+	c := &Client{httpClient: httpClient, retry: newRetryPolicy(config.Retry{}), log: zap.NewNop()}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithSigner returns a client sharing the http client and the options of c that signs every request with the signer.
+func (c *Client) WithSigner(s Signer) *Client {
+	signed := *c
+	signed.signer = s
+
+	return &signed
 }
 
 // Get performs a GET request.
 func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	return c.send(ctx, http.MethodGet, url, nil, nil)
+}
+
+// Post performs a POST request. The query is appended to the query of the url and the form is sent url encoded as the body.
+func (c *Client) Post(ctx context.Context, url string, query, form url.Values) (*http.Response, error) {
+	return c.send(ctx, http.MethodPost, url, query, form)
+}
+
+// Put performs a PUT request. The query is appended to the query of the url and the form is sent url encoded as the body.
+func (c *Client) Put(ctx context.Context, url string, query, form url.Values) (*http.Response, error) {
+	return c.send(ctx, http.MethodPut, url, query, form)
+}
+
+// Delete performs a DELETE request. The query is appended to the query of the url and the form is sent url encoded as the body.
+func (c *Client) Delete(ctx context.Context, url string, query, form url.Values) (*http.Response, error) {
+	return c.send(ctx, http.MethodDelete, url, query, form)
+}
+
+// send encodes the request and performs it, retrying it as configured. Every attempt is signed again when the client has a signer.
+// The query of the url is kept as it is, as signatures may depend on the order of its parameters.
+func (c *Client) send(ctx context.Context, method, rawURL string, query, form url.Values) (*http.Response, error) {
+	u, err := url.Parse(rawURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	if len(query) > 0 {
+		u.RawQuery = join(u.RawQuery, query.Encode())
+	}
+
+	body := form.Encode()
+	start := c.retry.now()
+
+	for attempt := 1; ; attempt++ {
+		req, err := c.newRequest(ctx, method, *u, body)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient.Do(req)
+
+		delay, ok := c.retry.delay(ctx, attempt, c.retry.now().Sub(start), method, resp, err)
+		if !ok {
+			if err != nil {
+				return nil, fmt.Errorf("failed to perform request: %w", err)
+			}
+
+			return resp, nil
+		}
+
+		c.log.Warn("retrying request", zap.String("method", method), zap.String("url", u.Redacted()), zap.Int("attempt", attempt),
+			zap.Duration("delay", delay), zap.Int("status", status(resp)), zap.Error(err))
+		discard(resp)
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to perform request: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+// newRequest creates the request of an attempt, signed when the client has a signer.
+func (c *Client) newRequest(ctx context.Context, method string, u url.URL, body string) (*http.Request, error) {
+	r := &Request{Method: method, URL: &u, Body: body, Header: http.Header{}}
+
+	if c.signer != nil {
+		if err := c.signer.Sign(r); err != nil {
+			return nil, fmt.Errorf("failed to sign request: %w", err)
+		}
+	}
+
+	var reader io.Reader = http.NoBody
+	if r.Body != "" {
+		reader = strings.NewReader(r.Body)
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.URL.String(), reader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to perform request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	return resp, nil
+	for k, v := range r.Header {
+		req.Header[k] = v
+	}
+
+	return req, nil
+}
+
+// status returns the status code of the response, zero when there is none.
+func status(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+
+	return resp.StatusCode
+}
+
+// join joins two encoded parameter lists.
+func join(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	default:
+		return a + "&" + b
+	}
 }
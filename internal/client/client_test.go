@@ -2,12 +2,22 @@ package client_test
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/twk/trader-b/internal/client"
+	"github.com/twk/trader-b/internal/config"
 )
 
 func TestClient_Get(t *testing.T) {
@@ -91,3 +101,354 @@ func TestClient_Get(t *testing.T) {
 		})
 	}
 }
+
+// recordedRequest is the part of a request checked by the tests.
+type recordedRequest struct {
+	method      string
+	query       string
+	body        string
+	contentType string
+	apiKey      string
+}
+
+func record(t *testing.T) (*httptest.Server, *recordedRequest) {
+	t.Helper()
+
+	rec := &recordedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		*rec = recordedRequest{
+			method:      r.Method,
+			query:       r.URL.RawQuery,
+			body:        string(body),
+			contentType: r.Header.Get("Content-Type"),
+			apiKey:      r.Header.Get("X-API-KEY"),
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server, rec
+}
+
+func TestClient_Methods(t *testing.T) {
+	type fields struct {
+		path  string
+		query url.Values
+		form  url.Values
+		do    func(c *client.Client, ctx context.Context, url string, query, form url.Values) (*http.Response, error)
+	}
+
+	tests := map[string]struct {
+		fields fields
+		want   recordedRequest
+	}{
+		"POST with query and form": {
+			fields: fields{
+				path:  "/order?symbol=BTCUSDT",
+				query: url.Values{"side": {"BUY"}},
+				form:  url.Values{"quantity": {"1"}, "price": {"0.1"}},
+				do:    (*client.Client).Post,
+			},
+			want: recordedRequest{
+				method:      http.MethodPost,
+				query:       "symbol=BTCUSDT&side=BUY",
+				body:        "price=0.1&quantity=1",
+				contentType: "application/x-www-form-urlencoded",
+			},
+		},
+		"PUT with form": {
+			fields: fields{
+				path: "/listenKey",
+				form: url.Values{"listenKey": {"key"}},
+				do:   (*client.Client).Put,
+			},
+			want: recordedRequest{
+				method:      http.MethodPut,
+				body:        "listenKey=key",
+				contentType: "application/x-www-form-urlencoded",
+			},
+		},
+		"DELETE with query": {
+			fields: fields{
+				path:  "/order",
+				query: url.Values{"orderId": {"7"}},
+				do:    (*client.Client).Delete,
+			},
+			want: recordedRequest{
+				method: http.MethodDelete,
+				query:  "orderId=7",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server, rec := record(t)
+			c := client.NewClient(server.Client())
+
+			resp, err := tt.fields.do(c, context.Background(), server.URL+tt.fields.path, tt.fields.query, tt.fields.form)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.NoError(t, resp.Body.Close())
+			assert.Equal(t, tt.want, *rec)
+		})
+	}
+}
+
+type failingSigner struct{}
+
+func (failingSigner) Sign(*client.Request) error {
+	return errors.New("no key")
+}
+
+func TestClient_WithSigner(t *testing.T) {
+	// The example of the Binance documentation, signing the query with the secret key.
+	const (
+		secretKey = "NhqPtmdSJYdKjVHjA7PZj4Mge3R5YNiP1e3UZjInClVN65XAbvqqM6A7H5fATj0j"
+		timestamp = 1499827319559
+	)
+
+	clock := func() time.Time { return time.UnixMilli(timestamp) }
+
+	tests := map[string]struct {
+		signer client.Signer
+		path   string
+		form   url.Values
+		want   recordedRequest
+		err    string
+	}{
+		"Signs the query": {
+			signer: client.NewHMACSigner("X-API-KEY", "api-key", secretKey, client.WithRecvWindow(5*time.Second), client.WithClock(clock)),
+			path:   "/order?symbol=LTCBTC&side=BUY&type=LIMIT&timeInForce=GTC&quantity=1&price=0.1",
+			want: recordedRequest{
+				method: http.MethodPost,
+				query: "symbol=LTCBTC&side=BUY&type=LIMIT&timeInForce=GTC&quantity=1&price=0.1&recvWindow=5000&timestamp=1499827319559" +
+					"&signature=c8db56825ae71d6d79447849e617115f4a920fa2acdcab2b053c4b2838bd6b71",
+				apiKey: "api-key",
+			},
+		},
+		"Signs the query and the body": {
+			signer: client.NewHMACSigner("X-API-KEY", "api-key", secretKey, client.WithClock(clock)),
+			path:   "/order?symbol=LTCBTC",
+			form:   url.Values{"side": {"BUY"}},
+			want: recordedRequest{
+				method:      http.MethodPost,
+				query:       "symbol=LTCBTC&timestamp=1499827319559&signature=" + hmacSHA256(secretKey, "symbol=LTCBTC&timestamp=1499827319559side=BUY"),
+				body:        "side=BUY",
+				contentType: "application/x-www-form-urlencoded",
+				apiKey:      "api-key",
+			},
+		},
+		"Signer error": {
+			signer: failingSigner{},
+			path:   "/order",
+			err:    "failed to sign request: no key",
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server, rec := record(t)
+			c := client.NewClient(server.Client()).WithSigner(tt.signer)
+
+			resp, err := c.Post(context.Background(), server.URL+tt.path, nil, tt.form)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NoError(t, resp.Body.Close())
+			assert.Equal(t, tt.want, *rec)
+		})
+	}
+}
+
+func hmacSHA256(key, payload string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(payload))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type countingSigner struct {
+	signed atomic.Int32
+}
+
+func (s *countingSigner) Sign(r *client.Request) error {
+	r.Header.Set("X-Attempt", strconv.Itoa(int(s.signed.Add(1))))
+
+	return nil
+}
+
+func TestClient_Retry(t *testing.T) {
+	retry := config.Retry{MaxAttempts: 3, InitialInterval: time.Millisecond, MaxInterval: 5 * time.Millisecond, MaxElapsedTime: time.Second}
+
+	// reset closes the connection without answering, failing the request with a network error.
+	const reset = -1
+
+	type want struct {
+		status   int
+		attempts int32
+		err      string
+	}
+
+	tests := map[string]struct {
+		method   string
+		retry    config.Retry
+		statuses []int
+		header   http.Header
+		timeout  time.Duration
+		want     want
+	}{
+		"Retries a 5xx response": {
+			method:   http.MethodGet,
+			retry:    retry,
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			want:     want{status: http.StatusOK, attempts: 2},
+		},
+		"Retries a network error": {
+			method:   http.MethodDelete,
+			retry:    retry,
+			statuses: []int{reset, reset, http.StatusOK},
+			want:     want{status: http.StatusOK, attempts: 3},
+		},
+		"Returns the last response once the attempts are exhausted": {
+			method:   http.MethodPut,
+			retry:    retry,
+			statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusInternalServerError, http.StatusOK},
+			want:     want{status: http.StatusInternalServerError, attempts: 3},
+		},
+		"Returns the last error once the attempts are exhausted": {
+			method:   http.MethodGet,
+			retry:    config.Retry{MaxAttempts: 2, InitialInterval: time.Millisecond},
+			statuses: []int{reset, reset, http.StatusOK},
+			want:     want{attempts: 2, err: "failed to perform request"},
+		},
+		"Retries a 429 response of a POST request after the Retry-After seconds": {
+			method:   http.MethodPost,
+			retry:    retry,
+			statuses: []int{http.StatusTooManyRequests, http.StatusOK},
+			header:   http.Header{"Retry-After": {"0"}},
+			want:     want{status: http.StatusOK, attempts: 2},
+		},
+		"Retries after the Retry-After date": {
+			method:   http.MethodGet,
+			retry:    retry,
+			statuses: []int{http.StatusTooManyRequests, http.StatusOK},
+			header:   http.Header{"Retry-After": {"Wed, 21 Oct 2015 07:28:00 GMT"}},
+			want:     want{status: http.StatusOK, attempts: 2},
+		},
+		"Does not retry a 5xx response of a POST request": {
+			method:   http.MethodPost,
+			retry:    retry,
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			want:     want{status: http.StatusServiceUnavailable, attempts: 1},
+		},
+		"Does not retry a 4xx response": {
+			method:   http.MethodGet,
+			retry:    retry,
+			statuses: []int{http.StatusBadRequest, http.StatusOK},
+			want:     want{status: http.StatusBadRequest, attempts: 1},
+		},
+		"Does not retry past the max elapsed time": {
+			method:   http.MethodGet,
+			retry:    retry,
+			statuses: []int{http.StatusTooManyRequests, http.StatusOK},
+			header:   http.Header{"Retry-After": {"10"}},
+			want:     want{status: http.StatusTooManyRequests, attempts: 1},
+		},
+		"Does not retry past the deadline of the context": {
+			method:   http.MethodGet,
+			retry:    config.Retry{MaxAttempts: 3, InitialInterval: time.Minute, MaxInterval: time.Minute},
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			timeout:  time.Second,
+			want:     want{status: http.StatusServiceUnavailable, attempts: 1},
+		},
+		"Does not retry without a retry configuration": {
+			method:   http.MethodGet,
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			want:     want{status: http.StatusServiceUnavailable, attempts: 1},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts atomic.Int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := attempts.Add(1)
+				assert.Equal(t, strconv.Itoa(int(n)), r.Header.Get("X-Attempt"), "every attempt is signed again")
+
+				status := tt.statuses[n-1]
+				if status == reset {
+					conn, _, err := w.(http.Hijacker).Hijack()
+					assert.NoError(t, err)
+					assert.NoError(t, conn.Close())
+
+					return
+				}
+
+				for k, v := range tt.header {
+					w.Header()[k] = v
+				}
+
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+
+				defer cancel()
+			}
+
+			c := client.NewClient(server.Client(), client.WithRetry(tt.retry)).WithSigner(&countingSigner{})
+
+			var (
+				resp *http.Response
+				err  error
+			)
+
+			switch tt.method {
+			case http.MethodGet:
+				resp, err = c.Get(ctx, server.URL)
+			case http.MethodPost:
+				resp, err = c.Post(ctx, server.URL, nil, nil)
+			case http.MethodPut:
+				resp, err = c.Put(ctx, server.URL, nil, nil)
+			case http.MethodDelete:
+				resp, err = c.Delete(ctx, server.URL, nil, nil)
+			}
+
+			assert.Equal(t, tt.want.attempts, attempts.Load())
+
+			if tt.want.err != "" {
+				assert.ErrorContains(t, err, tt.want.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.status, resp.StatusCode)
+			assert.NoError(t, resp.Body.Close())
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/twk/trader-b/internal/config"
+)
+
+// retryMultiplier is the factor the backoff grows by after every attempt.
+const retryMultiplier = 2
+
+// retryPolicy decides whether and when a failed request is attempted again.
+type retryPolicy struct {
+	maxAttempts     int
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	maxElapsedTime  time.Duration
+	// jitter randomizes the backoff, returning a duration in [0, d).
+	jitter func(d time.Duration) time.Duration
+	now    func() time.Time
+}
+
+func newRetryPolicy(cfg config.Retry) retryPolicy {
+	return retryPolicy{
+		maxAttempts:     cfg.MaxAttempts,
+		initialInterval: cfg.InitialInterval,
+		maxInterval:     cfg.MaxInterval,
+		maxElapsedTime:  cfg.MaxElapsedTime,
+		jitter: func(d time.Duration) time.Duration {
+			if d <= 0 {
+				return 0
+			}
+
+			return rand.N(d)
+		},
+		now: time.Now,
+	}
+}
+
+// delay returns the wait before the next attempt of a request, and false when the request is not retried:
+// when it succeeded or failed permanently, when the attempts are exhausted, or when the wait would end after the
+// max elapsed time or the deadline of the context.
+func (p retryPolicy) delay(
+	ctx context.Context, attempt int, elapsed time.Duration, method string, resp *http.Response, err error,
+) (time.Duration, bool) {
+	if attempt >= p.maxAttempts || !retryable(ctx, method, resp, err) {
+		return 0, false
+	}
+
+	d, ok := p.retryAfter(resp)
+	if !ok {
+		d = p.backoff(attempt)
+	}
+
+	if p.maxElapsedTime > 0 && elapsed+d > p.maxElapsedTime {
+		return 0, false
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && p.now().Add(d).After(deadline) {
+		return 0, false
+	}
+
+	return d, true
+}
+
+// backoff returns the exponential backoff after the attempt, randomized between half and one and a half times its value.
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	d := p.initialInterval
+	for i := 1; i < attempt && d < p.maxInterval; i++ {
+		d *= retryMultiplier
+	}
+
+	if p.maxInterval > 0 {
+		d = min(d, p.maxInterval)
+	}
+
+	return d/2 + p.jitter(d)
+}
+
+// retryAfter returns the wait asked by the Retry-After header of the response, given in seconds or as an HTTP date.
+func (p retryPolicy) retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(p.now()), 0), true
+	}
+
+	return 0, false
+}
+
+// retryable reports whether the failure of a request is transient. Network errors and 5xx responses are only retried
+// for the methods other than POST, as the request may have been executed, while a 429 response is always retried.
+func retryable(ctx context.Context, method string, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return method != http.MethodPost && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return method != http.MethodPost
+	default:
+		return false
+	}
+}
+
+// discard drains and closes the body of a response that is not returned, so its connection can be reused.
+func discard(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
@@ -1,15 +1,48 @@
 // Package config provides the configuration for the application. It contains the configuration schema and the default values for the configuration.
 package config
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// defaultRiskQuote is the asset the risk limits are valued in when none is configured.
+const defaultRiskQuote = "USDT"
+
+// The environments the Binance connector can target.
+const (
+	EnvironmentMainnet = "mainnet"
+	EnvironmentTestnet = "testnet"
+)
+
+// ErrMissingCredentials is returned when an API credential required for signed endpoints is not configured.
+var ErrMissingCredentials = errors.New("missing credentials")
 
 // Config represents the configuration for the application.
 type Config struct {
-	ConfigPath string    `mapstructure:"config_path"`
-	LogLevel   string    `mapstructure:"log_level"`
-	Stacktrace bool      `mapstructure:"stacktrace"`
-	Get        Get       `mapstructure:"get"`
-	Connector  Connector `mapstructure:"connector"`
+	ConfigPath string     `mapstructure:"config_path"`
+	LogLevel   string     `mapstructure:"log_level"`
+	Stacktrace bool       `mapstructure:"stacktrace"`
+	Get        Get        `mapstructure:"get"`
+	Symbols    Symbols    `mapstructure:"symbols"`
+	Order      Order      `mapstructure:"order"`
+	Klines     Klines     `mapstructure:"klines"`
+	Data       Data       `mapstructure:"data"`
+	Watch      Watch      `mapstructure:"watch"`
+	Book       Book       `mapstructure:"book"`
+	Time       Time       `mapstructure:"time"`
+	Portfolio  Portfolio  `mapstructure:"portfolio"`
+	Backtest   Backtest   `mapstructure:"backtest"`
+	Strategy   Strategy   `mapstructure:"strategy"`
+	Indicators Indicators `mapstructure:"indicators"`
+	Risk       Risk       `mapstructure:"risk"`
+	Retry      Retry      `mapstructure:"retry"`
+	Connector  Connector  `mapstructure:"connector"`
 }
 
 // Get represents the configuration for the get command.
@@ -17,12 +50,296 @@ type Get struct {
 	Timeout time.Duration `mapstructure:"timeout"`
 }
 
+// Symbols represents the configuration for the binance symbols command.
+type Symbols struct {
+	QuoteAsset string `mapstructure:"quote_asset"`
+	Status     string `mapstructure:"status"`
+}
+
+// Order represents the configuration for the order commands. Exchange is the exchange the order command targets,
+// the binance order commands always target Binance.
+type Order struct {
+	Exchange    string `mapstructure:"exchange"`
+	Symbol      string `mapstructure:"symbol"`
+	ID          int64  `mapstructure:"id"`
+	Side        string `mapstructure:"side"`
+	Type        string `mapstructure:"type"`
+	Quantity    string `mapstructure:"quantity"`
+	Price       string `mapstructure:"price"`
+	TimeInForce string `mapstructure:"time_in_force"`
+}
+
+// Klines represents the configuration for the binance klines command.
+// Start and End are RFC 3339 times or dates, and an empty Output writes to the standard output.
+type Klines struct {
+	Symbol   string `mapstructure:"symbol"`
+	Interval string `mapstructure:"interval"`
+	Start    string `mapstructure:"start"`
+	End      string `mapstructure:"end"`
+	Output   string `mapstructure:"output"`
+	Format   string `mapstructure:"format"`
+}
+
+// Data represents the configuration for the data commands, which manage the candle store at StorePath.
+// An empty Symbol selects every series of the store.
+type Data struct {
+	StorePath string `mapstructure:"store_path"`
+	Exchange  string `mapstructure:"exchange"`
+	Symbol    string `mapstructure:"symbol"`
+	Interval  string `mapstructure:"interval"`
+	Start     string `mapstructure:"start"`
+	End       string `mapstructure:"end"`
+	Backfill  bool   `mapstructure:"backfill"`
+	Format    string `mapstructure:"format"`
+}
+
+// Watch represents the configuration for the binance watch command.
+// Streams is a comma separated list of the stream types, and Interval is the interval of the kline stream.
+type Watch struct {
+	Streams  string `mapstructure:"streams"`
+	Interval string `mapstructure:"interval"`
+}
+
+// Book represents the configuration for the binance book command.
+// Quantity is the order quantity of the VWAP shown for both sides, no VWAP is shown when it is empty.
+type Book struct {
+	Depth    int           `mapstructure:"depth"`
+	Refresh  time.Duration `mapstructure:"refresh"`
+	Quantity string        `mapstructure:"quantity"`
+}
+
+// Time represents the configuration for the binance time command. Samples is the number of measures of the clock drift.
+type Time struct {
+	Samples int `mapstructure:"samples"`
+}
+
+// Portfolio represents the configuration for the portfolio command. Quote is the asset the holdings are valued in.
+type Portfolio struct {
+	Quote string `mapstructure:"quote"`
+}
+
+// Backtest represents the configuration for the backtest command. The candles of Symbol and Interval on Exchange are replayed from
+// the candle store between Start and End through Strategy, and the trade log is written to Output as CSV. Capital is the initial
+// balance of the Quote asset, and the fees and slippage are fractions of the traded amounts. Params are the comma separated
+// name=value parameters of the strategy.
+type Backtest struct {
+	Strategy string `mapstructure:"strategy"`
+	Params   string `mapstructure:"params"`
+	Exchange string `mapstructure:"exchange"`
+	Symbol   string `mapstructure:"symbol"`
+	Interval string `mapstructure:"interval"`
+	Start    string `mapstructure:"start"`
+	End      string `mapstructure:"end"`
+	Quote    string `mapstructure:"quote"`
+	Capital  string `mapstructure:"capital"`
+	MakerFee string `mapstructure:"maker_fee"`
+	TakerFee string `mapstructure:"taker_fee"`
+	Slippage string `mapstructure:"slippage"`
+	Output   string `mapstructure:"output"`
+}
+
+// Strategy represents the configuration for the strategy run command. The strategy Name is fed with the closed candles of Interval
+// and the trades of Symbol from the Binance streams, and trades on Exchange. Params are its comma separated name=value parameters,
+// and its orders are polled every Timer.
+type Strategy struct {
+	Name     string        `mapstructure:"name"`
+	Params   string        `mapstructure:"params"`
+	Exchange string        `mapstructure:"exchange"`
+	Symbol   string        `mapstructure:"symbol"`
+	Interval string        `mapstructure:"interval"`
+	Quote    string        `mapstructure:"quote"`
+	Timer    time.Duration `mapstructure:"timer"`
+}
+
+// Indicators represents the configuration for the indicators command. The indicators are computed over the last Limit closed
+// candles of Interval from Binance.
+type Indicators struct {
+	Interval string `mapstructure:"interval"`
+	Limit    int    `mapstructure:"limit"`
+}
+
+// Risk represents the configuration of the pre-trade risk checks of the orders. A zero limit disables its check, and an empty
+// AllowedSymbols allows every symbol. MaxOrderNotional and DailyLossLimit are amounts of the Quote asset, MaxPositions are the
+// maximum holdings of the assets given as comma separated ASSET=AMOUNT pairs, and PriceCollar is the maximum deviation of the limit
+// price from the last price, as a fraction of it. No order is placed while a file exists at KillSwitchPath, and the equity the daily
+// loss is measured from is kept in the file at StatePath.
+type Risk struct {
+	AllowedSymbols   []string `mapstructure:"allowed_symbols"`
+	MaxOrderNotional string   `mapstructure:"max_order_notional"`
+	MaxPositions     string   `mapstructure:"max_positions"`
+	MaxOpenOrders    int      `mapstructure:"max_open_orders"`
+	DailyLossLimit   string   `mapstructure:"daily_loss_limit"`
+	PriceCollar      string   `mapstructure:"price_collar"`
+	Quote            string   `mapstructure:"quote"`
+	KillSwitchPath   string   `mapstructure:"kill_switch_path"`
+	StatePath        string   `mapstructure:"state_path"`
+}
+
+// QuoteAsset returns the configured quote asset in upper case, defaulting to USDT when it is not set.
+func (r Risk) QuoteAsset() string {
+	if r.Quote == "" {
+		return defaultRiskQuote
+	}
+
+	return strings.ToUpper(r.Quote)
+}
+
+// Retry represents the configuration for the retries of the HTTP requests on network errors, 5xx and 429 responses.
+// A request is attempted at most MaxAttempts times, waiting an exponential backoff with jitter from InitialInterval up to MaxInterval,
+// or the Retry-After of the response, between attempts. No retry is made once MaxElapsedTime has passed since the first attempt.
+type Retry struct {
+	MaxAttempts     int           `mapstructure:"max_attempts"`
+	InitialInterval time.Duration `mapstructure:"initial_interval"`
+	MaxInterval     time.Duration `mapstructure:"max_interval"`
+	MaxElapsedTime  time.Duration `mapstructure:"max_elapsed_time"`
+}
+
 // Connector represents the configuration for the connector.
 type Connector struct {
-	Binance Binance `mapstructure:"binance"`
+	// Exchanges lists the names of the enabled exchanges. The exchanges not requiring an explicit opt-in are enabled when it is empty.
+	Exchanges []string `mapstructure:"exchanges"`
+	Binance   Binance  `mapstructure:"binance"`
+	Kraken    Kraken   `mapstructure:"kraken"`
+	Paper     Paper    `mapstructure:"paper"`
 }
 
 // Binance represents the configuration for the Binance connector.
+// Environment selects the mainnet or testnet endpoints, and the testnet credentials are used instead of the mainnet ones on testnet.
+// When DryRun is on, every order placement is routed to the test order endpoint, so no live order can be placed.
+// TimeSyncInterval is the interval at which long running commands sync the timestamps of the signed requests with the server time.
 type Binance struct {
-	BaseURL string `mapstructure:"base_url"`
+	Environment      string        `mapstructure:"environment"`
+	BaseURL          string        `mapstructure:"base_url"`
+	WSBaseURL        string        `mapstructure:"ws_base_url"`
+	APIKey           Secret        `mapstructure:"api_key"`
+	SecretKey        Secret        `mapstructure:"secret_key"`
+	TestnetAPIKey    Secret        `mapstructure:"testnet_api_key"`
+	TestnetSecretKey Secret        `mapstructure:"testnet_secret_key"`
+	SymbolsTTL       time.Duration `mapstructure:"symbols_ttl"`
+	TimeSyncInterval time.Duration `mapstructure:"time_sync_interval"`
+	DryRun           bool          `mapstructure:"dry_run"`
+}
+
+// Env returns the configured environment in lower case, defaulting to mainnet when it is not set.
+func (b Binance) Env() string {
+	if b.Environment == "" {
+		return EnvironmentMainnet
+	}
+
+	return strings.ToLower(b.Environment)
+}
+
+// Credentials returns the API key and secret key of the configured environment.
+func (b Binance) Credentials() (Secret, Secret) {
+	if b.Env() == EnvironmentTestnet {
+		return b.TestnetAPIKey, b.TestnetSecretKey
+	}
+
+	return b.APIKey, b.SecretKey
+}
+
+// ValidateCredentials returns an error if the API key or secret key required by signed endpoints is missing.
+func (b Binance) ValidateCredentials() error {
+	var missing []string
+
+	apiKey, secretKey := b.Credentials()
+	prefix := "connector.binance."
+
+	if b.Env() == EnvironmentTestnet {
+		prefix += "testnet_"
+	}
+
+	if apiKey == "" {
+		missing = append(missing, prefix+"api_key")
+	}
+
+	if secretKey == "" {
+		missing = append(missing, prefix+"secret_key")
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s must be set for signed binance endpoints", ErrMissingCredentials, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// Kraken represents the configuration for the Kraken connector. The public endpoints are used at BaseURL when it is set.
+type Kraken struct {
+	BaseURL   string `mapstructure:"base_url"`
+	APIKey    Secret `mapstructure:"api_key"`
+	SecretKey Secret `mapstructure:"secret_key"`
+}
+
+// ValidateCredentials returns an error if the API key or secret key required by private endpoints is missing.
+func (k Kraken) ValidateCredentials() error {
+	var missing []string
+
+	if k.APIKey == "" {
+		missing = append(missing, "connector.kraken.api_key")
+	}
+
+	if k.SecretKey == "" {
+		missing = append(missing, "connector.kraken.secret_key")
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s must be set for private kraken endpoints", ErrMissingCredentials, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// Paper represents the configuration for the paper trading exchange, which simulates the orders with virtual balances.
+// The orders are matched against the live order books of the Source exchange, or against the recorded order books of BooksPath when it is set.
+// The balances and orders are kept in StatePath between runs, or in memory when it is empty. Balances are the initial balances,
+// e.g. USDT=10000,BTC=0.5, and the fees are the fractions of the traded amounts charged in the received asset, e.g. 0.001.
+type Paper struct {
+	Source    string `mapstructure:"source"`
+	BooksPath string `mapstructure:"books_path"`
+	StatePath string `mapstructure:"state_path"`
+	Balances  string `mapstructure:"balances"`
+	MakerFee  string `mapstructure:"maker_fee"`
+	TakerFee  string `mapstructure:"taker_fee"`
+}
+
+// ParseTime parses the times of the configuration, given as RFC 3339 times or as dates in UTC.
+func ParseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected an RFC 3339 time or a date: %w", err)
+	}
+
+	return t, nil
+}
+
+// Secret is a string that is redacted when it is printed or logged.
+type Secret string
+
+// String returns the redacted value so that the secret never ends up in logs.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+
+	return redacted
+}
+
+// MarshalJSON returns the redacted value so that the secret never ends up in logs.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(s.String())
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling secret: %w", err)
+	}
+
+	return b, nil
+}
+
+// Value returns the unredacted value of the secret.
+func (s Secret) Value() string {
+	return string(s)
 }
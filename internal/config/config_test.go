@@ -0,0 +1,241 @@
+package config_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/twk/trader-b/internal/config"
+)
+
+func TestBinance_ValidateCredentials(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		binance config.Binance
+	}
+
+	type want struct {
+		err error
+	}
+
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"credentials set": {
+			args: args{binance: config.Binance{APIKey: "key", SecretKey: "secret"}},
+		},
+		"missing api key": {
+			args: args{binance: config.Binance{SecretKey: "secret"}},
+			want: want{err: errors.New("missing credentials: connector.binance.api_key must be set for signed binance endpoints")},
+		},
+		"missing both": {
+			args: args{binance: config.Binance{}},
+			want: want{err: errors.New("missing credentials: connector.binance.api_key, connector.binance.secret_key must be set for signed binance endpoints")},
+		},
+		"testnet credentials set": {
+			args: args{binance: config.Binance{Environment: "testnet", TestnetAPIKey: "key", TestnetSecretKey: "secret"}},
+		},
+		"testnet ignores mainnet credentials": {
+			args: args{binance: config.Binance{Environment: "testnet", APIKey: "key", SecretKey: "secret", TestnetAPIKey: "key"}},
+			want: want{err: errors.New("missing credentials: connector.binance.testnet_secret_key must be set for signed binance endpoints")},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.args.binance.ValidateCredentials()
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				assert.ErrorIs(t, err, config.ErrMissingCredentials)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestKraken_ValidateCredentials(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		kraken config.Kraken
+		want   error
+	}{
+		"credentials set": {
+			kraken: config.Kraken{APIKey: "key", SecretKey: "secret"},
+		},
+		"missing secret key": {
+			kraken: config.Kraken{APIKey: "key"},
+			want:   errors.New("missing credentials: connector.kraken.secret_key must be set for private kraken endpoints"),
+		},
+		"missing both": {
+			kraken: config.Kraken{},
+			want:   errors.New("missing credentials: connector.kraken.api_key, connector.kraken.secret_key must be set for private kraken endpoints"),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.kraken.ValidateCredentials()
+			if tt.want != nil {
+				assert.EqualError(t, err, tt.want.Error())
+				assert.ErrorIs(t, err, config.ErrMissingCredentials)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBinance_Credentials(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		binance config.Binance
+	}
+
+	type want struct {
+		env       string
+		apiKey    config.Secret
+		secretKey config.Secret
+	}
+
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"default is mainnet": {
+			args: args{binance: config.Binance{APIKey: "key", SecretKey: "secret", TestnetAPIKey: "test-key", TestnetSecretKey: "test-secret"}},
+			want: want{env: "mainnet", apiKey: "key", secretKey: "secret"},
+		},
+		"testnet": {
+			args: args{binance: config.Binance{Environment: "Testnet", APIKey: "key", SecretKey: "secret", TestnetAPIKey: "test-key", TestnetSecretKey: "test-secret"}},
+			want: want{env: "testnet", apiKey: "test-key", secretKey: "test-secret"},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			apiKey, secretKey := tt.args.binance.Credentials()
+
+			assert.Equal(t, tt.want.env, tt.args.binance.Env())
+			assert.Equal(t, tt.want.apiKey, apiKey)
+			assert.Equal(t, tt.want.secretKey, secretKey)
+		})
+	}
+}
+
+func TestRisk_QuoteAsset(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		risk config.Risk
+		want string
+	}{
+		"default is USDT": {risk: config.Risk{}, want: "USDT"},
+		"upper case":      {risk: config.Risk{Quote: "fdusd"}, want: "FDUSD"},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.risk.QuoteAsset())
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		time time.Time
+		err  error
+	}
+
+	tests := map[string]struct {
+		value string
+		want  want
+	}{
+		"date":     {value: "2024-01-02", want: want{time: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}},
+		"RFC 3339": {value: "2024-01-02T03:04:05Z", want: want{time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}},
+		"invalid": {
+			value: "yesterday",
+			want:  want{err: errors.New(`expected an RFC 3339 time or a date: parsing time "yesterday" as "2006-01-02": cannot parse "yesterday" as "2006"`)},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := config.ParseTime(tt.value)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, tt.want.time.Equal(got))
+		})
+	}
+}
+
+func TestSecret_Redaction(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Connector: config.Connector{
+			Binance: config.Binance{APIKey: "my-api-key", SecretKey: "my-secret-key"},
+		},
+	}
+
+	b, err := json.Marshal(cfg)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "my-api-key")
+	assert.NotContains(t, string(b), "my-secret-key")
+
+	assert.NotContains(t, fmt.Sprintf("%v", cfg.Connector.Binance), "my-secret-key")
+	assert.Equal(t, "my-secret-key", cfg.Connector.Binance.SecretKey.Value())
+	assert.Equal(t, "", config.Secret("").String())
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	zap.New(core).Info("config", zap.Any("config", cfg))
+
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	for _, entry := range logs.All() {
+		buf, err := enc.EncodeEntry(entry.Entry, entry.Context)
+		assert.NoError(t, err)
+		assert.NotContains(t, buf.String(), "my-secret-key")
+		assert.NotContains(t, buf.String(), "my-api-key")
+	}
+}
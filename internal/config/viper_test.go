@@ -3,6 +3,7 @@ package config_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -37,6 +38,23 @@ func TestViper_BuildConfig(t *testing.T) {
 					Get: config.Get{
 						Timeout: 5000000000,
 					},
+					Retry: config.Retry{
+						MaxAttempts:     4,
+						InitialInterval: 100 * time.Millisecond,
+						MaxInterval:     2 * time.Second,
+						MaxElapsedTime:  10 * time.Second,
+					},
+					Connector: config.Connector{
+						Binance: config.Binance{
+							Environment:      "testnet",
+							BaseURL:          "https://testnet.binance.vision",
+							APIKey:           "test-api-key",
+							SecretKey:        "test-secret-key",
+							TestnetAPIKey:    "testnet-api-key",
+							TestnetSecretKey: "testnet-secret-key",
+							DryRun:           true,
+						},
+					},
 				},
 			},
 		},
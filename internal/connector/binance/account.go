@@ -3,6 +3,7 @@ package binance
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	binance_connector "github.com/binance/binance-connector-go"
 )
@@ -26,3 +27,28 @@ func (s *AccountService) GetAccount(ctx context.Context) (*binance_connector.Acc
 
 	return res, nil
 }
+
+// NonZeroBalances returns the balances that have a non-zero free or locked amount.
+func NonZeroBalances(balances []binance_connector.Balance) ([]binance_connector.Balance, error) {
+	res := make([]binance_connector.Balance, 0, len(balances))
+
+	for _, b := range balances {
+		free, err := strconv.ParseFloat(b.Free, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing free amount of %s: %w", b.Asset, err)
+		}
+
+		locked, err := strconv.ParseFloat(b.Locked, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing locked amount of %s: %w", b.Asset, err)
+		}
+
+		if free == 0 && locked == 0 {
+			continue
+		}
+
+		res = append(res, b)
+	}
+
+	return res, nil
+}
@@ -68,3 +68,79 @@ func TestAccountService_GetAccount(t *testing.T) {
 		})
 	}
 }
+
+func TestNonZeroBalances(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		balances []binance_connector.Balance
+	}
+
+	type want struct {
+		res []binance_connector.Balance
+		err error
+	}
+
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"filters zero balances": {
+			args: args{
+				balances: []binance_connector.Balance{
+					{Asset: "BTC", Free: "0.50000000", Locked: "0.00000000"},
+					{Asset: "ETH", Free: "0.00000000", Locked: "0.00000000"},
+					{Asset: "BNB", Free: "0.00000000", Locked: "1.00000000"},
+				},
+			},
+			want: want{
+				res: []binance_connector.Balance{
+					{Asset: "BTC", Free: "0.50000000", Locked: "0.00000000"},
+					{Asset: "BNB", Free: "0.00000000", Locked: "1.00000000"},
+				},
+			},
+		},
+		"empty balances": {
+			args: args{},
+			want: want{
+				res: []binance_connector.Balance{},
+			},
+		},
+		"invalid free amount": {
+			args: args{
+				balances: []binance_connector.Balance{
+					{Asset: "BTC", Free: "abc", Locked: "0"},
+				},
+			},
+			want: want{
+				err: errors.New(`error parsing free amount of BTC: strconv.ParseFloat: parsing "abc": invalid syntax`),
+			},
+		},
+		"invalid locked amount": {
+			args: args{
+				balances: []binance_connector.Balance{
+					{Asset: "BTC", Free: "0", Locked: "abc"},
+				},
+			},
+			want: want{
+				err: errors.New(`error parsing locked amount of BTC: strconv.ParseFloat: parsing "abc": invalid syntax`),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := binance.NonZeroBalances(tt.args.balances)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.Equal(t, tt.want.res, res)
+		})
+	}
+}
@@ -3,8 +3,25 @@ package binance
 import (
 	"context"
 	"fmt"
+	"time"
 
 	binance_connector "github.com/binance/binance-connector-go"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// Name is the name of the Binance exchange. It matches the key of the Binance connector configuration.
+const Name = "binance"
+
+// defaultTimeInForce is the time in force of limit orders placed without one.
+const defaultTimeInForce = "GTC"
+
+// OrderStatusDryRun is the status of the orders returned by PlaceOrder in dry-run mode. They were validated by Binance but never placed.
+const OrderStatusDryRun = "DRY_RUN"
+
+var (
+	_ connector.Exchange = (*Service)(nil)
+	_ connector.Trader   = (*Service)(nil)
 )
 
 // AccountClient is a client for interacting with the Binance account.
@@ -14,23 +31,163 @@ type AccountClient interface {
 
 // ExchangeInfoClient is a client for interacting with the Binance exchange info.
 type ExchangeInfoClient interface {
-	Do(ctx context.Context, opts ...binance_connector.RequestOption) (res *binance_connector.ExchangeInfoResponse, err error)
+	Do(ctx context.Context, opts ...binance_connector.RequestOption) (res *ExchangeInfoResponse, err error)
+}
+
+// TickerPriceClient is a client for interacting with the Binance ticker price.
+type TickerPriceClient interface {
+	Do(ctx context.Context, opts ...binance_connector.RequestOption) (res *binance_connector.TickerPriceResponse, err error)
+}
+
+// OrderBookClient is a client for interacting with the Binance order book.
+type OrderBookClient interface {
+	Do(ctx context.Context, opts ...binance_connector.RequestOption) (res *binance_connector.OrderBookResponse, err error)
+}
+
+// RecentTradesClient is a client for interacting with the Binance recent trades.
+type RecentTradesClient interface {
+	Do(ctx context.Context, opts ...binance_connector.RequestOption) (res []*binance_connector.RecentTradesListResponse, err error)
+}
+
+// CreateOrderClient is a client for placing Binance orders.
+// The response is one of *CreateOrderResponseACK, *CreateOrderResponseRESULT or *CreateOrderResponseFULL.
+type CreateOrderClient interface {
+	Do(ctx context.Context, opts ...binance_connector.RequestOption) (res interface{}, err error)
+}
+
+// TestOrderClient is a client for validating Binance orders without placing them.
+type TestOrderClient interface {
+	Do(ctx context.Context, opts ...binance_connector.RequestOption) (res *binance_connector.AccountOrderBookResponse, err error)
+}
+
+// CancelOrderClient is a client for cancelling Binance orders.
+type CancelOrderClient interface {
+	Do(ctx context.Context, opts ...binance_connector.RequestOption) (res *binance_connector.CancelOrderResponse, err error)
+}
+
+// GetOrderClient is a client for querying Binance orders.
+type GetOrderClient interface {
+	Do(ctx context.Context, opts ...binance_connector.RequestOption) (res *binance_connector.GetOrderResponse, err error)
+}
+
+// OpenOrdersClient is a client for listing the open Binance orders.
+type OpenOrdersClient interface {
+	Do(ctx context.Context, opts ...binance_connector.RequestOption) (res []*binance_connector.NewOpenOrdersResponse, err error)
+}
+
+// KlinesClient is a client for getting the Binance klines.
+type KlinesClient interface {
+	Do(ctx context.Context, opts ...binance_connector.RequestOption) (res []*binance_connector.KlinesResponse, err error)
+}
+
+// ListenKeyClient is a client for creating the listen keys of the Binance user data stream.
+type ListenKeyClient interface {
+	Do(ctx context.Context, opts ...binance_connector.RequestOption) (listenKey string, err error)
+}
+
+// UserStreamClient is a client for keeping alive or closing a listen key of the Binance user data stream.
+type UserStreamClient interface {
+	Do(ctx context.Context, opts ...binance_connector.RequestOption) (err error)
+}
+
+// ServerTimeClient is a client for getting the time of the Binance server.
+type ServerTimeClient interface {
+	Do(ctx context.Context, opts ...binance_connector.RequestOption) (res *binance_connector.ServerTimeResponse, err error)
 }
 
 // Client is a client for interacting with Binance.
 type Client interface {
 	NewGetAccountService() AccountClient
 	NewExchangeInfoService() ExchangeInfoClient
+	NewTickerPriceService(symbol string) TickerPriceClient
+	NewOrderBookService(symbol string, limit int) OrderBookClient
+	NewRecentTradesService(symbol string, limit int) RecentTradesClient
+	NewCreateOrderService(req connector.OrderRequest) CreateOrderClient
+	NewTestOrderService(req connector.OrderRequest) TestOrderClient
+	NewCancelOrderService(symbol string, orderID int64) CancelOrderClient
+	NewGetOrderService(symbol string, orderID int64) GetOrderClient
+	NewOpenOrdersService(symbol string) OpenOrdersClient
+	NewKlinesService(symbol, interval string, start, end time.Time, limit int) KlinesClient
+	NewCreateListenKeyService() ListenKeyClient
+	NewPingUserStreamService(listenKey string) UserStreamClient
+	NewCloseUserStreamService(listenKey string) UserStreamClient
+	NewServerTimeService() ServerTimeClient
 }
 
 // Service is a service for interacting with Binance.
 type Service struct {
-	client Client
+	client   Client
+	dryRun   bool
+	limiter  *Limiter
+	timeSync *TimeSync
+}
+
+// Option configures the service.
+type Option func(*Service)
+
+// WithDryRun routes every order placement to the Binance test order endpoint, so no live order can be placed.
+func WithDryRun(dryRun bool) Option {
+	return func(s *Service) {
+		s.dryRun = dryRun
+	}
+}
+
+// WithLimiter sets the limiter in front of the client, whose usage is reported by RateLimitUsage.
+func WithLimiter(l *Limiter) Option {
+	return func(s *Service) {
+		s.limiter = l
+	}
+}
+
+// WithTimeSync sets the time sync correcting the timestamps of the signed requests of the client, returned by TimeSync.
+func WithTimeSync(t *TimeSync) Option {
+	return func(s *Service) {
+		s.timeSync = t
+	}
 }
 
 // NewService creates a new service.
-func NewService(client Client) *Service {
-	return &Service{client: client}
+func NewService(client Client, opts ...Option) *Service {
+	s := &Service{client: client}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// DryRun reports whether the service is in dry-run mode.
+func (s *Service) DryRun() bool {
+	return s.dryRun
+}
+
+// RateLimitUsage returns the usage of the Binance rate limits, nil when the service has no limiter.
+func (s *Service) RateLimitUsage() []RateLimitUsage {
+	if s.limiter == nil {
+		return nil
+	}
+
+	return s.limiter.Usage()
+}
+
+// TimeSync returns the time sync of the service, nil when the timestamps of the signed requests are not corrected.
+func (s *Service) TimeSync() *TimeSync {
+	return s.timeSync
+}
+
+// GetServerTime gets the time of the Binance server.
+func (s *Service) GetServerTime(ctx context.Context) (time.Time, error) {
+	res, err := s.client.NewServerTimeService().Do(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error getting server time: %w", err)
+	}
+
+	return time.UnixMilli(int64(res.ServerTime)).UTC(), nil
+}
+
+// Name returns the name of the exchange.
+func (s *Service) Name() string {
+	return Name
 }
 
 // GetAccount gets the account information from Binance.
@@ -46,7 +203,7 @@ func (s *Service) GetAccount(ctx context.Context) (*binance_connector.AccountRes
 }
 
 // GetExchangeInfo gets the exchange info from Binance.
-func (s *Service) GetExchangeInfo(ctx context.Context) (*binance_connector.ExchangeInfoResponse, error) {
+func (s *Service) GetExchangeInfo(ctx context.Context) (*ExchangeInfoResponse, error) {
 	exchangeInfoService := s.client.NewExchangeInfoService()
 
 	res, err := exchangeInfoService.Do(ctx)
@@ -56,3 +213,150 @@ func (s *Service) GetExchangeInfo(ctx context.Context) (*binance_connector.Excha
 
 	return res, nil
 }
+
+// GetBalances gets the balances of the Binance account.
+func (s *Service) GetBalances(ctx context.Context) ([]connector.Balance, error) {
+	res, err := s.GetAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBalances(res.Balances)
+}
+
+// GetTickers gets the latest price of the given symbols from Binance.
+// The connector only supports single symbol requests, so one request is made per symbol.
+func (s *Service) GetTickers(ctx context.Context, symbols []string) ([]connector.Ticker, error) {
+	tickers := make([]connector.Ticker, 0, len(symbols))
+
+	for _, symbol := range symbols {
+		res, err := s.client.NewTickerPriceService(symbol).Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting ticker price of %s: %w", symbol, err)
+		}
+
+		t, err := toTicker(res)
+		if err != nil {
+			return nil, err
+		}
+
+		tickers = append(tickers, t)
+	}
+
+	return tickers, nil
+}
+
+// GetOrderBook gets the order book of the symbol from Binance.
+func (s *Service) GetOrderBook(ctx context.Context, symbol string, limit int) (*connector.OrderBook, error) {
+	res, err := s.client.NewOrderBookService(symbol, limit).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting order book of %s: %w", symbol, err)
+	}
+
+	return toOrderBook(symbol, res)
+}
+
+// GetTrades gets the most recent trades of the symbol from Binance.
+func (s *Service) GetTrades(ctx context.Context, symbol string, limit int) ([]connector.Trade, error) {
+	res, err := s.client.NewRecentTradesService(symbol, limit).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting trades of %s: %w", symbol, err)
+	}
+
+	return toTrades(symbol, res)
+}
+
+// GetSymbols gets the symbols listed on Binance.
+func (s *Service) GetSymbols(ctx context.Context) ([]connector.Symbol, error) {
+	res, err := s.GetExchangeInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSymbols(res)
+}
+
+// PlaceOrder places a new order on Binance. Limit orders without a time in force are placed as GTC.
+// In dry-run mode the order is only validated by Binance and returned with the OrderStatusDryRun status.
+func (s *Service) PlaceOrder(ctx context.Context, req connector.OrderRequest) (*connector.Order, error) {
+	if req.Type == connector.OrderTypeLimit && req.TimeInForce == "" {
+		req.TimeInForce = defaultTimeInForce
+	}
+
+	if s.dryRun {
+		return s.placeTestOrder(ctx, req)
+	}
+
+	res, err := s.client.NewCreateOrderService(req).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error placing order on %s: %w", req.Symbol, err)
+	}
+
+	return toCreatedOrder(res)
+}
+
+// CancelOrder cancels an open order on Binance.
+func (s *Service) CancelOrder(ctx context.Context, symbol string, orderID int64) (*connector.Order, error) {
+	res, err := s.client.NewCancelOrderService(symbol, orderID).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error cancelling order %d on %s: %w", orderID, symbol, err)
+	}
+
+	return toOrder(orderFields{
+		id: res.OrderId, clientOrderID: res.ClientOrderId, symbol: res.Symbol, side: res.Side, orderType: res.Type,
+		status: res.Status, price: res.Price, quantity: res.OrigQty, executedQuantity: res.ExecutedQty,
+	})
+}
+
+// GetOrder gets an order from Binance.
+func (s *Service) GetOrder(ctx context.Context, symbol string, orderID int64) (*connector.Order, error) {
+	res, err := s.client.NewGetOrderService(symbol, orderID).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting order %d on %s: %w", orderID, symbol, err)
+	}
+
+	return toOrder(orderFields{
+		id: res.OrderId, clientOrderID: res.ClientOrderId, symbol: res.Symbol, side: res.Side, orderType: res.Type,
+		status: res.Status, price: res.Price, quantity: res.OrigQty, executedQuantity: res.ExecutedQty, time: res.Time,
+	})
+}
+
+// ListOpenOrders lists the open orders of the symbol on Binance, or of every symbol when it is empty.
+func (s *Service) ListOpenOrders(ctx context.Context, symbol string) ([]connector.Order, error) {
+	res, err := s.client.NewOpenOrdersService(symbol).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing open orders: %w", err)
+	}
+
+	orders := make([]connector.Order, 0, len(res))
+
+	for _, o := range res {
+		order, err := toOrder(orderFields{
+			id: o.OrderId, clientOrderID: o.ClientOrderId, symbol: o.Symbol, side: o.Side, orderType: o.Type,
+			status: o.Status, price: o.Price, quantity: o.OrigQty, executedQuantity: o.ExecutedQty, time: o.Time,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, *order)
+	}
+
+	return orders, nil
+}
+
+func (s *Service) placeTestOrder(ctx context.Context, req connector.OrderRequest) (*connector.Order, error) {
+	if _, err := s.client.NewTestOrderService(req).Do(ctx); err != nil {
+		return nil, fmt.Errorf("error placing test order on %s: %w", req.Symbol, err)
+	}
+
+	return &connector.Order{
+		ClientOrderID: req.ClientOrderID,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		Status:        OrderStatusDryRun,
+		Price:         req.Price,
+		Quantity:      req.Quantity,
+	}, nil
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"testing"
+	"time"
 
 	binance_connector "github.com/binance/binance-connector-go"
 	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/twk/trader-b/internal/connector"
 	"github.com/twk/trader-b/internal/connector/binance"
 	mock_binance "github.com/twk/trader-b/internal/connector/binance/mocks"
 )
@@ -79,7 +83,7 @@ func TestService_GetExchangeInfo_ErrorFromDo(t *testing.T) {
 	}
 
 	type want struct {
-		res *binance_connector.ExchangeInfoResponse
+		res *binance.ExchangeInfoResponse
 		err error
 	}
 
@@ -101,12 +105,12 @@ func TestService_GetExchangeInfo_ErrorFromDo(t *testing.T) {
 		"Success": {
 			fields: fields{
 				mockOperation: func(client *mock_binance.MockClient, exchangeInfoClient *mock_binance.MockExchangeInfoClient) {
-					exchangeInfoClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.ExchangeInfoResponse{}, nil)
+					exchangeInfoClient.EXPECT().Do(gomock.Any()).Return(&binance.ExchangeInfoResponse{}, nil)
 					client.EXPECT().NewExchangeInfoService().Return(exchangeInfoClient)
 				},
 			},
 			want: want{
-				res: &binance_connector.ExchangeInfoResponse{},
+				res: &binance.ExchangeInfoResponse{},
 			},
 		},
 	}
@@ -131,3 +135,470 @@ func TestService_GetExchangeInfo_ErrorFromDo(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Name(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	service := binance.NewService(mock_binance.NewMockClient(ctrl))
+
+	assert.Equal(t, binance.Name, service.Name())
+}
+
+func TestService_GetBalances(t *testing.T) {
+	type fields struct {
+		mockOperation func(client *mock_binance.MockClient, accountClient *mock_binance.MockAccountClient)
+	}
+
+	type want struct {
+		res []connector.Balance
+		err error
+	}
+
+	tests := map[string]struct {
+		fields fields
+		want   want
+	}{
+		"Success": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, accountClient *mock_binance.MockAccountClient) {
+					accountClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.AccountResponse{
+						Balances: []binance_connector.Balance{{Asset: "BTC", Free: "0.50000000", Locked: "0.10000000"}},
+					}, nil)
+					client.EXPECT().NewGetAccountService().Return(accountClient)
+				},
+			},
+			want: want{
+				res: []connector.Balance{
+					{Asset: "BTC", Free: decimal.RequireFromString("0.50000000"), Locked: decimal.RequireFromString("0.10000000")},
+				},
+			},
+		},
+		"GetAccountError": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, accountClient *mock_binance.MockAccountClient) {
+					accountClient.EXPECT().Do(gomock.Any()).Return(nil, errors.New("do error"))
+					client.EXPECT().NewGetAccountService().Return(accountClient)
+				},
+			},
+			want: want{
+				err: errors.New("error getting account: do error"),
+			},
+		},
+		"InvalidFree": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, accountClient *mock_binance.MockAccountClient) {
+					accountClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.AccountResponse{
+						Balances: []binance_connector.Balance{{Asset: "BTC", Free: "abc", Locked: "0"}},
+					}, nil)
+					client.EXPECT().NewGetAccountService().Return(accountClient)
+				},
+			},
+			want: want{
+				err: errors.New("error parsing free amount of BTC: can't convert abc to decimal"),
+			},
+		},
+		"InvalidLocked": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, accountClient *mock_binance.MockAccountClient) {
+					accountClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.AccountResponse{
+						Balances: []binance_connector.Balance{{Asset: "BTC", Free: "0", Locked: "abc"}},
+					}, nil)
+					client.EXPECT().NewGetAccountService().Return(accountClient)
+				},
+			},
+			want: want{
+				err: errors.New("error parsing locked amount of BTC: can't convert abc to decimal"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock_binance.NewMockClient(ctrl)
+			mockAccountClient := mock_binance.NewMockAccountClient(ctrl)
+			tt.fields.mockOperation(mockClient, mockAccountClient)
+			service := binance.NewService(mockClient)
+
+			res, err := service.GetBalances(context.Background())
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.Equal(t, tt.want.res, res)
+		})
+	}
+}
+
+func TestService_GetTickers(t *testing.T) {
+	type fields struct {
+		mockOperation func(client *mock_binance.MockClient, tickerClient *mock_binance.MockTickerPriceClient)
+	}
+
+	type want struct {
+		res []connector.Ticker
+		err error
+	}
+
+	tests := map[string]struct {
+		fields fields
+		want   want
+	}{
+		"Success": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, tickerClient *mock_binance.MockTickerPriceClient) {
+					gomock.InOrder(
+						client.EXPECT().NewTickerPriceService("BTCUSDT").Return(tickerClient),
+						tickerClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.TickerPriceResponse{Symbol: "BTCUSDT", Price: "65000.01"}, nil),
+						client.EXPECT().NewTickerPriceService("ETHUSDT").Return(tickerClient),
+						tickerClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.TickerPriceResponse{Symbol: "ETHUSDT", Price: "3500.5"}, nil),
+					)
+				},
+			},
+			want: want{
+				res: []connector.Ticker{
+					{Symbol: "BTCUSDT", Price: decimal.RequireFromString("65000.01")},
+					{Symbol: "ETHUSDT", Price: decimal.RequireFromString("3500.5")},
+				},
+			},
+		},
+		"DoError": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, tickerClient *mock_binance.MockTickerPriceClient) {
+					client.EXPECT().NewTickerPriceService("BTCUSDT").Return(tickerClient)
+					tickerClient.EXPECT().Do(gomock.Any()).Return(nil, errors.New("do error"))
+				},
+			},
+			want: want{
+				err: errors.New("error getting ticker price of BTCUSDT: do error"),
+			},
+		},
+		"InvalidPrice": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, tickerClient *mock_binance.MockTickerPriceClient) {
+					client.EXPECT().NewTickerPriceService("BTCUSDT").Return(tickerClient)
+					tickerClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.TickerPriceResponse{Symbol: "BTCUSDT", Price: "abc"}, nil)
+				},
+			},
+			want: want{
+				err: errors.New("error parsing price of BTCUSDT: can't convert abc to decimal"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock_binance.NewMockClient(ctrl)
+			mockTickerClient := mock_binance.NewMockTickerPriceClient(ctrl)
+			tt.fields.mockOperation(mockClient, mockTickerClient)
+			service := binance.NewService(mockClient)
+
+			res, err := service.GetTickers(context.Background(), []string{"BTCUSDT", "ETHUSDT"})
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.Equal(t, tt.want.res, res)
+		})
+	}
+}
+
+func TestService_GetOrderBook(t *testing.T) {
+	type fields struct {
+		mockOperation func(client *mock_binance.MockClient, orderBookClient *mock_binance.MockOrderBookClient)
+	}
+
+	type want struct {
+		res *connector.OrderBook
+		err error
+	}
+
+	tests := map[string]struct {
+		fields fields
+		want   want
+	}{
+		"Success": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, orderBookClient *mock_binance.MockOrderBookClient) {
+					client.EXPECT().NewOrderBookService("BTCUSDT", 5).Return(orderBookClient)
+					orderBookClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.OrderBookResponse{
+						LastUpdateId: 42,
+						Bids:         [][]*big.Float{{big.NewFloat(100.5), big.NewFloat(2)}},
+						Asks:         [][]*big.Float{{big.NewFloat(101), big.NewFloat(0.5)}},
+					}, nil)
+				},
+			},
+			want: want{
+				res: &connector.OrderBook{
+					Symbol:       "BTCUSDT",
+					LastUpdateID: 42,
+					Bids:         []connector.PriceLevel{{Price: decimal.RequireFromString("100.5"), Quantity: decimal.RequireFromString("2")}},
+					Asks:         []connector.PriceLevel{{Price: decimal.RequireFromString("101"), Quantity: decimal.RequireFromString("0.5")}},
+				},
+			},
+		},
+		"DoError": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, orderBookClient *mock_binance.MockOrderBookClient) {
+					client.EXPECT().NewOrderBookService("BTCUSDT", 5).Return(orderBookClient)
+					orderBookClient.EXPECT().Do(gomock.Any()).Return(nil, errors.New("do error"))
+				},
+			},
+			want: want{
+				err: errors.New("error getting order book of BTCUSDT: do error"),
+			},
+		},
+		"InvalidBid": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, orderBookClient *mock_binance.MockOrderBookClient) {
+					client.EXPECT().NewOrderBookService("BTCUSDT", 5).Return(orderBookClient)
+					orderBookClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.OrderBookResponse{
+						Bids: [][]*big.Float{{big.NewFloat(100.5)}},
+					}, nil)
+				},
+			},
+			want: want{
+				err: errors.New("error parsing bids of BTCUSDT: invalid price level: [100.5]"),
+			},
+		},
+		"InvalidAsk": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, orderBookClient *mock_binance.MockOrderBookClient) {
+					client.EXPECT().NewOrderBookService("BTCUSDT", 5).Return(orderBookClient)
+					orderBookClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.OrderBookResponse{
+						Asks: [][]*big.Float{{nil, nil}},
+					}, nil)
+				},
+			},
+			want: want{
+				err: errors.New("error parsing asks of BTCUSDT: invalid price level: [<nil> <nil>]"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock_binance.NewMockClient(ctrl)
+			mockOrderBookClient := mock_binance.NewMockOrderBookClient(ctrl)
+			tt.fields.mockOperation(mockClient, mockOrderBookClient)
+			service := binance.NewService(mockClient)
+
+			res, err := service.GetOrderBook(context.Background(), "BTCUSDT", 5)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.Equal(t, tt.want.res, res)
+		})
+	}
+}
+
+func TestService_GetTrades(t *testing.T) {
+	type fields struct {
+		mockOperation func(client *mock_binance.MockClient, tradesClient *mock_binance.MockRecentTradesClient)
+	}
+
+	type want struct {
+		res []connector.Trade
+		err error
+	}
+
+	tests := map[string]struct {
+		fields fields
+		want   want
+	}{
+		"Success": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, tradesClient *mock_binance.MockRecentTradesClient) {
+					client.EXPECT().NewRecentTradesService("BTCUSDT", 1).Return(tradesClient)
+					tradesClient.EXPECT().Do(gomock.Any()).Return([]*binance_connector.RecentTradesListResponse{
+						{Id: 7, Price: "100.5", Qty: "0.01", Time: 1700000000000, IsBuyerMaker: true},
+					}, nil)
+				},
+			},
+			want: want{
+				res: []connector.Trade{
+					{
+						ID:           7,
+						Symbol:       "BTCUSDT",
+						Price:        decimal.RequireFromString("100.5"),
+						Quantity:     decimal.RequireFromString("0.01"),
+						Time:         time.UnixMilli(1700000000000),
+						IsBuyerMaker: true,
+					},
+				},
+			},
+		},
+		"DoError": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, tradesClient *mock_binance.MockRecentTradesClient) {
+					client.EXPECT().NewRecentTradesService("BTCUSDT", 1).Return(tradesClient)
+					tradesClient.EXPECT().Do(gomock.Any()).Return(nil, errors.New("do error"))
+				},
+			},
+			want: want{
+				err: errors.New("error getting trades of BTCUSDT: do error"),
+			},
+		},
+		"InvalidQuantity": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, tradesClient *mock_binance.MockRecentTradesClient) {
+					client.EXPECT().NewRecentTradesService("BTCUSDT", 1).Return(tradesClient)
+					tradesClient.EXPECT().Do(gomock.Any()).Return([]*binance_connector.RecentTradesListResponse{
+						{Id: 7, Price: "100.5", Qty: "abc"},
+					}, nil)
+				},
+			},
+			want: want{
+				err: errors.New("error parsing quantity of trade 7: can't convert abc to decimal"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock_binance.NewMockClient(ctrl)
+			mockTradesClient := mock_binance.NewMockRecentTradesClient(ctrl)
+			tt.fields.mockOperation(mockClient, mockTradesClient)
+			service := binance.NewService(mockClient)
+
+			res, err := service.GetTrades(context.Background(), "BTCUSDT", 1)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.Equal(t, tt.want.res, res)
+		})
+	}
+}
+
+func TestService_GetSymbols(t *testing.T) {
+	type fields struct {
+		mockOperation func(client *mock_binance.MockClient, exchangeInfoClient *mock_binance.MockExchangeInfoClient)
+	}
+
+	type want struct {
+		res []connector.Symbol
+		err error
+	}
+
+	tests := map[string]struct {
+		fields fields
+		want   want
+	}{
+		"Success": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, exchangeInfoClient *mock_binance.MockExchangeInfoClient) {
+					exchangeInfoClient.EXPECT().Do(gomock.Any()).Return(&binance.ExchangeInfoResponse{
+						Symbols: []*binance.SymbolInfo{
+							{
+								Symbol: "BTCUSDT", Status: "TRADING", BaseAsset: "BTC", QuoteAsset: "USDT",
+								Filters: []*binance.SymbolFilter{
+									{FilterType: "PRICE_FILTER", MinPrice: "0.01000000", MaxPrice: "1000000.00000000", TickSize: "0.01000000"},
+									{FilterType: "LOT_SIZE", MinQty: "0.00001000", MaxQty: "9000.00000000", StepSize: "0.00001000"},
+									{FilterType: "MARKET_LOT_SIZE", MinQty: "0.00000000", MaxQty: "120.00000000", StepSize: "0.00000000"},
+									{FilterType: "MIN_NOTIONAL", MinNotional: "10.00000000", ApplyToMarket: true},
+									{FilterType: "NOTIONAL", MinNotional: "5.00000000", MaxNotional: "9000000.00000000", ApplyMinToMarket: true},
+									{FilterType: "ICEBERG_PARTS"},
+									nil,
+								},
+							},
+							nil,
+						},
+					}, nil)
+					client.EXPECT().NewExchangeInfoService().Return(exchangeInfoClient)
+				},
+			},
+			want: want{
+				res: []connector.Symbol{
+					{
+						Name: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Status: "TRADING",
+						Filters: connector.SymbolFilters{
+							MinPrice:       decimal.RequireFromString("0.01000000"),
+							MaxPrice:       decimal.RequireFromString("1000000.00000000"),
+							TickSize:       decimal.RequireFromString("0.01000000"),
+							MinQty:         decimal.RequireFromString("0.00001000"),
+							MaxQty:         decimal.RequireFromString("9000.00000000"),
+							StepSize:       decimal.RequireFromString("0.00001000"),
+							MarketMinQty:   decimal.RequireFromString("0.00000000"),
+							MarketMaxQty:   decimal.RequireFromString("120.00000000"),
+							MarketStepSize: decimal.RequireFromString("0.00000000"),
+							MinNotional:    decimal.RequireFromString("10.00000000"),
+							MaxNotional:    decimal.RequireFromString("9000000.00000000"),
+							NotionalFilters: []connector.NotionalFilter{
+								{Name: "MIN_NOTIONAL", MinNotional: decimal.RequireFromString("10.00000000"), ApplyMinToMarket: true},
+								{
+									Name: "NOTIONAL", MinNotional: decimal.RequireFromString("5.00000000"), MaxNotional: decimal.RequireFromString("9000000.00000000"),
+									ApplyMinToMarket: true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"InvalidFilter": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, exchangeInfoClient *mock_binance.MockExchangeInfoClient) {
+					exchangeInfoClient.EXPECT().Do(gomock.Any()).Return(&binance.ExchangeInfoResponse{
+						Symbols: []*binance.SymbolInfo{
+							{Symbol: "BTCUSDT", Filters: []*binance.SymbolFilter{{FilterType: "MIN_NOTIONAL", MinNotional: "abc"}}},
+						},
+					}, nil)
+					client.EXPECT().NewExchangeInfoService().Return(exchangeInfoClient)
+				},
+			},
+			want: want{
+				err: errors.New("error parsing filters of BTCUSDT: error parsing MIN_NOTIONAL: error parsing decimal: can't convert abc to decimal"),
+			},
+		},
+		"DoError": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, exchangeInfoClient *mock_binance.MockExchangeInfoClient) {
+					exchangeInfoClient.EXPECT().Do(gomock.Any()).Return(nil, errors.New("do error"))
+					client.EXPECT().NewExchangeInfoService().Return(exchangeInfoClient)
+				},
+			},
+			want: want{
+				err: errors.New("error getting exchange info: do error"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock_binance.NewMockClient(ctrl)
+			mockExchangeInfoClient := mock_binance.NewMockExchangeInfoClient(ctrl)
+			tt.fields.mockOperation(mockClient, mockExchangeInfoClient)
+			service := binance.NewService(mockClient)
+
+			res, err := service.GetSymbols(context.Background())
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.Equal(t, tt.want.res, res)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/orderbook"
+)
+
+// Default settings of the book syncer.
+const (
+	defaultSnapshotLimit = 1000
+	depthBufferSize      = 1000
+)
+
+// errBookGap is returned internally when a depth update does not follow the last applied update, so the book has to be resynced.
+var errBookGap = errors.New("depth update gap")
+
+// depthSnapshotter gets the order book snapshots. It is implemented by Service.
+type depthSnapshotter interface {
+	GetOrderBook(ctx context.Context, symbol string, limit int) (*connector.OrderBook, error)
+}
+
+// streamRunner runs market streams. It is implemented by Streamer.
+type streamRunner interface {
+	Run(ctx context.Context, streams []Stream, fn func(StreamEvent) error) error
+}
+
+// BookSyncer maintains a local order book of a symbol the way Binance documents it:
+// the diff depth stream is buffered, a REST snapshot is applied, and the buffered and following updates are applied in sequence.
+// The book is resynced from a new snapshot whenever an update does not follow the previous one.
+type BookSyncer struct {
+	snapshots depthSnapshotter
+	streams   streamRunner
+	book      *orderbook.Book
+	limit     int
+	log       *zap.Logger
+}
+
+// BookSyncerOption configures the book syncer.
+type BookSyncerOption func(*BookSyncer)
+
+// WithSnapshotLimit sets the depth of the REST snapshots.
+func WithSnapshotLimit(limit int) BookSyncerOption {
+	return func(s *BookSyncer) {
+		s.limit = limit
+	}
+}
+
+// WithBookLogger sets the logger of the resyncs.
+func WithBookLogger(l *zap.Logger) BookSyncerOption {
+	return func(s *BookSyncer) {
+		s.log = l
+	}
+}
+
+// NewBookSyncer creates a new book syncer of the symbol.
+func NewBookSyncer(snapshots depthSnapshotter, streams streamRunner, symbol string, opts ...BookSyncerOption) *BookSyncer {
+	s := &BookSyncer{
+		snapshots: snapshots,
+		streams:   streams,
+		book:      orderbook.New(symbol),
+		limit:     defaultSnapshotLimit,
+		log:       zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Book returns the local order book. It is safe to read while Run updates it.
+func (s *BookSyncer) Book() *orderbook.Book {
+	return s.book
+}
+
+// Run keeps the book in sync until the context is done, calling onUpdate after every applied update when it is not nil.
+func (s *BookSyncer) Run(ctx context.Context, onUpdate func(*orderbook.Book)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	updates := make(chan DepthUpdate, depthBufferSize)
+	streamErr := make(chan error, 1)
+
+	go func() {
+		defer close(updates)
+
+		streamErr <- s.streams.Run(ctx, []Stream{{Symbol: s.book.Symbol(), Type: StreamDepth}}, func(e StreamEvent) error {
+			if e.Depth == nil {
+				return nil
+			}
+
+			select {
+			case updates <- *e.Depth:
+				return nil
+			case <-ctx.Done():
+				return fmt.Errorf("book sync stopped: %w", ctx.Err())
+			}
+		})
+	}()
+
+	for {
+		err := s.sync(ctx, updates, onUpdate)
+		if !errors.Is(err, errBookGap) {
+			if err == nil {
+				// The updates channel is closed once the stream stopped.
+				err = <-streamErr
+			}
+
+			return err
+		}
+
+		s.log.Warn("order book out of sync, resyncing", zap.String("symbol", s.book.Symbol()), zap.Error(err))
+	}
+}
+
+// sync waits for the first buffered update, resets the book from a snapshot and applies the updates until a gap is found.
+func (s *BookSyncer) sync(ctx context.Context, updates <-chan DepthUpdate, onUpdate func(*orderbook.Book)) error {
+	first, ok := <-updates
+	if !ok {
+		return nil
+	}
+
+	snapshot, err := s.snapshots.GetOrderBook(ctx, s.book.Symbol(), s.limit)
+	if err != nil {
+		return fmt.Errorf("error getting order book snapshot: %w", err)
+	}
+
+	s.book.Reset(*snapshot)
+
+	synced := false
+
+	for u := first; ok; u, ok = <-updates {
+		last := s.book.LastUpdateID()
+
+		// Updates older than the snapshot are already part of it.
+		if u.FinalUpdateID <= last {
+			continue
+		}
+
+		// The first applied update has to contain the update following the snapshot, and every next update has to follow the previous one.
+		if u.FirstUpdateID > last+1 || (synced && u.FirstUpdateID != last+1) {
+			return fmt.Errorf("%w: expected update %d, got updates %d to %d", errBookGap, last+1, u.FirstUpdateID, u.FinalUpdateID)
+		}
+
+		synced = true
+
+		s.book.Apply(u.FinalUpdateID, u.Bids, u.Asks)
+
+		if onUpdate != nil {
+			onUpdate(s.book)
+		}
+	}
+
+	return nil
+}
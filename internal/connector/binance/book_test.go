@@ -0,0 +1,178 @@
+package binance_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+	"github.com/twk/trader-b/internal/orderbook"
+)
+
+type fakeSnapshots struct {
+	mu        sync.Mutex
+	snapshots []*connector.OrderBook
+	err       error
+	calls     int
+}
+
+func (f *fakeSnapshots) GetOrderBook(_ context.Context, symbol string, _ int) (*connector.OrderBook, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	s := f.snapshots[f.calls]
+	s.Symbol = symbol
+	f.calls++
+
+	return s, nil
+}
+
+type fakeDepthStream struct {
+	updates []binance.DepthUpdate
+}
+
+func (f *fakeDepthStream) Run(ctx context.Context, _ []binance.Stream, fn func(binance.StreamEvent) error) error {
+	for i := range f.updates {
+		if err := fn(binance.StreamEvent{Depth: &f.updates[i]}); err != nil {
+			return err
+		}
+	}
+
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func depth(first, final uint64, bids, asks []connector.PriceLevel) binance.DepthUpdate {
+	return binance.DepthUpdate{Symbol: "BTCUSDT", FirstUpdateID: first, FinalUpdateID: final, Bids: bids, Asks: asks}
+}
+
+func lvl(price, quantity string) connector.PriceLevel {
+	return connector.PriceLevel{Price: decimal.RequireFromString(price), Quantity: decimal.RequireFromString(quantity)}
+}
+
+func TestBookSyncer_Run(t *testing.T) {
+	t.Parallel()
+
+	snapshot := func(id uint64) *connector.OrderBook {
+		return &connector.OrderBook{
+			LastUpdateID: id,
+			Bids:         []connector.PriceLevel{lvl("100", "1")},
+			Asks:         []connector.PriceLevel{lvl("101", "1")},
+		}
+	}
+
+	type want struct {
+		book          connector.OrderBook
+		snapshotCalls int
+		err           error
+	}
+
+	tests := map[string]struct {
+		snapshots []*connector.OrderBook
+		err       error
+		updates   []binance.DepthUpdate
+		applied   int
+		want      want
+	}{
+		"stale updates are dropped and the first update straddles the snapshot": {
+			snapshots: []*connector.OrderBook{snapshot(10)},
+			updates: []binance.DepthUpdate{
+				depth(5, 8, []connector.PriceLevel{lvl("90", "5")}, nil),
+				depth(9, 12, []connector.PriceLevel{lvl("100", "2")}, nil),
+				depth(13, 14, nil, []connector.PriceLevel{lvl("101", "0"), lvl("102", "3")}),
+			},
+			applied: 2,
+			want: want{
+				book: connector.OrderBook{
+					Symbol: "BTCUSDT", LastUpdateID: 14,
+					Bids: []connector.PriceLevel{lvl("100", "2")},
+					Asks: []connector.PriceLevel{lvl("102", "3")},
+				},
+				snapshotCalls: 1,
+			},
+		},
+		"a gap resyncs from a new snapshot": {
+			snapshots: []*connector.OrderBook{snapshot(10), snapshot(20)},
+			updates: []binance.DepthUpdate{
+				depth(11, 12, []connector.PriceLevel{lvl("99", "1")}, nil),
+				depth(15, 16, []connector.PriceLevel{lvl("98", "1")}, nil),
+				depth(17, 21, nil, []connector.PriceLevel{lvl("103", "1")}),
+			},
+			applied: 2,
+			want: want{
+				book: connector.OrderBook{
+					Symbol: "BTCUSDT", LastUpdateID: 21,
+					Bids: []connector.PriceLevel{lvl("100", "1")},
+					Asks: []connector.PriceLevel{lvl("101", "1"), lvl("103", "1")},
+				},
+				snapshotCalls: 2,
+			},
+		},
+		"a snapshot older than the buffered updates resyncs": {
+			snapshots: []*connector.OrderBook{snapshot(10), snapshot(30)},
+			updates: []binance.DepthUpdate{
+				depth(25, 26, nil, nil),
+				depth(27, 31, []connector.PriceLevel{lvl("100", "0")}, nil),
+			},
+			applied: 1,
+			want: want{
+				book: connector.OrderBook{
+					Symbol: "BTCUSDT", LastUpdateID: 31,
+					Bids: []connector.PriceLevel{},
+					Asks: []connector.PriceLevel{lvl("101", "1")},
+				},
+				snapshotCalls: 2,
+			},
+		},
+		"snapshot error": {
+			err:     errors.New("get error"),
+			updates: []binance.DepthUpdate{depth(1, 2, nil, nil)},
+			want: want{
+				err: errors.New("error getting order book snapshot: get error"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			snapshots := &fakeSnapshots{snapshots: tt.snapshots, err: tt.err}
+			s := binance.NewBookSyncer(snapshots, &fakeDepthStream{updates: tt.updates}, "BTCUSDT", binance.WithSnapshotLimit(5))
+
+			applied := 0
+
+			err := s.Run(ctx, func(*orderbook.Book) {
+				applied++
+				if applied == tt.applied {
+					cancel()
+				}
+			})
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.Equal(t, tt.applied, applied)
+			assert.Equal(t, tt.want.snapshotCalls, snapshots.calls)
+			assert.Equal(t, tt.want.book, s.Book().Top(0))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// ErrSymbolNotFound is returned when a symbol is not listed in the catalog.
+var ErrSymbolNotFound = errors.New("symbol not found")
+
+type symbolsGetter interface {
+	GetSymbols(ctx context.Context) ([]connector.Symbol, error)
+}
+
+// SymbolQuery filters the symbols of the catalog. Empty fields match every symbol.
+type SymbolQuery struct {
+	QuoteAsset string
+	Status     string
+}
+
+func (q SymbolQuery) matches(s connector.Symbol) bool {
+	if q.QuoteAsset != "" && q.QuoteAsset != s.QuoteAsset {
+		return false
+	}
+
+	if q.Status != "" && q.Status != s.Status {
+		return false
+	}
+
+	return true
+}
+
+// Catalog is an in-memory cache of the symbols listed on Binance, built from the exchange info.
+type Catalog struct {
+	service symbolsGetter
+	ttl     time.Duration
+
+	mu        sync.Mutex
+	symbols   map[string]connector.Symbol
+	fetchedAt time.Time
+}
+
+// NewCatalog creates a new catalog. The exchange info is fetched again once the ttl has elapsed; a ttl of zero or less disables caching.
+func NewCatalog(service symbolsGetter, ttl time.Duration) *Catalog {
+	return &Catalog{service: service, ttl: ttl}
+}
+
+// Symbols returns the symbols matching the query sorted by name.
+func (c *Catalog) Symbols(ctx context.Context, q SymbolQuery) ([]connector.Symbol, error) {
+	symbols, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]connector.Symbol, 0, len(symbols))
+
+	for _, s := range symbols {
+		if q.matches(s) {
+			res = append(res, s)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res, nil
+}
+
+// Symbol returns the symbol with the given name.
+func (c *Catalog) Symbol(ctx context.Context, name string) (connector.Symbol, error) {
+	symbols, err := c.load(ctx)
+	if err != nil {
+		return connector.Symbol{}, err
+	}
+
+	s, ok := symbols[name]
+	if !ok {
+		return connector.Symbol{}, fmt.Errorf("%w: %s", ErrSymbolNotFound, name)
+	}
+
+	return s, nil
+}
+
+func (c *Catalog) load(ctx context.Context) (map[string]connector.Symbol, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.symbols != nil && c.ttl > 0 && time.Since(c.fetchedAt) < c.ttl {
+		return c.symbols, nil
+	}
+
+	symbols, err := c.service.GetSymbols(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error loading symbol catalog: %w", err)
+	}
+
+	c.symbols = make(map[string]connector.Symbol, len(symbols))
+	for _, s := range symbols {
+		c.symbols[s.Name] = s
+	}
+
+	c.fetchedAt = time.Now()
+
+	return c.symbols, nil
+}
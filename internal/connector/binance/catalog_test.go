@@ -0,0 +1,158 @@
+package binance_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+)
+
+type fakeSymbolsGetter struct {
+	symbols []connector.Symbol
+	err     error
+	calls   int
+}
+
+func (f *fakeSymbolsGetter) GetSymbols(_ context.Context) ([]connector.Symbol, error) {
+	f.calls++
+
+	return f.symbols, f.err
+}
+
+func testSymbols() []connector.Symbol {
+	return []connector.Symbol{
+		{Name: "ETHUSDT", BaseAsset: "ETH", QuoteAsset: "USDT", Status: "TRADING"},
+		{Name: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Status: "TRADING"},
+		{Name: "ETHBTC", BaseAsset: "ETH", QuoteAsset: "BTC", Status: "TRADING"},
+		{Name: "LUNAUSDT", BaseAsset: "LUNA", QuoteAsset: "USDT", Status: "BREAK"},
+	}
+}
+
+func TestCatalog_Symbols(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		query binance.SymbolQuery
+	}
+
+	type want struct {
+		names []string
+	}
+
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"no filter": {
+			args: args{},
+			want: want{names: []string{"BTCUSDT", "ETHBTC", "ETHUSDT", "LUNAUSDT"}},
+		},
+		"quote asset": {
+			args: args{query: binance.SymbolQuery{QuoteAsset: "USDT"}},
+			want: want{names: []string{"BTCUSDT", "ETHUSDT", "LUNAUSDT"}},
+		},
+		"quote asset and status": {
+			args: args{query: binance.SymbolQuery{QuoteAsset: "USDT", Status: "TRADING"}},
+			want: want{names: []string{"BTCUSDT", "ETHUSDT"}},
+		},
+		"status": {
+			args: args{query: binance.SymbolQuery{Status: "BREAK"}},
+			want: want{names: []string{"LUNAUSDT"}},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := binance.NewCatalog(&fakeSymbolsGetter{symbols: testSymbols()}, time.Hour)
+
+			res, err := c.Symbols(context.Background(), tt.args.query)
+			assert.NoError(t, err)
+
+			names := make([]string, 0, len(res))
+			for _, s := range res {
+				names = append(names, s.Name)
+			}
+
+			assert.Equal(t, tt.want.names, names)
+		})
+	}
+}
+
+func TestCatalog_Symbol(t *testing.T) {
+	t.Parallel()
+
+	c := binance.NewCatalog(&fakeSymbolsGetter{symbols: testSymbols()}, time.Hour)
+
+	s, err := c.Symbol(context.Background(), "ETHBTC")
+	assert.NoError(t, err)
+	assert.Equal(t, "BTC", s.QuoteAsset)
+
+	_, err = c.Symbol(context.Background(), "DOGEUSDT")
+	assert.ErrorIs(t, err, binance.ErrSymbolNotFound)
+}
+
+func TestCatalog_TTL(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		ttl time.Duration
+	}
+
+	type want struct {
+		calls int
+	}
+
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"cached within ttl": {
+			args: args{ttl: time.Hour},
+			want: want{calls: 1},
+		},
+		"caching disabled": {
+			args: args{ttl: 0},
+			want: want{calls: 2},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			getter := &fakeSymbolsGetter{symbols: testSymbols()}
+			c := binance.NewCatalog(getter, tt.args.ttl)
+
+			_, err := c.Symbols(context.Background(), binance.SymbolQuery{})
+			assert.NoError(t, err)
+
+			_, err = c.Symbol(context.Background(), "BTCUSDT")
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.want.calls, getter.calls)
+		})
+	}
+}
+
+func TestCatalog_Error(t *testing.T) {
+	t.Parallel()
+
+	c := binance.NewCatalog(&fakeSymbolsGetter{err: errors.New("boom")}, time.Hour)
+
+	_, err := c.Symbols(context.Background(), binance.SymbolQuery{})
+	assert.EqualError(t, err, "error loading symbol catalog: boom")
+
+	_, err = c.Symbol(context.Background(), "BTCUSDT")
+	assert.EqualError(t, err, "error loading symbol catalog: boom")
+}
@@ -2,16 +2,215 @@
 package binance
 
 import (
+	"context"
+	"net/http"
+	"time"
+
 	binance_connector "github.com/binance/binance-connector-go"
 
+	"github.com/twk/trader-b/internal/client"
 	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
 )
 
-// NewBinanceClient creates a new Binance client.
-func NewBinanceClient(cfg *config.Config, apiKey, apiSecret string) *binance_connector.Client {
-	if cfg.Connector.Binance.BaseURL == "" {
-		return binance_connector.NewClient(apiKey, apiSecret)
+// initialTimeSyncTimeout bounds the time sync of NewBinanceService.
+const initialTimeSyncTimeout = 5 * time.Second
+
+var _ Client = (*ClientAdapter)(nil)
+
+// ClientAdapter adapts the binance connector client to the Client interface.
+// The endpoints the connector does not support well enough are called with a client of the application sharing its http client.
+type ClientAdapter struct {
+	client *binance_connector.Client
+	rest   *client.Client
+	offset func() time.Duration
+}
+
+// ClientAdapterOption configures the client adapter.
+type ClientAdapterOption func(*ClientAdapter)
+
+// WithTimeOffset corrects the timestamps of the signed requests by the offset of the Binance server clock from the local clock,
+// e.g. TimeSync.Offset. The offset is read when a signed service of the connector is created, and when a request of the
+// application client is signed.
+func WithTimeOffset(offset func() time.Duration) ClientAdapterOption {
+	return func(a *ClientAdapter) {
+		a.offset = offset
+	}
+}
+
+// NewClientAdapter creates a new client adapter.
+func NewClientAdapter(c *binance_connector.Client, opts ...ClientAdapterOption) *ClientAdapter {
+	a := &ClientAdapter{client: c, rest: client.NewClient(c.HTTPClient)}
+
+	for _, opt := range opts {
+		opt(a)
 	}
 
-	return binance_connector.NewClient(apiKey, apiSecret, cfg.Connector.Binance.BaseURL)
+	return a
+}
+
+// signed returns the client of a signed service. The connector subtracts its TimeOffset from the local time of the timestamps,
+// and it is read by every request, so the corrected offset is set on a copy of the client rather than the shared one.
+func (a *ClientAdapter) signed() *binance_connector.Client {
+	if a.offset == nil {
+		return a.client
+	}
+
+	c := *a.client
+	c.TimeOffset = -a.offset().Milliseconds()
+
+	return &c
+}
+
+// signedREST returns the application client signing its requests with the credentials of the connector client.
+func (a *ClientAdapter) signedREST() *client.Client {
+	return a.rest.WithSigner(client.NewHMACSigner(apiKeyHeader, a.client.APIKey, a.client.SecretKey, client.WithClock(a.now)))
+}
+
+// now returns the local time corrected by the offset of the Binance server clock.
+func (a *ClientAdapter) now() time.Time {
+	if a.offset == nil {
+		return time.Now()
+	}
+
+	return time.Now().Add(a.offset())
+}
+
+// NewGetAccountService creates a new account service.
+func (a *ClientAdapter) NewGetAccountService() AccountClient {
+	return a.signed().NewGetAccountService()
+}
+
+// NewExchangeInfoService creates a new exchange info service.
+func (a *ClientAdapter) NewExchangeInfoService() ExchangeInfoClient {
+	return &exchangeInfoService{client: a.rest, baseURL: a.client.BaseURL}
+}
+
+// NewTickerPriceService creates a new ticker price service for the symbol.
+func (a *ClientAdapter) NewTickerPriceService(symbol string) TickerPriceClient {
+	return a.client.NewTickerPriceService().Symbol(symbol)
+}
+
+// NewOrderBookService creates a new order book service for the symbol.
+func (a *ClientAdapter) NewOrderBookService(symbol string, limit int) OrderBookClient {
+	return a.client.NewOrderBookService().Symbol(symbol).Limit(limit)
+}
+
+// NewRecentTradesService creates a new recent trades service for the symbol.
+func (a *ClientAdapter) NewRecentTradesService(symbol string, limit int) RecentTradesClient {
+	return a.client.NewRecentTradesListService().Symbol(symbol).Limit(limit)
+}
+
+// NewCreateOrderService creates a new create order service for the order request.
+func (a *ClientAdapter) NewCreateOrderService(req connector.OrderRequest) CreateOrderClient {
+	return &createOrderService{a.orderRequest(createOrderEndpoint, req)}
+}
+
+// NewTestOrderService creates a new test order service for the order request.
+func (a *ClientAdapter) NewTestOrderService(req connector.OrderRequest) TestOrderClient {
+	return &testOrderService{a.orderRequest(testOrderEndpoint, req)}
+}
+
+// orderRequest creates the signed request of the order on the endpoint.
+func (a *ClientAdapter) orderRequest(endpoint string, req connector.OrderRequest) orderRequest {
+	return orderRequest{client: a.signedREST(), baseURL: a.client.BaseURL, endpoint: endpoint, req: req}
+}
+
+// NewCancelOrderService creates a new cancel order service for the order.
+func (a *ClientAdapter) NewCancelOrderService(symbol string, orderID int64) CancelOrderClient {
+	return a.signed().NewCancelOrderService().Symbol(symbol).OrderId(orderID)
+}
+
+// NewGetOrderService creates a new get order service for the order.
+func (a *ClientAdapter) NewGetOrderService(symbol string, orderID int64) GetOrderClient {
+	return a.signed().NewGetOrderService().Symbol(symbol).OrderId(orderID)
+}
+
+// NewOpenOrdersService creates a new open orders service for the symbol, or for every symbol when it is empty.
+func (a *ClientAdapter) NewOpenOrdersService(symbol string) OpenOrdersClient {
+	s := a.signed().NewGetOpenOrdersService()
+	if symbol != "" {
+		s = s.Symbol(symbol)
+	}
+
+	return s
+}
+
+// NewKlinesService creates a new klines service. Zero start and end times are left out of the request.
+func (a *ClientAdapter) NewKlinesService(symbol, interval string, start, end time.Time, limit int) KlinesClient {
+	s := a.client.NewKlinesService().Symbol(symbol).Interval(interval).Limit(limit)
+
+	if !start.IsZero() {
+		s = s.StartTime(uint64(start.UnixMilli()))
+	}
+
+	if !end.IsZero() {
+		s = s.EndTime(uint64(end.UnixMilli()))
+	}
+
+	return s
+}
+
+// NewCreateListenKeyService creates a new service creating a listen key of the user data stream.
+func (a *ClientAdapter) NewCreateListenKeyService() ListenKeyClient {
+	return a.client.NewCreateListenKeyService()
+}
+
+// NewPingUserStreamService creates a new service keeping the listen key alive.
+func (a *ClientAdapter) NewPingUserStreamService(listenKey string) UserStreamClient {
+	return a.client.NewPingUserStream().ListenKey(listenKey)
+}
+
+// NewCloseUserStreamService creates a new service closing the listen key.
+func (a *ClientAdapter) NewCloseUserStreamService(listenKey string) UserStreamClient {
+	return a.client.NewCloseUserStream().ListenKey(listenKey)
+}
+
+// NewServerTimeService creates a new service getting the time of the Binance server.
+func (a *ClientAdapter) NewServerTimeService() ServerTimeClient {
+	return a.client.NewServerTimeService()
+}
+
+// NewBinanceClient creates a new Binance client using the credentials and base URL of the configured environment.
+func NewBinanceClient(cfg *config.Config) (*binance_connector.Client, error) {
+	b := cfg.Connector.Binance
+
+	e, err := ResolveEndpoints(b)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, secretKey := b.Credentials()
+
+	return binance_connector.NewClient(apiKey.Value(), secretKey.Value(), e.BaseURL), nil
+}
+
+// NewBinanceService creates a new Binance service backed by the binance connector client, whose requests go through a Limiter.
+// The timestamps of its signed requests are corrected by its TimeSync. When the credentials are set, it is synced once here,
+// and the signed requests are stamped by the local clock if that sync fails.
+func NewBinanceService(cfg *config.Config) (*Service, error) {
+	c, err := NewBinanceClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	l := NewLimiter(c.HTTPClient.Transport)
+	c.HTTPClient = &http.Client{Transport: l, Timeout: c.HTTPClient.Timeout}
+
+	ts := NewTimeSync(NewService(NewClientAdapter(c)), WithTimeSyncInterval(cfg.Connector.Binance.TimeSyncInterval))
+
+	if cfg.Connector.Binance.ValidateCredentials() == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), initialTimeSyncTimeout)
+		_, _ = ts.Sync(ctx)
+
+		cancel()
+	}
+
+	return NewService(NewClientAdapter(c, WithTimeOffset(ts.Offset)),
+		WithDryRun(cfg.Connector.Binance.DryRun), WithLimiter(l), WithTimeSync(ts)), nil
+}
+
+// NewExchange creates the Binance exchange from the configuration. It is the connector.Factory of Binance.
+func NewExchange(cfg *config.Config) (connector.Exchange, error) {
+	return NewBinanceService(cfg)
 }
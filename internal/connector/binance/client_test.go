@@ -0,0 +1,426 @@
+package binance_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	binance_connector "github.com/binance/binance-connector-go"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+)
+
+func newBinanceServer(t *testing.T, path string, status int, body string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestNewBinanceService_GetAccount(t *testing.T) {
+	type fields struct {
+		status int
+		body   string
+	}
+
+	type want struct {
+		res *binance_connector.AccountResponse
+		err string
+	}
+
+	tests := map[string]struct {
+		fields fields
+		want   want
+	}{
+		"Success": {
+			fields: fields{
+				status: http.StatusOK,
+				body:   `{"canTrade":true,"accountType":"SPOT","balances":[{"asset":"BTC","free":"0.5","locked":"0.1"}]}`,
+			},
+			want: want{
+				res: &binance_connector.AccountResponse{
+					CanTrade:    true,
+					AccountType: "SPOT",
+					Balances: []binance_connector.Balance{
+						{Asset: "BTC", Free: "0.5", Locked: "0.1"},
+					},
+				},
+			},
+		},
+		"API error": {
+			fields: fields{
+				status: http.StatusUnauthorized,
+				body:   `{"code":-2015,"msg":"Invalid API-key, IP, or permissions for action."}`,
+			},
+			want: want{
+				err: "error getting account",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newBinanceServer(t, "/api/v3/account", tt.fields.status, tt.fields.body)
+			defer server.Close()
+
+			cfg := &config.Config{Connector: config.Connector{Binance: config.Binance{BaseURL: server.URL, APIKey: "key", SecretKey: "secret"}}}
+			service, err := binance.NewBinanceService(cfg)
+			assert.NoError(t, err)
+
+			res, err := service.GetAccount(context.Background())
+			if tt.want.err != "" {
+				assert.ErrorContains(t, err, tt.want.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.res, res)
+		})
+	}
+}
+
+func TestNewBinanceService_GetExchangeInfo(t *testing.T) {
+	type fields struct {
+		status int
+		body   string
+	}
+
+	type want struct {
+		res *binance.ExchangeInfoResponse
+		err string
+	}
+
+	tests := map[string]struct {
+		fields fields
+		want   want
+	}{
+		"Success": {
+			fields: fields{
+				status: http.StatusOK,
+				body: `{"timezone":"UTC","serverTime":1700000000000,"symbols":[{"symbol":"BTCUSDT","status":"TRADING","baseAsset":"BTC","quoteAsset":"USDT",` +
+					`"filters":[{"filterType":"NOTIONAL","minNotional":"5.00000000","applyMinToMarket":true,"maxNotional":"9000000.00000000",` +
+					`"applyMaxToMarket":false,"avgPriceMins":5}]}]}`,
+			},
+			want: want{
+				res: &binance.ExchangeInfoResponse{
+					Timezone:   "UTC",
+					ServerTime: 1700000000000,
+					Symbols: []*binance.SymbolInfo{
+						{
+							Symbol: "BTCUSDT", Status: "TRADING", BaseAsset: "BTC", QuoteAsset: "USDT",
+							Filters: []*binance.SymbolFilter{{FilterType: "NOTIONAL", MinNotional: "5.00000000", MaxNotional: "9000000.00000000", ApplyMinToMarket: true}},
+						},
+					},
+				},
+			},
+		},
+		"Server error": {
+			fields: fields{
+				status: http.StatusInternalServerError,
+				body:   `{"code":-1000,"msg":"An unknown error occurred while processing the request."}`,
+			},
+			want: want{
+				err: "error getting exchange info",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newBinanceServer(t, "/api/v3/exchangeInfo", tt.fields.status, tt.fields.body)
+			defer server.Close()
+
+			cfg := &config.Config{Connector: config.Connector{Binance: config.Binance{BaseURL: server.URL, APIKey: "key", SecretKey: "secret"}}}
+			service, err := binance.NewBinanceService(cfg)
+			assert.NoError(t, err)
+
+			res, err := service.GetExchangeInfo(context.Background())
+			if tt.want.err != "" {
+				assert.ErrorContains(t, err, tt.want.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.res, res)
+		})
+	}
+}
+
+func TestNewBinanceClient(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		cfg *config.Config
+	}
+
+	type want struct {
+		baseURL string
+		err     error
+	}
+
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"default base URL": {
+			args: args{cfg: &config.Config{Connector: config.Connector{Binance: config.Binance{APIKey: "key", SecretKey: "secret"}}}},
+			want: want{baseURL: "https://api.binance.com"},
+		},
+		"custom base URL": {
+			args: args{cfg: &config.Config{Connector: config.Connector{Binance: config.Binance{BaseURL: "http://localhost:8080", APIKey: "key", SecretKey: "secret"}}}},
+			want: want{baseURL: "http://localhost:8080"},
+		},
+		"testnet": {
+			args: args{cfg: &config.Config{Connector: config.Connector{Binance: config.Binance{
+				Environment: "testnet", APIKey: "mainnet-key", SecretKey: "mainnet-secret", TestnetAPIKey: "key", TestnetSecretKey: "secret",
+			}}}},
+			want: want{baseURL: "https://testnet.binance.vision"},
+		},
+		"unknown environment": {
+			args: args{cfg: &config.Config{Connector: config.Connector{Binance: config.Binance{Environment: "devnet"}}}},
+			want: want{err: errors.New(`unknown binance environment: "devnet", expected mainnet or testnet`)},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := binance.NewBinanceClient(tt.args.cfg)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.baseURL, c.BaseURL)
+			assert.Equal(t, "key", c.APIKey)
+			assert.Equal(t, "secret", c.SecretKey)
+		})
+	}
+}
+
+func TestNewBinanceService_MarketData(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/ticker/price", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"symbol":"` + r.URL.Query().Get("symbol") + `","price":"65000.01"}`))
+	})
+	mux.HandleFunc("/api/v3/depth", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"lastUpdateId":42,"bids":[["100.5","2"]],"asks":[["101","0.5"]]}`))
+	})
+	mux.HandleFunc("/api/v3/trades", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[{"id":7,"price":"100.5","qty":"0.01","time":1700000000000,"isBuyerMaker":true}]`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cfg := &config.Config{Connector: config.Connector{Binance: config.Binance{BaseURL: server.URL}}}
+
+	e, err := binance.NewExchange(cfg)
+	assert.NoError(t, err)
+
+	tickers, err := e.GetTickers(context.Background(), []string{"BTCUSDT"})
+	assert.NoError(t, err)
+	assert.Equal(t, []connector.Ticker{{Symbol: "BTCUSDT", Price: decimal.RequireFromString("65000.01")}}, tickers)
+
+	ob, err := e.GetOrderBook(context.Background(), "BTCUSDT", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(42), ob.LastUpdateID)
+	assert.Equal(t, []connector.PriceLevel{{Price: decimal.RequireFromString("100.5"), Quantity: decimal.RequireFromString("2")}}, ob.Bids)
+	assert.Equal(t, []connector.PriceLevel{{Price: decimal.RequireFromString("101"), Quantity: decimal.RequireFromString("0.5")}}, ob.Asks)
+
+	trades, err := e.GetTrades(context.Background(), "BTCUSDT", 1)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+	assert.Equal(t, uint64(7), trades[0].ID)
+	assert.True(t, trades[0].IsBuyerMaker)
+}
+
+func TestNewBinanceService_Orders(t *testing.T) {
+	var sent url.Values
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/order", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		switch r.Method {
+		case http.MethodPost:
+			sent = q
+			_, _ = w.Write([]byte(`{"symbol":"` + q.Get("symbol") + `","orderId":1,"price":"` + q.Get("price") + `","origQty":"` + q.Get("quantity") + `","status":"NEW","type":"` + q.Get("type") + `","side":"` + q.Get("side") + `","timeInForce":"` + q.Get("timeInForce") + `"}`))
+		case http.MethodDelete:
+			_, _ = w.Write([]byte(`{"symbol":"` + q.Get("symbol") + `","orderId":` + q.Get("orderId") + `,"status":"CANCELED"}`))
+		default:
+			_, _ = w.Write([]byte(`{"symbol":"` + q.Get("symbol") + `","orderId":` + q.Get("orderId") + `,"status":"FILLED"}`))
+		}
+	})
+	mux.HandleFunc("/api/v3/openOrders", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[{"symbol":"BTCUSDT","orderId":1,"status":"NEW"},{"symbol":"ETHUSDT","orderId":2,"status":"NEW"}]`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cfg := &config.Config{Connector: config.Connector{Binance: config.Binance{BaseURL: server.URL, APIKey: "key", SecretKey: "secret"}}}
+	service, err := binance.NewBinanceService(cfg)
+	assert.NoError(t, err)
+
+	placed, err := service.PlaceOrder(context.Background(), connector.OrderRequest{
+		Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Quantity: decimal.RequireFromString("0.001"),
+		Price: decimal.RequireFromString("65000.5"), ClientOrderID: "my-order",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "65000.5", placed.Price.String())
+	assert.Equal(t, "0.001", placed.Quantity.String())
+	assert.Equal(t, connector.OrderTypeLimit, placed.Type)
+
+	small, err := service.PlaceOrder(context.Background(), connector.OrderRequest{
+		Symbol: "SHIBUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Quantity: decimal.RequireFromString("1000000"),
+		Price: decimal.RequireFromString("0.00001234"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "0.00001234", small.Price.String())
+	assert.Equal(t, "0.00001234", sent.Get("price"))
+	assert.Equal(t, "1000000", sent.Get("quantity"))
+	assert.Len(t, sent.Get("signature"), 64)
+
+	market, err := service.PlaceOrder(context.Background(), connector.OrderRequest{
+		Symbol: "BTCUSDT", Side: connector.SideSell, Type: connector.OrderTypeMarket, Quantity: decimal.RequireFromString("0.001"),
+	})
+	assert.NoError(t, err)
+	assert.True(t, market.Price.IsZero())
+	assert.Equal(t, connector.SideSell, market.Side)
+
+	cancelled, err := service.CancelOrder(context.Background(), "BTCUSDT", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "CANCELED", cancelled.Status)
+
+	order, err := service.GetOrder(context.Background(), "BTCUSDT", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "FILLED", order.Status)
+
+	open, err := service.ListOpenOrders(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Len(t, open, 2)
+
+	open, err = service.ListOpenOrders(context.Background(), "BTCUSDT")
+	assert.NoError(t, err)
+	assert.Len(t, open, 2)
+}
+
+func TestNewBinanceService_DryRun(t *testing.T) {
+	type fields struct {
+		status int
+		body   string
+	}
+
+	type want struct {
+		err string
+	}
+
+	tests := map[string]struct {
+		fields fields
+		want   want
+	}{
+		"Success": {
+			fields: fields{status: http.StatusOK, body: `{}`},
+		},
+		"API error": {
+			fields: fields{status: http.StatusBadRequest, body: `{"code":-1013,"msg":"Filter failure: LOT_SIZE"}`},
+			want:   want{err: "error placing test order on BTCUSDT: <APIError> code=-1013, msg=Filter failure: LOT_SIZE"},
+		},
+		"Server error": {
+			fields: fields{status: http.StatusBadGateway, body: `bad gateway`},
+			want:   want{err: "error placing test order on BTCUSDT: received non-OK HTTP status: 502"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got *http.Request
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+
+				if r.URL.Path != "/api/v3/order/test" || r.Method != http.MethodPost {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				w.WriteHeader(tt.fields.status)
+				_, _ = w.Write([]byte(tt.fields.body))
+			}))
+			defer server.Close()
+
+			cfg := &config.Config{Connector: config.Connector{Binance: config.Binance{BaseURL: server.URL, APIKey: "key", SecretKey: "secret", DryRun: true}}}
+			service, err := binance.NewBinanceService(cfg)
+			assert.NoError(t, err)
+
+			order, err := service.PlaceOrder(context.Background(), connector.OrderRequest{
+				Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit,
+				Quantity: decimal.RequireFromString("0.00001"), Price: decimal.RequireFromString("65000.01"), ClientOrderID: "my-order",
+			})
+			if tt.want.err != "" {
+				assert.EqualError(t, err, tt.want.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, binance.OrderStatusDryRun, order.Status)
+
+			q := got.URL.Query()
+			assert.Equal(t, "key", got.Header.Get("X-MBX-APIKEY"))
+			assert.Equal(t, "0.00001", q.Get("quantity"))
+			assert.Equal(t, "65000.01", q.Get("price"))
+			assert.Equal(t, "GTC", q.Get("timeInForce"))
+			assert.Equal(t, "my-order", q.Get("newClientOrderId"))
+			assert.NotEmpty(t, q.Get("timestamp"))
+			assert.Len(t, q.Get("signature"), 64)
+		})
+	}
+}
+
+func TestNewBinanceService_Klines(t *testing.T) {
+	var got *http.Request
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		_, _ = w.Write([]byte(`[[1700000040000,"100","101","99","100.5","2",1700000099999,"201",4,"1","100.5"]]`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Connector: config.Connector{Binance: config.Binance{BaseURL: server.URL}}}
+	service, err := binance.NewBinanceService(cfg)
+	assert.NoError(t, err)
+
+	start := time.UnixMilli(1700000040000)
+
+	candles, err := service.GetKlines(context.Background(), "BTCUSDT", "1m", start, time.Time{}, 500)
+	assert.NoError(t, err)
+	assert.Len(t, candles, 1)
+	assert.Equal(t, "100.5", candles[0].Close.String())
+
+	q := got.URL.Query()
+	assert.Equal(t, "/api/v3/klines", got.URL.Path)
+	assert.Equal(t, "1700000040000", q.Get("startTime"))
+	assert.False(t, q.Has("endTime"))
+	assert.Equal(t, "500", q.Get("limit"))
+	assert.Equal(t, "1m", q.Get("interval"))
+}
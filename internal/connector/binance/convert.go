@@ -0,0 +1,313 @@
+package binance
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	binance_connector "github.com/binance/binance-connector-go"
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// priceLevelSize is the number of elements of a price level in the order book response, the price and the quantity.
+const priceLevelSize = 2
+
+// Filter types of the exchange info symbol filters.
+const (
+	FilterTypePrice         = "PRICE_FILTER"
+	FilterTypeLotSize       = "LOT_SIZE"
+	FilterTypeMarketLotSize = "MARKET_LOT_SIZE"
+	FilterTypeMinNotional   = "MIN_NOTIONAL"
+	FilterTypeNotional      = "NOTIONAL"
+)
+
+func toBalances(balances []binance_connector.Balance) ([]connector.Balance, error) {
+	res := make([]connector.Balance, 0, len(balances))
+
+	for _, b := range balances {
+		free, err := decimal.NewFromString(b.Free)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing free amount of %s: %w", b.Asset, err)
+		}
+
+		locked, err := decimal.NewFromString(b.Locked)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing locked amount of %s: %w", b.Asset, err)
+		}
+
+		res = append(res, connector.Balance{Asset: b.Asset, Free: free, Locked: locked})
+	}
+
+	return res, nil
+}
+
+func toTicker(t *binance_connector.TickerPriceResponse) (connector.Ticker, error) {
+	price, err := decimal.NewFromString(t.Price)
+	if err != nil {
+		return connector.Ticker{}, fmt.Errorf("error parsing price of %s: %w", t.Symbol, err)
+	}
+
+	return connector.Ticker{Symbol: t.Symbol, Price: price}, nil
+}
+
+func toOrderBook(symbol string, ob *binance_connector.OrderBookResponse) (*connector.OrderBook, error) {
+	bids, err := toPriceLevels(ob.Bids)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing bids of %s: %w", symbol, err)
+	}
+
+	asks, err := toPriceLevels(ob.Asks)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing asks of %s: %w", symbol, err)
+	}
+
+	return &connector.OrderBook{
+		Symbol:       symbol,
+		LastUpdateID: ob.LastUpdateId,
+		Bids:         bids,
+		Asks:         asks,
+	}, nil
+}
+
+func toPriceLevels(levels [][]*big.Float) ([]connector.PriceLevel, error) {
+	res := make([]connector.PriceLevel, 0, len(levels))
+
+	for _, l := range levels {
+		if len(l) < priceLevelSize || l[0] == nil || l[1] == nil {
+			return nil, fmt.Errorf("invalid price level: %v", l)
+		}
+
+		price, err := decimal.NewFromString(l[0].Text('f', -1))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing price: %w", err)
+		}
+
+		qty, err := decimal.NewFromString(l[1].Text('f', -1))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing quantity: %w", err)
+		}
+
+		res = append(res, connector.PriceLevel{Price: price, Quantity: qty})
+	}
+
+	return res, nil
+}
+
+func toTrades(symbol string, trades []*binance_connector.RecentTradesListResponse) ([]connector.Trade, error) {
+	res := make([]connector.Trade, 0, len(trades))
+
+	for _, t := range trades {
+		price, err := decimal.NewFromString(t.Price)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing price of trade %d: %w", t.Id, err)
+		}
+
+		qty, err := decimal.NewFromString(t.Qty)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing quantity of trade %d: %w", t.Id, err)
+		}
+
+		res = append(res, connector.Trade{
+			ID:           t.Id,
+			Symbol:       symbol,
+			Price:        price,
+			Quantity:     qty,
+			Time:         time.UnixMilli(int64(t.Time)),
+			IsBuyerMaker: t.IsBuyerMaker,
+		})
+	}
+
+	return res, nil
+}
+
+func toSymbols(info *ExchangeInfoResponse) ([]connector.Symbol, error) {
+	res := make([]connector.Symbol, 0, len(info.Symbols))
+
+	for _, s := range info.Symbols {
+		if s == nil {
+			continue
+		}
+
+		filters, err := toSymbolFilters(s.Filters)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing filters of %s: %w", s.Symbol, err)
+		}
+
+		res = append(res, connector.Symbol{
+			Name:       s.Symbol,
+			BaseAsset:  s.BaseAsset,
+			QuoteAsset: s.QuoteAsset,
+			Status:     s.Status,
+			Filters:    filters,
+		})
+	}
+
+	return res, nil
+}
+
+func toSymbolFilters(filters []*SymbolFilter) (connector.SymbolFilters, error) {
+	var res connector.SymbolFilters
+
+	for _, f := range filters {
+		if f == nil {
+			continue
+		}
+
+		var fields map[*decimal.Decimal]string
+
+		switch f.FilterType {
+		case FilterTypePrice:
+			fields = map[*decimal.Decimal]string{&res.MinPrice: f.MinPrice, &res.MaxPrice: f.MaxPrice, &res.TickSize: f.TickSize}
+		case FilterTypeLotSize:
+			fields = map[*decimal.Decimal]string{&res.MinQty: f.MinQty, &res.MaxQty: f.MaxQty, &res.StepSize: f.StepSize}
+		case FilterTypeMarketLotSize:
+			fields = map[*decimal.Decimal]string{&res.MarketMinQty: f.MinQty, &res.MarketMaxQty: f.MaxQty, &res.MarketStepSize: f.StepSize}
+		case FilterTypeMinNotional, FilterTypeNotional:
+			n, err := toNotionalFilter(f)
+			if err != nil {
+				return connector.SymbolFilters{}, err
+			}
+
+			res.NotionalFilters = append(res.NotionalFilters, n)
+
+			if n.MinNotional.GreaterThan(res.MinNotional) {
+				res.MinNotional = n.MinNotional
+			}
+
+			if n.MaxNotional.IsPositive() && (res.MaxNotional.IsZero() || n.MaxNotional.LessThan(res.MaxNotional)) {
+				res.MaxNotional = n.MaxNotional
+			}
+
+			continue
+		default:
+			continue
+		}
+
+		for dst, v := range fields {
+			if err := parseOptionalDecimal(dst, v); err != nil {
+				return connector.SymbolFilters{}, fmt.Errorf("error parsing %s: %w", f.FilterType, err)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// toNotionalFilter converts a MIN_NOTIONAL or NOTIONAL filter. The minimum of MIN_NOTIONAL applies to the market orders with applyToMarket.
+func toNotionalFilter(f *SymbolFilter) (connector.NotionalFilter, error) {
+	n := connector.NotionalFilter{Name: f.FilterType, ApplyMinToMarket: f.ApplyMinToMarket, ApplyMaxToMarket: f.ApplyMaxToMarket}
+	if f.FilterType == FilterTypeMinNotional {
+		n.ApplyMinToMarket = f.ApplyToMarket
+	}
+
+	for dst, v := range map[*decimal.Decimal]string{&n.MinNotional: f.MinNotional, &n.MaxNotional: f.MaxNotional} {
+		if err := parseOptionalDecimal(dst, v); err != nil {
+			return connector.NotionalFilter{}, fmt.Errorf("error parsing %s: %w", f.FilterType, err)
+		}
+	}
+
+	return n, nil
+}
+
+// parseOptionalDecimal parses v into dst, leaving dst untouched when v is empty.
+func parseOptionalDecimal(dst *decimal.Decimal, v string) error {
+	if v == "" {
+		return nil
+	}
+
+	d, err := decimal.NewFromString(v)
+	if err != nil {
+		return fmt.Errorf("error parsing decimal: %w", err)
+	}
+
+	*dst = d
+
+	return nil
+}
+
+// orderFields holds the fields shared by the order responses of the connector.
+type orderFields struct {
+	id               int64
+	clientOrderID    string
+	symbol           string
+	side             string
+	orderType        string
+	status           string
+	price            string
+	quantity         string
+	executedQuantity string
+	time             uint64
+}
+
+func toOrder(f orderFields) (*connector.Order, error) {
+	o := &connector.Order{
+		ID:            f.id,
+		ClientOrderID: f.clientOrderID,
+		Symbol:        f.symbol,
+		Side:          connector.Side(f.side),
+		Type:          connector.OrderType(f.orderType),
+		Status:        f.status,
+	}
+
+	if f.time > 0 {
+		o.Time = time.UnixMilli(int64(f.time))
+	}
+
+	for dst, v := range map[*decimal.Decimal]string{&o.Price: f.price, &o.Quantity: f.quantity, &o.ExecutedQuantity: f.executedQuantity} {
+		if err := parseOptionalDecimal(dst, v); err != nil {
+			return nil, fmt.Errorf("error parsing order %d: %w", f.id, err)
+		}
+	}
+
+	return o, nil
+}
+
+func toCreatedOrder(res interface{}) (*connector.Order, error) {
+	switch r := res.(type) {
+	case *binance_connector.CreateOrderResponseACK:
+		return toOrder(orderFields{id: r.OrderId, clientOrderID: r.ClientOrderId, symbol: r.Symbol, time: r.TransactTime})
+	case *binance_connector.CreateOrderResponseRESULT:
+		return toOrder(orderFields{
+			id: r.OrderId, clientOrderID: r.ClientOrderId, symbol: r.Symbol, side: r.Side, orderType: r.Type, status: r.Status,
+			price: r.Price, quantity: r.OrigQty, executedQuantity: r.ExecutedQty, time: r.TransactTime,
+		})
+	case *binance_connector.CreateOrderResponseFULL:
+		return toOrder(orderFields{
+			id: r.OrderId, clientOrderID: r.ClientOrderId, symbol: r.Symbol, side: r.Side, orderType: r.Type, status: r.Status,
+			price: r.Price, quantity: r.OrigQty, executedQuantity: r.ExecutedQty, time: r.TransactTime,
+		})
+	default:
+		return nil, fmt.Errorf("unexpected order response type %T", res)
+	}
+}
+
+func toCandles(symbol, interval string, klines []*binance_connector.KlinesResponse) ([]connector.Candle, error) {
+	res := make([]connector.Candle, 0, len(klines))
+
+	for _, k := range klines {
+		if k == nil {
+			continue
+		}
+
+		c := connector.Candle{
+			Symbol:    symbol,
+			Interval:  interval,
+			OpenTime:  time.UnixMilli(int64(k.OpenTime)).UTC(),
+			CloseTime: time.UnixMilli(int64(k.CloseTime)).UTC(),
+			Trades:    k.NumberOfTrades,
+		}
+
+		fields := map[*decimal.Decimal]string{&c.Open: k.Open, &c.High: k.High, &c.Low: k.Low, &c.Close: k.Close, &c.Volume: k.Volume, &c.QuoteVolume: k.QuoteAssetVolume}
+		for dst, v := range fields {
+			if err := parseOptionalDecimal(dst, v); err != nil {
+				return nil, fmt.Errorf("error parsing kline of %s opened at %d: %w", symbol, k.OpenTime, err)
+			}
+		}
+
+		res = append(res, c)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,91 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	binance_connector "github.com/binance/binance-connector-go"
+
+	"github.com/twk/trader-b/internal/client"
+	"github.com/twk/trader-b/internal/connector"
+)
+
+const (
+	createOrderEndpoint = "/api/v3/order"
+	testOrderEndpoint   = "/api/v3/order/test"
+)
+
+// orderRequest sends an order request on POST /api/v3/order or POST /api/v3/order/test.
+// The connector formats float64 amounts with %v, e.g. 1.234e-05, which Binance rejects, so the request is built here
+// from the decimal order request and signed by the client. Placed and test orders share it so that what is validated is what is sent.
+type orderRequest struct {
+	client   *client.Client
+	baseURL  string
+	endpoint string
+	req      connector.OrderRequest
+}
+
+// send sends the order request and returns the body of the response.
+func (o *orderRequest) send(ctx context.Context) ([]byte, error) {
+	q := url.Values{}
+	q.Set("symbol", o.req.Symbol)
+	q.Set("side", string(o.req.Side))
+	q.Set("type", string(o.req.Type))
+	q.Set("quantity", o.req.Quantity.String())
+	q.Set("newOrderRespType", "FULL")
+
+	if o.req.Type != connector.OrderTypeMarket {
+		q.Set("price", o.req.Price.String())
+	}
+
+	if o.req.TimeInForce != "" {
+		q.Set("timeInForce", o.req.TimeInForce)
+	}
+
+	if o.req.ClientOrderID != "" {
+		q.Set("newClientOrderId", o.req.ClientOrderID)
+	}
+
+	resp, err := o.client.Post(ctx, o.baseURL+o.endpoint, q, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return readResponse(resp)
+}
+
+// createOrderService places an order on POST /api/v3/order.
+type createOrderService struct {
+	orderRequest
+}
+
+// Do places the order and returns its full response. The request options of the connector are not supported and are ignored.
+func (s *createOrderService) Do(ctx context.Context, _ ...binance_connector.RequestOption) (interface{}, error) {
+	body, err := s.send(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &binance_connector.CreateOrderResponseFULL{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return res, nil
+}
+
+// testOrderService validates an order on POST /api/v3/order/test without placing it.
+type testOrderService struct {
+	orderRequest
+}
+
+// Do sends the test order. The request options of the connector are not supported and are ignored.
+func (s *testOrderService) Do(ctx context.Context, _ ...binance_connector.RequestOption) (*binance_connector.AccountOrderBookResponse, error) {
+	if _, err := s.send(ctx); err != nil {
+		return nil, err
+	}
+
+	return &binance_connector.AccountOrderBookResponse{}, nil
+}
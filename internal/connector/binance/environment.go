@@ -0,0 +1,56 @@
+package binance
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/twk/trader-b/internal/config"
+)
+
+// The REST and websocket base URLs of the Binance environments.
+const (
+	MainnetBaseURL   = "https://api.binance.com"
+	MainnetWSBaseURL = "wss://stream.binance.com:9443"
+	TestnetBaseURL   = "https://testnet.binance.vision"
+	TestnetWSBaseURL = "wss://stream.testnet.binance.vision"
+)
+
+// ErrUnknownEnvironment is returned when connector.binance.environment is neither mainnet nor testnet.
+var ErrUnknownEnvironment = errors.New("unknown binance environment")
+
+// Endpoints holds the environment and the base URLs resolved from the Binance configuration.
+type Endpoints struct {
+	Environment string
+	BaseURL     string
+	WSBaseURL   string
+}
+
+// Mainnet reports whether the endpoints belong to the production environment, where orders use real funds.
+func (e Endpoints) Mainnet() bool {
+	return e.Environment == config.EnvironmentMainnet
+}
+
+// ResolveEndpoints returns the base URLs of the configured environment. An empty environment means mainnet,
+// and base_url and ws_base_url override the URLs of the environment when they are set.
+func ResolveEndpoints(b config.Binance) (Endpoints, error) {
+	var e Endpoints
+
+	switch b.Env() {
+	case config.EnvironmentMainnet:
+		e = Endpoints{Environment: config.EnvironmentMainnet, BaseURL: MainnetBaseURL, WSBaseURL: MainnetWSBaseURL}
+	case config.EnvironmentTestnet:
+		e = Endpoints{Environment: config.EnvironmentTestnet, BaseURL: TestnetBaseURL, WSBaseURL: TestnetWSBaseURL}
+	default:
+		return Endpoints{}, fmt.Errorf("%w: %q, expected %s or %s", ErrUnknownEnvironment, b.Environment, config.EnvironmentMainnet, config.EnvironmentTestnet)
+	}
+
+	if b.BaseURL != "" {
+		e.BaseURL = b.BaseURL
+	}
+
+	if b.WSBaseURL != "" {
+		e.WSBaseURL = b.WSBaseURL
+	}
+
+	return e, nil
+}
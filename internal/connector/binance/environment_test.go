@@ -0,0 +1,67 @@
+package binance_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector/binance"
+)
+
+func TestResolveEndpoints(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		binance config.Binance
+	}
+
+	type want struct {
+		endpoints binance.Endpoints
+		mainnet   bool
+		err       error
+	}
+
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"default is mainnet": {
+			args: args{binance: config.Binance{}},
+			want: want{endpoints: binance.Endpoints{Environment: "mainnet", BaseURL: binance.MainnetBaseURL, WSBaseURL: binance.MainnetWSBaseURL}, mainnet: true},
+		},
+		"testnet": {
+			args: args{binance: config.Binance{Environment: "TESTNET"}},
+			want: want{endpoints: binance.Endpoints{Environment: "testnet", BaseURL: binance.TestnetBaseURL, WSBaseURL: binance.TestnetWSBaseURL}},
+		},
+		"overrides": {
+			args: args{binance: config.Binance{Environment: "testnet", BaseURL: "http://localhost:8080", WSBaseURL: "ws://localhost:8081"}},
+			want: want{endpoints: binance.Endpoints{Environment: "testnet", BaseURL: "http://localhost:8080", WSBaseURL: "ws://localhost:8081"}},
+		},
+		"unknown environment": {
+			args: args{binance: config.Binance{Environment: "devnet"}},
+			want: want{err: errors.New(`unknown binance environment: "devnet", expected mainnet or testnet`)},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := binance.ResolveEndpoints(tt.args.binance)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				assert.ErrorIs(t, err, binance.ErrUnknownEnvironment)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.endpoints, e)
+			assert.Equal(t, tt.want.mainnet, e.Mainnet())
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	binance_connector "github.com/binance/binance-connector-go"
+
+	"github.com/twk/trader-b/internal/client"
+)
+
+const exchangeInfoEndpoint = "/api/v3/exchangeInfo"
+
+// ExchangeInfoResponse is the response of GET /api/v3/exchangeInfo. The connector does not decode every field of the symbol filters,
+// e.g. the maxNotional of the NOTIONAL filter, so the response is decoded into these types instead.
+type ExchangeInfoResponse struct {
+	Timezone   string        `json:"timezone"`
+	ServerTime uint64        `json:"serverTime"`
+	Symbols    []*SymbolInfo `json:"symbols"`
+}
+
+// SymbolInfo is a symbol of the exchange info.
+type SymbolInfo struct {
+	Symbol     string          `json:"symbol"`
+	Status     string          `json:"status"`
+	BaseAsset  string          `json:"baseAsset"`
+	QuoteAsset string          `json:"quoteAsset"`
+	Filters    []*SymbolFilter `json:"filters"`
+}
+
+// SymbolFilter is a filter of a symbol. It holds the fields of every filter type, the ones of the other types are empty.
+// The MIN_NOTIONAL filter applies to the market orders with applyToMarket, the NOTIONAL one with applyMinToMarket and applyMaxToMarket.
+type SymbolFilter struct {
+	FilterType       string `json:"filterType"`
+	MinPrice         string `json:"minPrice"`
+	MaxPrice         string `json:"maxPrice"`
+	TickSize         string `json:"tickSize"`
+	MinQty           string `json:"minQty"`
+	MaxQty           string `json:"maxQty"`
+	StepSize         string `json:"stepSize"`
+	MinNotional      string `json:"minNotional"`
+	MaxNotional      string `json:"maxNotional"`
+	ApplyToMarket    bool   `json:"applyToMarket"`
+	ApplyMinToMarket bool   `json:"applyMinToMarket"`
+	ApplyMaxToMarket bool   `json:"applyMaxToMarket"`
+}
+
+// exchangeInfoService gets the exchange info on GET /api/v3/exchangeInfo.
+type exchangeInfoService struct {
+	client  *client.Client
+	baseURL string
+}
+
+// Do gets the exchange info. The request options of the connector are not supported and are ignored.
+func (s *exchangeInfoService) Do(ctx context.Context, _ ...binance_connector.RequestOption) (*ExchangeInfoResponse, error) {
+	resp, err := s.client.Get(ctx, s.baseURL+exchangeInfoEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := readResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ExchangeInfoResponse{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,114 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// KlinesPageLimit is the maximum number of klines Binance returns per request.
+const KlinesPageLimit = 1000
+
+// ErrUnsupportedInterval is returned when a kline interval is not supported.
+var ErrUnsupportedInterval = errors.New("unsupported kline interval")
+
+// IntervalDuration returns the duration of a kline interval such as 1m, 4h or 1d.
+// The monthly interval has no fixed duration and is not supported.
+func IntervalDuration(interval string) (time.Duration, error) {
+	const day = 24 * time.Hour
+
+	switch interval {
+	case "1s":
+		return time.Second, nil
+	case "1m":
+		return time.Minute, nil
+	case "3m":
+		return 3 * time.Minute, nil
+	case "5m":
+		return 5 * time.Minute, nil
+	case "15m":
+		return 15 * time.Minute, nil
+	case "30m":
+		return 30 * time.Minute, nil
+	case "1h":
+		return time.Hour, nil
+	case "2h":
+		return 2 * time.Hour, nil
+	case "4h":
+		return 4 * time.Hour, nil
+	case "6h":
+		return 6 * time.Hour, nil
+	case "8h":
+		return 8 * time.Hour, nil
+	case "12h":
+		return 12 * time.Hour, nil
+	case "1d":
+		return day, nil
+	case "3d":
+		return 3 * day, nil
+	case "1w":
+		return 7 * day, nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnsupportedInterval, interval)
+	}
+}
+
+// KlinesQuery selects the klines of a symbol and interval opened in [Start, End). A zero End means up to now.
+type KlinesQuery struct {
+	Symbol   string
+	Interval string
+	Start    time.Time
+	End      time.Time
+}
+
+// GetKlines gets at most limit klines of the symbol and interval opened in [start, end) from Binance.
+func (s *Service) GetKlines(ctx context.Context, symbol, interval string, start, end time.Time, limit int) ([]connector.Candle, error) {
+	res, err := s.client.NewKlinesService(symbol, interval, start, end, limit).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s klines of %s: %w", interval, symbol, err)
+	}
+
+	return toCandles(symbol, interval, res)
+}
+
+// FetchKlines pages through the klines selected by the query, KlinesPageLimit at a time, and calls fn with every page in order.
+// It stops at the first error returned by fn.
+func (s *Service) FetchKlines(ctx context.Context, q KlinesQuery, fn func([]connector.Candle) error) error {
+	if _, err := IntervalDuration(q.Interval); err != nil {
+		return err
+	}
+
+	start := q.Start
+
+	for q.End.IsZero() || start.Before(q.End) {
+		end := q.End
+		if !end.IsZero() {
+			// The end time of the endpoint is inclusive.
+			end = end.Add(-time.Millisecond)
+		}
+
+		page, err := s.GetKlines(ctx, q.Symbol, q.Interval, start, end, KlinesPageLimit)
+		if err != nil {
+			return err
+		}
+
+		if len(page) == 0 {
+			return nil
+		}
+
+		if err := fn(page); err != nil {
+			return err
+		}
+
+		if len(page) < KlinesPageLimit {
+			return nil
+		}
+
+		start = page[len(page)-1].OpenTime.Add(time.Millisecond)
+	}
+
+	return nil
+}
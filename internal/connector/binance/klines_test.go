@@ -0,0 +1,204 @@
+package binance_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	binance_connector "github.com/binance/binance-connector-go"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+	mock_binance "github.com/twk/trader-b/internal/connector/binance/mocks"
+)
+
+// klines returns n one minute klines opened from start.
+func klines(start time.Time, n int) []*binance_connector.KlinesResponse {
+	res := make([]*binance_connector.KlinesResponse, 0, n)
+
+	for i := 0; i < n; i++ {
+		open := uint64(start.Add(time.Duration(i) * time.Minute).UnixMilli())
+		res = append(res, &binance_connector.KlinesResponse{
+			OpenTime: open, Open: "100", High: "101", Low: "99", Close: "100.5", Volume: "2", CloseTime: open + 59999,
+			QuoteAssetVolume: "201", NumberOfTrades: 4,
+		})
+	}
+
+	return res
+}
+
+func TestIntervalDuration(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		duration time.Duration
+		err      error
+	}
+
+	tests := map[string]struct {
+		interval string
+		want     want
+	}{
+		"second":  {interval: "1s", want: want{duration: time.Second}},
+		"minutes": {interval: "15m", want: want{duration: 15 * time.Minute}},
+		"hours":   {interval: "4h", want: want{duration: 4 * time.Hour}},
+		"days":    {interval: "3d", want: want{duration: 72 * time.Hour}},
+		"week":    {interval: "1w", want: want{duration: 168 * time.Hour}},
+		"month":   {interval: "1M", want: want{err: errors.New(`unsupported kline interval: "1M"`)}},
+		"unknown": {interval: "7m", want: want{err: errors.New(`unsupported kline interval: "7m"`)}},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := binance.IntervalDuration(tt.interval)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				assert.ErrorIs(t, err, binance.ErrUnsupportedInterval)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.duration, d)
+		})
+	}
+}
+
+func TestService_GetKlines(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	start := time.UnixMilli(1700000040000).UTC()
+
+	mockClient := mock_binance.NewMockClient(ctrl)
+	mockKlinesClient := mock_binance.NewMockKlinesClient(ctrl)
+	mockClient.EXPECT().NewKlinesService("BTCUSDT", "1m", start, time.Time{}, 1).Return(mockKlinesClient)
+	mockKlinesClient.EXPECT().Do(gomock.Any()).Return(klines(start, 1), nil)
+
+	candles, err := binance.NewService(mockClient).GetKlines(context.Background(), "BTCUSDT", "1m", start, time.Time{}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []connector.Candle{{
+		Symbol: "BTCUSDT", Interval: "1m", OpenTime: start, CloseTime: start.Add(time.Minute - time.Millisecond),
+		Open: decimal.RequireFromString("100"), High: decimal.RequireFromString("101"), Low: decimal.RequireFromString("99"),
+		Close: decimal.RequireFromString("100.5"), Volume: decimal.RequireFromString("2"), QuoteVolume: decimal.RequireFromString("201"), Trades: 4,
+	}}, candles)
+}
+
+func TestService_FetchKlines(t *testing.T) {
+	type fields struct {
+		mockOperation func(client *mock_binance.MockClient, klinesClient *mock_binance.MockKlinesClient)
+		fnErr         error
+	}
+
+	type want struct {
+		pages []int
+		err   error
+	}
+
+	start := time.UnixMilli(1700000040000).UTC()
+	end := start.Add(2000 * time.Minute)
+	second := start.Add((binance.KlinesPageLimit-1)*time.Minute + time.Millisecond)
+
+	tests := map[string]struct {
+		interval string
+		fields   fields
+		want     want
+	}{
+		"Pages until a short page": {
+			interval: "1m",
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, klinesClient *mock_binance.MockKlinesClient) {
+					gomock.InOrder(
+						client.EXPECT().NewKlinesService("BTCUSDT", "1m", start, end.Add(-time.Millisecond), binance.KlinesPageLimit).Return(klinesClient),
+						klinesClient.EXPECT().Do(gomock.Any()).Return(klines(start, binance.KlinesPageLimit), nil),
+						client.EXPECT().NewKlinesService("BTCUSDT", "1m", second, end.Add(-time.Millisecond), binance.KlinesPageLimit).Return(klinesClient),
+						klinesClient.EXPECT().Do(gomock.Any()).Return(klines(start.Add(binance.KlinesPageLimit*time.Minute), 3), nil),
+					)
+				},
+			},
+			want: want{pages: []int{binance.KlinesPageLimit, 3}},
+		},
+		"Empty page": {
+			interval: "1m",
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, klinesClient *mock_binance.MockKlinesClient) {
+					client.EXPECT().NewKlinesService("BTCUSDT", "1m", start, end.Add(-time.Millisecond), binance.KlinesPageLimit).Return(klinesClient)
+					klinesClient.EXPECT().Do(gomock.Any()).Return(nil, nil)
+				},
+			},
+		},
+		"Do error": {
+			interval: "1m",
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, klinesClient *mock_binance.MockKlinesClient) {
+					client.EXPECT().NewKlinesService("BTCUSDT", "1m", start, end.Add(-time.Millisecond), binance.KlinesPageLimit).Return(klinesClient)
+					klinesClient.EXPECT().Do(gomock.Any()).Return(nil, errors.New("do error"))
+				},
+			},
+			want: want{err: errors.New("error getting 1m klines of BTCUSDT: do error")},
+		},
+		"Callback error stops the download": {
+			interval: "1m",
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, klinesClient *mock_binance.MockKlinesClient) {
+					client.EXPECT().NewKlinesService("BTCUSDT", "1m", start, end.Add(-time.Millisecond), binance.KlinesPageLimit).Return(klinesClient)
+					klinesClient.EXPECT().Do(gomock.Any()).Return(klines(start, binance.KlinesPageLimit), nil)
+				},
+				fnErr: errors.New("disk full"),
+			},
+			want: want{pages: []int{binance.KlinesPageLimit}, err: errors.New("disk full")},
+		},
+		"Invalid kline": {
+			interval: "1m",
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, klinesClient *mock_binance.MockKlinesClient) {
+					client.EXPECT().NewKlinesService("BTCUSDT", "1m", start, end.Add(-time.Millisecond), binance.KlinesPageLimit).Return(klinesClient)
+					klinesClient.EXPECT().Do(gomock.Any()).Return([]*binance_connector.KlinesResponse{{OpenTime: 1700000040000, Open: "abc"}}, nil)
+				},
+			},
+			want: want{err: errors.New("error parsing kline of BTCUSDT opened at 1700000040000: error parsing decimal: can't convert abc to decimal")},
+		},
+		"Unsupported interval": {
+			interval: "1M",
+			fields: fields{
+				mockOperation: func(_ *mock_binance.MockClient, _ *mock_binance.MockKlinesClient) {},
+			},
+			want: want{err: errors.New(`unsupported kline interval: "1M"`)},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock_binance.NewMockClient(ctrl)
+			mockKlinesClient := mock_binance.NewMockKlinesClient(ctrl)
+			tt.fields.mockOperation(mockClient, mockKlinesClient)
+
+			var pages []int
+
+			q := binance.KlinesQuery{Symbol: "BTCUSDT", Interval: tt.interval, Start: start, End: end}
+
+			err := binance.NewService(mockClient).FetchKlines(context.Background(), q, func(candles []connector.Candle) error {
+				pages = append(pages, len(candles))
+				return tt.fields.fnErr
+			})
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want.pages, pages)
+		})
+	}
+}
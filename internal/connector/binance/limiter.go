@@ -0,0 +1,353 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The kinds of the Binance rate limits, as named in the exchange info.
+const (
+	RateLimitRequestWeight = "REQUEST_WEIGHT"
+	RateLimitOrders        = "ORDERS"
+)
+
+// The limits of the Binance spot API.
+const (
+	defaultWeightLimit     = 6000
+	defaultOrderLimit      = 100
+	defaultDailyOrderLimit = 200000
+	defaultOrderInterval   = 10 * time.Second
+	day                    = 24 * time.Hour
+)
+
+// The response headers reporting the usage of the limits, followed by their interval, e.g. X-MBX-USED-WEIGHT-1M.
+const (
+	weightHeaderPrefix     = "X-Mbx-Used-Weight-"
+	orderCountHeaderPrefix = "X-Mbx-Order-Count-"
+)
+
+// statusIPBanned is the status of the responses to an IP banned for exceeding the limits after a 429 response.
+const statusIPBanned = 418
+
+// defaultDepthLimit is the limit of the order book endpoint when the request has none.
+const defaultDepthLimit = 100
+
+// endpointWeight is the weight of an endpoint, which is lower when the request is made for a single symbol.
+type endpointWeight struct {
+	weight       int
+	symbolWeight int
+	// orders is the number of orders placed by a request.
+	orders int
+}
+
+// endpointWeights returns the weights of the Binance spot endpoints keyed by method and path. Other endpoints weigh 1.
+func endpointWeights() map[string]endpointWeight {
+	return map[string]endpointWeight{
+		"POST /api/v3/order":               {weight: 1, orders: 1},
+		"POST /api/v3/order/cancelReplace": {weight: 1, orders: 1},
+		"POST /api/v3/sor/order":           {weight: 1, orders: 1},
+		"GET /api/v3/order":                {weight: 4},
+		"GET /api/v3/openOrders":           {weight: 80, symbolWeight: 6},
+		"GET /api/v3/allOrders":            {weight: 20},
+		"GET /api/v3/account":              {weight: 20},
+		"GET /api/v3/myTrades":             {weight: 20},
+		"GET /api/v3/exchangeInfo":         {weight: 20},
+		"GET /api/v3/trades":               {weight: 25},
+		"GET /api/v3/historicalTrades":     {weight: 25},
+		"GET /api/v3/aggTrades":            {weight: 2},
+		"GET /api/v3/klines":               {weight: 2},
+		"GET /api/v3/uiKlines":             {weight: 2},
+		"GET /api/v3/avgPrice":             {weight: 2},
+		"GET /api/v3/ticker/price":         {weight: 4, symbolWeight: 2},
+		"GET /api/v3/ticker/bookTicker":    {weight: 4, symbolWeight: 2},
+		"GET /api/v3/ticker/24hr":          {weight: 80, symbolWeight: 2},
+		"POST /api/v3/userDataStream":      {weight: 2},
+		"PUT /api/v3/userDataStream":       {weight: 2},
+		"DELETE /api/v3/userDataStream":    {weight: 2},
+	}
+}
+
+// depthWeights returns the weights of the order book endpoint by the upper bound of its limit.
+func depthWeights() []struct{ limit, weight int } {
+	return []struct{ limit, weight int }{{100, 5}, {500, 25}, {1000, 50}, {5000, 250}}
+}
+
+// RateLimit is a limit of the Binance API on the request weight or the number of orders of an interval.
+// The intervals start at the multiples of their duration in UTC, like the intervals of Binance.
+type RateLimit struct {
+	Kind     string
+	Interval time.Duration
+	Limit    int
+}
+
+// RateLimitUsage is the usage of a rate limit in its current interval.
+type RateLimitUsage struct {
+	RateLimit
+	Used int
+}
+
+// DefaultRateLimits returns the rate limits of the Binance spot API.
+func DefaultRateLimits() []RateLimit {
+	return []RateLimit{
+		{Kind: RateLimitRequestWeight, Interval: time.Minute, Limit: defaultWeightLimit},
+		{Kind: RateLimitOrders, Interval: defaultOrderInterval, Limit: defaultOrderLimit},
+		{Kind: RateLimitOrders, Interval: day, Limit: defaultDailyOrderLimit},
+	}
+}
+
+// rateWindow counts the usage of a rate limit in its current interval.
+type rateWindow struct {
+	RateLimit
+	start time.Time
+	used  int
+}
+
+// roll starts a new interval when the current one is over.
+func (w *rateWindow) roll(now time.Time) {
+	if start := now.Truncate(w.Interval); start.After(w.start) {
+		w.start, w.used = start, 0
+	}
+}
+
+// cost returns the usage of a request of the weight placing the orders.
+func (w *rateWindow) cost(weight, orders int) int {
+	if w.Kind == RateLimitOrders {
+		return orders
+	}
+
+	return weight
+}
+
+// fits reports whether the cost fits in the interval. A cost above the limit fits an unused interval, so it is not blocked forever.
+func (w *rateWindow) fits(cost int) bool {
+	return cost == 0 || w.used == 0 || w.used+cost <= w.Limit
+}
+
+// header returns the response header reporting the usage of the interval, e.g. X-MBX-USED-WEIGHT-1M.
+func (w *rateWindow) header() string {
+	prefix := weightHeaderPrefix
+	if w.Kind == RateLimitOrders {
+		prefix = orderCountHeaderPrefix
+	}
+
+	switch {
+	case w.Interval%day == 0:
+		return prefix + strconv.Itoa(int(w.Interval/day)) + "d"
+	case w.Interval%time.Hour == 0:
+		return prefix + strconv.Itoa(int(w.Interval/time.Hour)) + "h"
+	case w.Interval%time.Minute == 0:
+		return prefix + strconv.Itoa(int(w.Interval/time.Minute)) + "m"
+	default:
+		return prefix + strconv.Itoa(int(w.Interval/time.Second)) + "s"
+	}
+}
+
+// Limiter keeps the requests to the Binance REST API within its rate limits. It is an http.RoundTripper, so it is put in front of
+// every call of the connector client. Requests that would exceed a limit block until its interval is over, and the usage is synced
+// with the used weight and order count headers of the responses, which also count the requests of other clients of the IP.
+// After a 429 or 418 response, every request blocks for the Retry-After of the response.
+type Limiter struct {
+	next         http.RoundTripper
+	now          func() time.Time
+	weights      map[string]endpointWeight
+	mu           sync.Mutex
+	windows      []*rateWindow
+	blockedUntil time.Time
+}
+
+// LimiterOption configures the limiter.
+type LimiterOption func(*Limiter)
+
+// WithRateLimits replaces the default rate limits, e.g. by the limits of the exchange info.
+func WithRateLimits(limits ...RateLimit) LimiterOption {
+	return func(l *Limiter) {
+		l.windows = newRateWindows(limits)
+	}
+}
+
+// WithLimiterClock sets the clock of the limiter.
+func WithLimiterClock(now func() time.Time) LimiterOption {
+	return func(l *Limiter) {
+		l.now = now
+	}
+}
+
+// NewLimiter creates a limiter sending the requests with next, or with http.DefaultTransport when it is nil.
+func NewLimiter(next http.RoundTripper, opts ...LimiterOption) *Limiter {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	l := &Limiter{next: next, now: time.Now, weights: endpointWeights(), windows: newRateWindows(DefaultRateLimits())}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+func newRateWindows(limits []RateLimit) []*rateWindow {
+	windows := make([]*rateWindow, 0, len(limits))
+	for _, limit := range limits {
+		windows = append(windows, &rateWindow{RateLimit: limit})
+	}
+
+	return windows
+}
+
+// RoundTrip waits until the request fits the rate limits, sends it and syncs the usage with the response.
+func (l *Limiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	weight, orders := l.cost(req)
+
+	if err := l.wait(req.Context(), weight, orders); err != nil {
+		return nil, err
+	}
+
+	resp, err := l.next.RoundTrip(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+
+	l.sync(resp)
+
+	return resp, nil
+}
+
+// Usage returns the usage of the rate limits in their current intervals.
+func (l *Limiter) Usage() []RateLimitUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	usage := make([]RateLimitUsage, 0, len(l.windows))
+
+	for _, w := range l.windows {
+		w.roll(now)
+		usage = append(usage, RateLimitUsage{RateLimit: w.RateLimit, Used: w.used})
+	}
+
+	return usage
+}
+
+// wait blocks until the cost of the request is reserved in every interval, or the context is done.
+func (l *Limiter) wait(ctx context.Context, weight, orders int) error {
+	for {
+		d := l.reserve(weight, orders)
+		if d <= 0 {
+			return nil
+		}
+
+		t := time.NewTimer(d)
+
+		select {
+		case <-ctx.Done():
+			t.Stop()
+
+			return fmt.Errorf("error waiting for the binance rate limit: %w", ctx.Err())
+		case <-t.C:
+		}
+	}
+}
+
+// reserve adds the cost of the request to every interval when it fits all of them,
+// otherwise it returns the time until the intervals it does not fit are over.
+func (l *Limiter) reserve(weight, orders int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Before(l.blockedUntil) {
+		return l.blockedUntil.Sub(now)
+	}
+
+	var wait time.Duration
+
+	for _, w := range l.windows {
+		w.roll(now)
+
+		if !w.fits(w.cost(weight, orders)) {
+			wait = max(wait, w.start.Add(w.Interval).Sub(now))
+		}
+	}
+
+	if wait > 0 {
+		return wait
+	}
+
+	for _, w := range l.windows {
+		w.used += w.cost(weight, orders)
+	}
+
+	return 0
+}
+
+// sync raises the usage of the intervals to the usage reported by the response, and blocks the requests when the response
+// reports that a limit was exceeded.
+func (l *Limiter) sync(resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	for _, w := range l.windows {
+		used, err := strconv.Atoi(resp.Header.Get(w.header()))
+		if err != nil {
+			continue
+		}
+
+		w.roll(now)
+		w.used = max(w.used, used)
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != statusIPBanned {
+		return
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		l.blockedUntil = now.Add(time.Duration(seconds) * time.Second)
+	}
+}
+
+// cost returns the weight of the request and the number of orders it places.
+func (l *Limiter) cost(req *http.Request) (int, int) {
+	q := req.URL.Query()
+	key := req.Method + " " + strings.TrimSuffix(req.URL.Path, "/")
+
+	if key == "GET /api/v3/depth" {
+		return depthWeight(q.Get("limit")), 0
+	}
+
+	w, ok := l.weights[key]
+	if !ok {
+		return 1, 0
+	}
+
+	if w.symbolWeight > 0 && q.Has("symbol") {
+		return w.symbolWeight, w.orders
+	}
+
+	return w.weight, w.orders
+}
+
+// depthWeight returns the weight of the order book endpoint for the limit.
+func depthWeight(limit string) int {
+	n, err := strconv.Atoi(limit)
+	if err != nil {
+		n = defaultDepthLimit
+	}
+
+	weights := depthWeights()
+	for _, w := range weights {
+		if n <= w.limit {
+			return w.weight
+		}
+	}
+
+	return weights[len(weights)-1].weight
+}
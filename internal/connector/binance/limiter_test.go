@@ -0,0 +1,188 @@
+package binance_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector/binance"
+)
+
+// newLimitedServer serves empty responses with the status and headers, and counts the requests it receives.
+func newLimitedServer(t *testing.T, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+
+		w.WriteHeader(status)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func send(ctx context.Context, l *binance.Limiter, method, url string) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := (&http.Client{Transport: l}).Do(req)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// used returns the usage of the limits as kind/interval: used.
+func used(l *binance.Limiter) map[string]int {
+	res := make(map[string]int)
+	for _, u := range l.Usage() {
+		res[u.Kind+"/"+u.Interval.String()] = u.Used
+	}
+
+	return res
+}
+
+func TestLimiter_Weights(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		weight int
+		orders int
+	}
+
+	tests := map[string]struct {
+		method string
+		path   string
+		want   want
+	}{
+		"Order book with the default limit":     {method: http.MethodGet, path: "/api/v3/depth", want: want{weight: 5}},
+		"Order book with a limit of 1000":       {method: http.MethodGet, path: "/api/v3/depth?limit=1000", want: want{weight: 50}},
+		"Order book with a limit of 5000":       {method: http.MethodGet, path: "/api/v3/depth?limit=5000", want: want{weight: 250}},
+		"Ticker price of a symbol":              {method: http.MethodGet, path: "/api/v3/ticker/price?symbol=BTCUSDT", want: want{weight: 2}},
+		"Ticker price of every symbol":          {method: http.MethodGet, path: "/api/v3/ticker/price", want: want{weight: 4}},
+		"Open orders of a symbol":               {method: http.MethodGet, path: "/api/v3/openOrders?symbol=BTCUSDT", want: want{weight: 6}},
+		"Open orders of every symbol":           {method: http.MethodGet, path: "/api/v3/openOrders", want: want{weight: 80}},
+		"Account":                               {method: http.MethodGet, path: "/api/v3/account", want: want{weight: 20}},
+		"New order":                             {method: http.MethodPost, path: "/api/v3/order", want: want{weight: 1, orders: 1}},
+		"Test order does not count as an order": {method: http.MethodPost, path: "/api/v3/order/test", want: want{weight: 1}},
+		"Cancel order":                          {method: http.MethodDelete, path: "/api/v3/order", want: want{weight: 1}},
+		"Keep alive of a listen key":            {method: http.MethodPut, path: "/api/v3/userDataStream", want: want{weight: 2}},
+		"Unknown endpoint":                      {method: http.MethodGet, path: "/api/v3/ping", want: want{weight: 1}},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server, _ := newLimitedServer(t, http.StatusOK, nil)
+			l := binance.NewLimiter(nil)
+
+			assert.NoError(t, send(context.Background(), l, tt.method, server.URL+tt.path))
+			assert.Equal(t, map[string]int{
+				"REQUEST_WEIGHT/1m0s": tt.want.weight,
+				"ORDERS/10s":          tt.want.orders,
+				"ORDERS/24h0m0s":      tt.want.orders,
+			}, used(l))
+		})
+	}
+}
+
+func TestLimiter_SyncsWithHeaders(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newLimitedServer(t, http.StatusOK, http.Header{
+		"X-MBX-USED-WEIGHT-1M":  {"1234"},
+		"X-MBX-ORDER-COUNT-10S": {"3"},
+		"X-MBX-ORDER-COUNT-1D":  {"42"},
+		"X-MBX-USED-WEIGHT-5M":  {"99999"},
+	})
+	l := binance.NewLimiter(nil)
+
+	assert.NoError(t, send(context.Background(), l, http.MethodGet, server.URL+"/api/v3/account"))
+	assert.Equal(t, map[string]int{"REQUEST_WEIGHT/1m0s": 1234, "ORDERS/10s": 3, "ORDERS/24h0m0s": 42}, used(l))
+}
+
+func TestLimiter_Blocks(t *testing.T) {
+	t.Parallel()
+
+	const interval = 200 * time.Millisecond
+
+	server, requests := newLimitedServer(t, http.StatusOK, nil)
+	l := binance.NewLimiter(nil, binance.WithRateLimits(binance.RateLimit{Kind: binance.RateLimitRequestWeight, Interval: interval, Limit: 30}))
+
+	next := time.Now().Truncate(interval).Add(interval)
+
+	// A request weighing more than the limit is not blocked forever.
+	assert.NoError(t, send(context.Background(), l, http.MethodGet, server.URL+"/api/v3/openOrders"))
+
+	assert.NoError(t, send(context.Background(), l, http.MethodGet, server.URL+"/api/v3/account"))
+	assert.False(t, time.Now().Before(next), "the request waits for the next interval")
+
+	ctx, cancel := context.WithTimeout(context.Background(), interval/10)
+	defer cancel()
+
+	assert.NoError(t, send(ctx, l, http.MethodGet, server.URL+"/api/v3/ping"), "the request fits the interval")
+
+	err := send(ctx, l, http.MethodGet, server.URL+"/api/v3/account")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "error waiting for the binance rate limit")
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestLimiter_BlocksAfterRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newLimitedServer(t, http.StatusTooManyRequests, http.Header{"Retry-After": {"60"}})
+	l := binance.NewLimiter(nil)
+
+	assert.NoError(t, send(context.Background(), l, http.MethodGet, server.URL+"/api/v3/ping"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, send(ctx, l, http.MethodGet, server.URL+"/api/v3/ping"), context.DeadlineExceeded)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestNewBinanceService_RateLimitUsage(t *testing.T) {
+	t.Parallel()
+
+	server := newBinanceServer(t, "/api/v3/account", http.StatusOK, `{"balances":[]}`)
+	defer server.Close()
+
+	cfg := &config.Config{Connector: config.Connector{Binance: config.Binance{BaseURL: server.URL, APIKey: "key", SecretKey: "secret"}}}
+
+	s, err := binance.NewBinanceService(cfg)
+	assert.NoError(t, err)
+
+	_, err = s.GetAccount(context.Background())
+	assert.NoError(t, err)
+
+	usage := s.RateLimitUsage()
+	assert.Len(t, usage, 3)
+	assert.Equal(t, binance.RateLimitUsage{
+		RateLimit: binance.RateLimit{Kind: binance.RateLimitRequestWeight, Interval: time.Minute, Limit: 6000},
+		Used:      21,
+	}, usage[0], "the weight of the time sync of the service and of the account request")
+
+	assert.Nil(t, binance.NewService(nil).RateLimitUsage())
+}
@@ -7,9 +7,11 @@ package mock_binance
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	binance_connector "github.com/binance/binance-connector-go"
 	gomock "github.com/golang/mock/gomock"
+	connector "github.com/twk/trader-b/internal/connector"
 	binance "github.com/twk/trader-b/internal/connector/binance"
 )
 
@@ -80,14 +82,14 @@ func (m *MockExchangeInfoClient) EXPECT() *MockExchangeInfoClientMockRecorder {
 }
 
 // Do mocks base method.
-func (m *MockExchangeInfoClient) Do(ctx context.Context, opts ...binance_connector.RequestOption) (*binance_connector.ExchangeInfoResponse, error) {
+func (m *MockExchangeInfoClient) Do(ctx context.Context, opts ...binance_connector.RequestOption) (*binance.ExchangeInfoResponse, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{ctx}
 	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "Do", varargs...)
-	ret0, _ := ret[0].(*binance_connector.ExchangeInfoResponse)
+	ret0, _ := ret[0].(*binance.ExchangeInfoResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -99,6 +101,521 @@ func (mr *MockExchangeInfoClientMockRecorder) Do(ctx interface{}, opts ...interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockExchangeInfoClient)(nil).Do), varargs...)
 }
 
+// MockTickerPriceClient is a mock of TickerPriceClient interface.
+type MockTickerPriceClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockTickerPriceClientMockRecorder
+}
+
+// MockTickerPriceClientMockRecorder is the mock recorder for MockTickerPriceClient.
+type MockTickerPriceClientMockRecorder struct {
+	mock *MockTickerPriceClient
+}
+
+// NewMockTickerPriceClient creates a new mock instance.
+func NewMockTickerPriceClient(ctrl *gomock.Controller) *MockTickerPriceClient {
+	mock := &MockTickerPriceClient{ctrl: ctrl}
+	mock.recorder = &MockTickerPriceClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTickerPriceClient) EXPECT() *MockTickerPriceClientMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockTickerPriceClient) Do(ctx context.Context, opts ...binance_connector.RequestOption) (*binance_connector.TickerPriceResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Do", varargs...)
+	ret0, _ := ret[0].(*binance_connector.TickerPriceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockTickerPriceClientMockRecorder) Do(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockTickerPriceClient)(nil).Do), varargs...)
+}
+
+// MockOrderBookClient is a mock of OrderBookClient interface.
+type MockOrderBookClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderBookClientMockRecorder
+}
+
+// MockOrderBookClientMockRecorder is the mock recorder for MockOrderBookClient.
+type MockOrderBookClientMockRecorder struct {
+	mock *MockOrderBookClient
+}
+
+// NewMockOrderBookClient creates a new mock instance.
+func NewMockOrderBookClient(ctrl *gomock.Controller) *MockOrderBookClient {
+	mock := &MockOrderBookClient{ctrl: ctrl}
+	mock.recorder = &MockOrderBookClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderBookClient) EXPECT() *MockOrderBookClientMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockOrderBookClient) Do(ctx context.Context, opts ...binance_connector.RequestOption) (*binance_connector.OrderBookResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Do", varargs...)
+	ret0, _ := ret[0].(*binance_connector.OrderBookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockOrderBookClientMockRecorder) Do(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockOrderBookClient)(nil).Do), varargs...)
+}
+
+// MockRecentTradesClient is a mock of RecentTradesClient interface.
+type MockRecentTradesClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecentTradesClientMockRecorder
+}
+
+// MockRecentTradesClientMockRecorder is the mock recorder for MockRecentTradesClient.
+type MockRecentTradesClientMockRecorder struct {
+	mock *MockRecentTradesClient
+}
+
+// NewMockRecentTradesClient creates a new mock instance.
+func NewMockRecentTradesClient(ctrl *gomock.Controller) *MockRecentTradesClient {
+	mock := &MockRecentTradesClient{ctrl: ctrl}
+	mock.recorder = &MockRecentTradesClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecentTradesClient) EXPECT() *MockRecentTradesClientMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockRecentTradesClient) Do(ctx context.Context, opts ...binance_connector.RequestOption) ([]*binance_connector.RecentTradesListResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Do", varargs...)
+	ret0, _ := ret[0].([]*binance_connector.RecentTradesListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockRecentTradesClientMockRecorder) Do(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockRecentTradesClient)(nil).Do), varargs...)
+}
+
+// MockCreateOrderClient is a mock of CreateOrderClient interface.
+type MockCreateOrderClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreateOrderClientMockRecorder
+}
+
+// MockCreateOrderClientMockRecorder is the mock recorder for MockCreateOrderClient.
+type MockCreateOrderClientMockRecorder struct {
+	mock *MockCreateOrderClient
+}
+
+// NewMockCreateOrderClient creates a new mock instance.
+func NewMockCreateOrderClient(ctrl *gomock.Controller) *MockCreateOrderClient {
+	mock := &MockCreateOrderClient{ctrl: ctrl}
+	mock.recorder = &MockCreateOrderClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCreateOrderClient) EXPECT() *MockCreateOrderClientMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockCreateOrderClient) Do(ctx context.Context, opts ...binance_connector.RequestOption) (interface{}, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Do", varargs...)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockCreateOrderClientMockRecorder) Do(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockCreateOrderClient)(nil).Do), varargs...)
+}
+
+// MockTestOrderClient is a mock of TestOrderClient interface.
+type MockTestOrderClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockTestOrderClientMockRecorder
+}
+
+// MockTestOrderClientMockRecorder is the mock recorder for MockTestOrderClient.
+type MockTestOrderClientMockRecorder struct {
+	mock *MockTestOrderClient
+}
+
+// NewMockTestOrderClient creates a new mock instance.
+func NewMockTestOrderClient(ctrl *gomock.Controller) *MockTestOrderClient {
+	mock := &MockTestOrderClient{ctrl: ctrl}
+	mock.recorder = &MockTestOrderClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTestOrderClient) EXPECT() *MockTestOrderClientMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockTestOrderClient) Do(ctx context.Context, opts ...binance_connector.RequestOption) (*binance_connector.AccountOrderBookResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Do", varargs...)
+	ret0, _ := ret[0].(*binance_connector.AccountOrderBookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockTestOrderClientMockRecorder) Do(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockTestOrderClient)(nil).Do), varargs...)
+}
+
+// MockCancelOrderClient is a mock of CancelOrderClient interface.
+type MockCancelOrderClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockCancelOrderClientMockRecorder
+}
+
+// MockCancelOrderClientMockRecorder is the mock recorder for MockCancelOrderClient.
+type MockCancelOrderClientMockRecorder struct {
+	mock *MockCancelOrderClient
+}
+
+// NewMockCancelOrderClient creates a new mock instance.
+func NewMockCancelOrderClient(ctrl *gomock.Controller) *MockCancelOrderClient {
+	mock := &MockCancelOrderClient{ctrl: ctrl}
+	mock.recorder = &MockCancelOrderClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCancelOrderClient) EXPECT() *MockCancelOrderClientMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockCancelOrderClient) Do(ctx context.Context, opts ...binance_connector.RequestOption) (*binance_connector.CancelOrderResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Do", varargs...)
+	ret0, _ := ret[0].(*binance_connector.CancelOrderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockCancelOrderClientMockRecorder) Do(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockCancelOrderClient)(nil).Do), varargs...)
+}
+
+// MockGetOrderClient is a mock of GetOrderClient interface.
+type MockGetOrderClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetOrderClientMockRecorder
+}
+
+// MockGetOrderClientMockRecorder is the mock recorder for MockGetOrderClient.
+type MockGetOrderClientMockRecorder struct {
+	mock *MockGetOrderClient
+}
+
+// NewMockGetOrderClient creates a new mock instance.
+func NewMockGetOrderClient(ctrl *gomock.Controller) *MockGetOrderClient {
+	mock := &MockGetOrderClient{ctrl: ctrl}
+	mock.recorder = &MockGetOrderClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetOrderClient) EXPECT() *MockGetOrderClientMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockGetOrderClient) Do(ctx context.Context, opts ...binance_connector.RequestOption) (*binance_connector.GetOrderResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Do", varargs...)
+	ret0, _ := ret[0].(*binance_connector.GetOrderResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockGetOrderClientMockRecorder) Do(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockGetOrderClient)(nil).Do), varargs...)
+}
+
+// MockOpenOrdersClient is a mock of OpenOrdersClient interface.
+type MockOpenOrdersClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockOpenOrdersClientMockRecorder
+}
+
+// MockOpenOrdersClientMockRecorder is the mock recorder for MockOpenOrdersClient.
+type MockOpenOrdersClientMockRecorder struct {
+	mock *MockOpenOrdersClient
+}
+
+// NewMockOpenOrdersClient creates a new mock instance.
+func NewMockOpenOrdersClient(ctrl *gomock.Controller) *MockOpenOrdersClient {
+	mock := &MockOpenOrdersClient{ctrl: ctrl}
+	mock.recorder = &MockOpenOrdersClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOpenOrdersClient) EXPECT() *MockOpenOrdersClientMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockOpenOrdersClient) Do(ctx context.Context, opts ...binance_connector.RequestOption) ([]*binance_connector.NewOpenOrdersResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Do", varargs...)
+	ret0, _ := ret[0].([]*binance_connector.NewOpenOrdersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockOpenOrdersClientMockRecorder) Do(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockOpenOrdersClient)(nil).Do), varargs...)
+}
+
+// MockKlinesClient is a mock of KlinesClient interface.
+type MockKlinesClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockKlinesClientMockRecorder
+}
+
+// MockKlinesClientMockRecorder is the mock recorder for MockKlinesClient.
+type MockKlinesClientMockRecorder struct {
+	mock *MockKlinesClient
+}
+
+// NewMockKlinesClient creates a new mock instance.
+func NewMockKlinesClient(ctrl *gomock.Controller) *MockKlinesClient {
+	mock := &MockKlinesClient{ctrl: ctrl}
+	mock.recorder = &MockKlinesClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKlinesClient) EXPECT() *MockKlinesClientMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockKlinesClient) Do(ctx context.Context, opts ...binance_connector.RequestOption) ([]*binance_connector.KlinesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Do", varargs...)
+	ret0, _ := ret[0].([]*binance_connector.KlinesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockKlinesClientMockRecorder) Do(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockKlinesClient)(nil).Do), varargs...)
+}
+
+// MockListenKeyClient is a mock of ListenKeyClient interface.
+type MockListenKeyClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockListenKeyClientMockRecorder
+}
+
+// MockListenKeyClientMockRecorder is the mock recorder for MockListenKeyClient.
+type MockListenKeyClientMockRecorder struct {
+	mock *MockListenKeyClient
+}
+
+// NewMockListenKeyClient creates a new mock instance.
+func NewMockListenKeyClient(ctrl *gomock.Controller) *MockListenKeyClient {
+	mock := &MockListenKeyClient{ctrl: ctrl}
+	mock.recorder = &MockListenKeyClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockListenKeyClient) EXPECT() *MockListenKeyClientMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockListenKeyClient) Do(ctx context.Context, opts ...binance_connector.RequestOption) (string, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Do", varargs...)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockListenKeyClientMockRecorder) Do(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockListenKeyClient)(nil).Do), varargs...)
+}
+
+// MockUserStreamClient is a mock of UserStreamClient interface.
+type MockUserStreamClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserStreamClientMockRecorder
+}
+
+// MockUserStreamClientMockRecorder is the mock recorder for MockUserStreamClient.
+type MockUserStreamClientMockRecorder struct {
+	mock *MockUserStreamClient
+}
+
+// NewMockUserStreamClient creates a new mock instance.
+func NewMockUserStreamClient(ctrl *gomock.Controller) *MockUserStreamClient {
+	mock := &MockUserStreamClient{ctrl: ctrl}
+	mock.recorder = &MockUserStreamClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserStreamClient) EXPECT() *MockUserStreamClientMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockUserStreamClient) Do(ctx context.Context, opts ...binance_connector.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Do", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockUserStreamClientMockRecorder) Do(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockUserStreamClient)(nil).Do), varargs...)
+}
+
+// MockServerTimeClient is a mock of ServerTimeClient interface.
+type MockServerTimeClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockServerTimeClientMockRecorder
+}
+
+// MockServerTimeClientMockRecorder is the mock recorder for MockServerTimeClient.
+type MockServerTimeClientMockRecorder struct {
+	mock *MockServerTimeClient
+}
+
+// NewMockServerTimeClient creates a new mock instance.
+func NewMockServerTimeClient(ctrl *gomock.Controller) *MockServerTimeClient {
+	mock := &MockServerTimeClient{ctrl: ctrl}
+	mock.recorder = &MockServerTimeClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServerTimeClient) EXPECT() *MockServerTimeClientMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockServerTimeClient) Do(ctx context.Context, opts ...binance_connector.RequestOption) (*binance_connector.ServerTimeResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Do", varargs...)
+	ret0, _ := ret[0].(*binance_connector.ServerTimeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockServerTimeClientMockRecorder) Do(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockServerTimeClient)(nil).Do), varargs...)
+}
+
 // MockClient is a mock of Client interface.
 type MockClient struct {
 	ctrl     *gomock.Controller
@@ -122,6 +639,62 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// NewCancelOrderService mocks base method.
+func (m *MockClient) NewCancelOrderService(symbol string, orderID int64) binance.CancelOrderClient {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewCancelOrderService", symbol, orderID)
+	ret0, _ := ret[0].(binance.CancelOrderClient)
+	return ret0
+}
+
+// NewCancelOrderService indicates an expected call of NewCancelOrderService.
+func (mr *MockClientMockRecorder) NewCancelOrderService(symbol, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewCancelOrderService", reflect.TypeOf((*MockClient)(nil).NewCancelOrderService), symbol, orderID)
+}
+
+// NewCloseUserStreamService mocks base method.
+func (m *MockClient) NewCloseUserStreamService(listenKey string) binance.UserStreamClient {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewCloseUserStreamService", listenKey)
+	ret0, _ := ret[0].(binance.UserStreamClient)
+	return ret0
+}
+
+// NewCloseUserStreamService indicates an expected call of NewCloseUserStreamService.
+func (mr *MockClientMockRecorder) NewCloseUserStreamService(listenKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewCloseUserStreamService", reflect.TypeOf((*MockClient)(nil).NewCloseUserStreamService), listenKey)
+}
+
+// NewCreateListenKeyService mocks base method.
+func (m *MockClient) NewCreateListenKeyService() binance.ListenKeyClient {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewCreateListenKeyService")
+	ret0, _ := ret[0].(binance.ListenKeyClient)
+	return ret0
+}
+
+// NewCreateListenKeyService indicates an expected call of NewCreateListenKeyService.
+func (mr *MockClientMockRecorder) NewCreateListenKeyService() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewCreateListenKeyService", reflect.TypeOf((*MockClient)(nil).NewCreateListenKeyService))
+}
+
+// NewCreateOrderService mocks base method.
+func (m *MockClient) NewCreateOrderService(req connector.OrderRequest) binance.CreateOrderClient {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewCreateOrderService", req)
+	ret0, _ := ret[0].(binance.CreateOrderClient)
+	return ret0
+}
+
+// NewCreateOrderService indicates an expected call of NewCreateOrderService.
+func (mr *MockClientMockRecorder) NewCreateOrderService(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewCreateOrderService", reflect.TypeOf((*MockClient)(nil).NewCreateOrderService), req)
+}
+
 // NewExchangeInfoService mocks base method.
 func (m *MockClient) NewExchangeInfoService() binance.ExchangeInfoClient {
 	m.ctrl.T.Helper()
@@ -149,3 +722,129 @@ func (mr *MockClientMockRecorder) NewGetAccountService() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewGetAccountService", reflect.TypeOf((*MockClient)(nil).NewGetAccountService))
 }
+
+// NewGetOrderService mocks base method.
+func (m *MockClient) NewGetOrderService(symbol string, orderID int64) binance.GetOrderClient {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewGetOrderService", symbol, orderID)
+	ret0, _ := ret[0].(binance.GetOrderClient)
+	return ret0
+}
+
+// NewGetOrderService indicates an expected call of NewGetOrderService.
+func (mr *MockClientMockRecorder) NewGetOrderService(symbol, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewGetOrderService", reflect.TypeOf((*MockClient)(nil).NewGetOrderService), symbol, orderID)
+}
+
+// NewKlinesService mocks base method.
+func (m *MockClient) NewKlinesService(symbol, interval string, start, end time.Time, limit int) binance.KlinesClient {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewKlinesService", symbol, interval, start, end, limit)
+	ret0, _ := ret[0].(binance.KlinesClient)
+	return ret0
+}
+
+// NewKlinesService indicates an expected call of NewKlinesService.
+func (mr *MockClientMockRecorder) NewKlinesService(symbol, interval, start, end, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewKlinesService", reflect.TypeOf((*MockClient)(nil).NewKlinesService), symbol, interval, start, end, limit)
+}
+
+// NewOpenOrdersService mocks base method.
+func (m *MockClient) NewOpenOrdersService(symbol string) binance.OpenOrdersClient {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewOpenOrdersService", symbol)
+	ret0, _ := ret[0].(binance.OpenOrdersClient)
+	return ret0
+}
+
+// NewOpenOrdersService indicates an expected call of NewOpenOrdersService.
+func (mr *MockClientMockRecorder) NewOpenOrdersService(symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewOpenOrdersService", reflect.TypeOf((*MockClient)(nil).NewOpenOrdersService), symbol)
+}
+
+// NewOrderBookService mocks base method.
+func (m *MockClient) NewOrderBookService(symbol string, limit int) binance.OrderBookClient {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewOrderBookService", symbol, limit)
+	ret0, _ := ret[0].(binance.OrderBookClient)
+	return ret0
+}
+
+// NewOrderBookService indicates an expected call of NewOrderBookService.
+func (mr *MockClientMockRecorder) NewOrderBookService(symbol, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewOrderBookService", reflect.TypeOf((*MockClient)(nil).NewOrderBookService), symbol, limit)
+}
+
+// NewPingUserStreamService mocks base method.
+func (m *MockClient) NewPingUserStreamService(listenKey string) binance.UserStreamClient {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewPingUserStreamService", listenKey)
+	ret0, _ := ret[0].(binance.UserStreamClient)
+	return ret0
+}
+
+// NewPingUserStreamService indicates an expected call of NewPingUserStreamService.
+func (mr *MockClientMockRecorder) NewPingUserStreamService(listenKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewPingUserStreamService", reflect.TypeOf((*MockClient)(nil).NewPingUserStreamService), listenKey)
+}
+
+// NewRecentTradesService mocks base method.
+func (m *MockClient) NewRecentTradesService(symbol string, limit int) binance.RecentTradesClient {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewRecentTradesService", symbol, limit)
+	ret0, _ := ret[0].(binance.RecentTradesClient)
+	return ret0
+}
+
+// NewRecentTradesService indicates an expected call of NewRecentTradesService.
+func (mr *MockClientMockRecorder) NewRecentTradesService(symbol, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewRecentTradesService", reflect.TypeOf((*MockClient)(nil).NewRecentTradesService), symbol, limit)
+}
+
+// NewServerTimeService mocks base method.
+func (m *MockClient) NewServerTimeService() binance.ServerTimeClient {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewServerTimeService")
+	ret0, _ := ret[0].(binance.ServerTimeClient)
+	return ret0
+}
+
+// NewServerTimeService indicates an expected call of NewServerTimeService.
+func (mr *MockClientMockRecorder) NewServerTimeService() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewServerTimeService", reflect.TypeOf((*MockClient)(nil).NewServerTimeService))
+}
+
+// NewTestOrderService mocks base method.
+func (m *MockClient) NewTestOrderService(req connector.OrderRequest) binance.TestOrderClient {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewTestOrderService", req)
+	ret0, _ := ret[0].(binance.TestOrderClient)
+	return ret0
+}
+
+// NewTestOrderService indicates an expected call of NewTestOrderService.
+func (mr *MockClientMockRecorder) NewTestOrderService(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewTestOrderService", reflect.TypeOf((*MockClient)(nil).NewTestOrderService), req)
+}
+
+// NewTickerPriceService mocks base method.
+func (m *MockClient) NewTickerPriceService(symbol string) binance.TickerPriceClient {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewTickerPriceService", symbol)
+	ret0, _ := ret[0].(binance.TickerPriceClient)
+	return ret0
+}
+
+// NewTickerPriceService indicates an expected call of NewTickerPriceService.
+func (mr *MockClientMockRecorder) NewTickerPriceService(symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewTickerPriceService", reflect.TypeOf((*MockClient)(nil).NewTickerPriceService), symbol)
+}
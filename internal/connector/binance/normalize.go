@@ -0,0 +1,232 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// FilterError is returned when an order would be rejected by a filter of the symbol.
+type FilterError struct {
+	Symbol string
+	Filter string
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("%s would reject the order on %s: %s", e.Filter, e.Symbol, e.Reason)
+}
+
+// NormalizedOrder holds the price and quantity of an order rounded to the filters of the symbol.
+// The price of a market order is the price it is valued at by the notional filters.
+type NormalizedOrder struct {
+	Symbol   string
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+}
+
+// Notional returns the value of the order in the quote asset.
+func (o NormalizedOrder) Notional() decimal.Decimal {
+	return o.Price.Mul(o.Quantity)
+}
+
+type symbolLookup interface {
+	Symbol(ctx context.Context, name string) (connector.Symbol, error)
+}
+
+type tickerGetter interface {
+	GetTickers(ctx context.Context, symbols []string) ([]connector.Ticker, error)
+}
+
+// Normalizer rounds the price and quantity of orders to the filters of the symbols in the catalog.
+type Normalizer struct {
+	catalog symbolLookup
+	tickers tickerGetter
+}
+
+// NormalizerOption configures the normalizer.
+type NormalizerOption func(*Normalizer)
+
+// WithLastPrices values the market orders at the last price of their symbol, so that they are checked by the notional filters
+// applying to them as Binance checks them. The market orders are not checked by the notional filters when it is not set.
+func WithLastPrices(tickers tickerGetter) NormalizerOption {
+	return func(n *Normalizer) {
+		n.tickers = tickers
+	}
+}
+
+// NewNormalizer creates a new normalizer.
+func NewNormalizer(catalog symbolLookup, opts ...NormalizerOption) *Normalizer {
+	n := &Normalizer{catalog: catalog}
+
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	return n
+}
+
+// Normalize rounds the price and quantity to the filters of the symbol. See NormalizeOrder.
+// The price of a market order is replaced by the last price of the symbol when the normalizer gets the last prices.
+func (n *Normalizer) Normalize(ctx context.Context, symbol string, orderType connector.OrderType, price, quantity decimal.Decimal) (NormalizedOrder, error) {
+	s, err := n.catalog.Symbol(ctx, symbol)
+	if err != nil {
+		return NormalizedOrder{}, fmt.Errorf("error getting symbol: %w", err)
+	}
+
+	if orderType == connector.OrderTypeMarket && n.tickers != nil {
+		if price, err = n.lastPrice(ctx, symbol); err != nil {
+			return NormalizedOrder{}, err
+		}
+	}
+
+	return NormalizeOrder(s, orderType, price, quantity)
+}
+
+func (n *Normalizer) lastPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	tickers, err := n.tickers.GetTickers(ctx, []string{symbol})
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("error getting last price: %w", err)
+	}
+
+	if len(tickers) == 0 {
+		return decimal.Zero, fmt.Errorf("error getting last price: no ticker for %s", symbol)
+	}
+
+	return tickers[0].Price, nil
+}
+
+// NormalizeOrder rounds the price down to the tick size and the quantity down to the step size of the symbol,
+// then returns a *FilterError naming the first filter that would still reject the order.
+// The price of a market order is the price it is valued at, e.g. the last price: it is not rounded nor checked by the price filter,
+// and only the notional bounds applying to the market orders are checked, none when it is zero. Its quantity is rounded
+// and checked by the MARKET_LOT_SIZE filter too.
+func NormalizeOrder(s connector.Symbol, orderType connector.OrderType, price, quantity decimal.Decimal) (NormalizedOrder, error) {
+	f := s.Filters
+	market := orderType == connector.OrderTypeMarket
+	o := NormalizedOrder{
+		Symbol:   s.Name,
+		Price:    price,
+		Quantity: roundDown(quantity, f.MinQty, f.StepSize),
+	}
+
+	if market {
+		o.Quantity = roundDown(o.Quantity, f.MarketMinQty, f.MarketStepSize)
+	} else {
+		o.Price = roundDown(price, f.MinPrice, f.TickSize)
+
+		if err := checkRange(s.Name, FilterTypePrice, "price", o.Price, f.MinPrice, f.MaxPrice); err != nil {
+			return o, err
+		}
+	}
+
+	if !o.Quantity.IsPositive() {
+		return o, &FilterError{Symbol: s.Name, Filter: FilterTypeLotSize, Reason: fmt.Sprintf("quantity %s rounds down to %s", quantity, o.Quantity)}
+	}
+
+	if err := checkRange(s.Name, FilterTypeLotSize, "quantity", o.Quantity, f.MinQty, f.MaxQty); err != nil {
+		return o, err
+	}
+
+	if market {
+		if err := checkRange(s.Name, FilterTypeMarketLotSize, "quantity", o.Quantity, f.MarketMinQty, f.MarketMaxQty); err != nil {
+			return o, err
+		}
+
+		if !o.Price.IsPositive() {
+			return o, nil
+		}
+	}
+
+	for _, n := range notionalFilters(f) {
+		minimum, maximum := n.MinNotional, n.MaxNotional
+		if market && !n.ApplyMinToMarket {
+			minimum = decimal.Zero
+		}
+
+		if market && !n.ApplyMaxToMarket {
+			maximum = decimal.Zero
+		}
+
+		if err := checkRange(s.Name, n.Name, "notional", o.Notional(), minimum, maximum); err != nil {
+			return o, err
+		}
+	}
+
+	return o, nil
+}
+
+// notionalFilters returns the notional filters of the symbol, or a MIN_NOTIONAL filter of the notional bounds of a symbol built without them,
+// which does not apply to the market orders.
+func notionalFilters(f connector.SymbolFilters) []connector.NotionalFilter {
+	if len(f.NotionalFilters) > 0 {
+		return f.NotionalFilters
+	}
+
+	return []connector.NotionalFilter{{Name: FilterTypeMinNotional, MinNotional: f.MinNotional, MaxNotional: f.MaxNotional}}
+}
+
+// roundDown rounds v down to the closest min + n * step. It returns v unchanged when step is not positive.
+func roundDown(v, minimum, step decimal.Decimal) decimal.Decimal {
+	if !step.IsPositive() || v.LessThan(minimum) {
+		return v
+	}
+
+	steps := v.Sub(minimum).Div(step).Floor()
+
+	return minimum.Add(steps.Mul(step))
+}
+
+func checkRange(symbol, filter, name string, v, minimum, maximum decimal.Decimal) error {
+	if minimum.IsPositive() && v.LessThan(minimum) {
+		return &FilterError{Symbol: symbol, Filter: filter, Reason: fmt.Sprintf("%s %s is below the minimum of %s", name, v, minimum)}
+	}
+
+	if maximum.IsPositive() && v.GreaterThan(maximum) {
+		return &FilterError{Symbol: symbol, Filter: filter, Reason: fmt.Sprintf("%s %s is above the maximum of %s", name, v, maximum)}
+	}
+
+	return nil
+}
+
+// NormalizedService is a Service placing the orders with their price and quantity rounded to the filters of their symbol,
+// so that the prices computed by the strategies are not rejected by the PRICE_FILTER.
+type NormalizedService struct {
+	*Service
+	normalizer *Normalizer
+}
+
+// NewNormalizedService creates a new service normalizing the orders with the symbols of the catalog.
+// The market orders are valued at the last price of the service.
+func NewNormalizedService(s *Service, catalog symbolLookup) *NormalizedService {
+	return &NormalizedService{Service: s, normalizer: NewNormalizer(catalog, WithLastPrices(s))}
+}
+
+// Normalize returns the order request with its price and quantity rounded to the filters of its symbol, as it is placed.
+func (s *NormalizedService) Normalize(ctx context.Context, req connector.OrderRequest) (connector.OrderRequest, error) {
+	o, err := s.normalizer.Normalize(ctx, req.Symbol, req.Type, req.Price, req.Quantity)
+	if err != nil {
+		return connector.OrderRequest{}, fmt.Errorf("error normalizing order: %w", err)
+	}
+
+	req.Quantity = o.Quantity
+	if req.Type != connector.OrderTypeMarket {
+		req.Price = o.Price
+	}
+
+	return req, nil
+}
+
+// PlaceOrder normalizes the order, then places it. An order that a filter would still reject is not placed.
+func (s *NormalizedService) PlaceOrder(ctx context.Context, req connector.OrderRequest) (*connector.Order, error) {
+	req, err := s.Normalize(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Service.PlaceOrder(ctx, req)
+}
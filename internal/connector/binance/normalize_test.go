@@ -0,0 +1,309 @@
+package binance_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	binance_connector "github.com/binance/binance-connector-go"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+	mock_binance "github.com/twk/trader-b/internal/connector/binance/mocks"
+)
+
+func btcusdt() connector.Symbol {
+	return connector.Symbol{
+		Name:       "BTCUSDT",
+		BaseAsset:  "BTC",
+		QuoteAsset: "USDT",
+		Status:     "TRADING",
+		Filters: connector.SymbolFilters{
+			MinPrice:    decimal.RequireFromString("0.01000000"),
+			MaxPrice:    decimal.RequireFromString("1000000.00000000"),
+			TickSize:    decimal.RequireFromString("0.01000000"),
+			MinQty:      decimal.RequireFromString("0.00001000"),
+			MaxQty:      decimal.RequireFromString("9000.00000000"),
+			StepSize:    decimal.RequireFromString("0.00001000"),
+			MinNotional: decimal.RequireFromString("5.00000000"),
+		},
+	}
+}
+
+// btcusdtNotional is BTCUSDT with the MARKET_LOT_SIZE and NOTIONAL filters of the current exchange info.
+func btcusdtNotional() connector.Symbol {
+	s := btcusdt()
+	s.Filters.MarketMinQty = decimal.RequireFromString("0.00000000")
+	s.Filters.MarketMaxQty = decimal.RequireFromString("120.00000000")
+	s.Filters.MarketStepSize = decimal.RequireFromString("0.00010000")
+	s.Filters.MaxNotional = decimal.RequireFromString("9000000.00000000")
+	s.Filters.NotionalFilters = []connector.NotionalFilter{
+		{Name: binance.FilterTypeNotional, MinNotional: s.Filters.MinNotional, MaxNotional: s.Filters.MaxNotional, ApplyMinToMarket: true},
+	}
+
+	return s
+}
+
+// btcusdtBothNotional is BTCUSDT with a MIN_NOTIONAL filter decoded before a less strict NOTIONAL filter.
+func btcusdtBothNotional() connector.Symbol {
+	s := btcusdtNotional()
+	s.Filters.MinNotional = decimal.RequireFromString("10.00000000")
+	s.Filters.NotionalFilters = append([]connector.NotionalFilter{
+		{Name: binance.FilterTypeMinNotional, MinNotional: s.Filters.MinNotional, ApplyMinToMarket: true},
+	}, s.Filters.NotionalFilters...)
+
+	return s
+}
+
+type fakeTickers struct {
+	tickers []connector.Ticker
+	err     error
+}
+
+func (f *fakeTickers) GetTickers(_ context.Context, _ []string) ([]connector.Ticker, error) {
+	return f.tickers, f.err
+}
+
+func TestNormalizeOrder(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		symbol    connector.Symbol
+		orderType connector.OrderType
+		price     string
+		quantity  string
+	}
+
+	type want struct {
+		price    string
+		quantity string
+		filter   string
+		err      string
+	}
+
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"rounds down to tick and step": {
+			args: args{symbol: btcusdt(), price: "65000.123456", quantity: "0.123456789"},
+			want: want{price: "65000.12", quantity: "0.12345"},
+		},
+		"already valid": {
+			args: args{symbol: btcusdt(), price: "65000.1", quantity: "0.001"},
+			want: want{price: "65000.1", quantity: "0.001"},
+		},
+		"market order skips price filters": {
+			args: args{symbol: btcusdt(), orderType: connector.OrderTypeMarket, price: "0", quantity: "0.00001"},
+			want: want{price: "0", quantity: "0.00001"},
+		},
+		"market order below notional minimum applying to market": {
+			args: args{symbol: btcusdtNotional(), orderType: connector.OrderTypeMarket, price: "100", quantity: "0.01"},
+			want: want{
+				price: "100", quantity: "0.01", filter: binance.FilterTypeNotional,
+				err: "NOTIONAL would reject the order on BTCUSDT: notional 1 is below the minimum of 5",
+			},
+		},
+		"market order skips notional maximum not applying to market": {
+			args: args{symbol: btcusdtNotional(), orderType: connector.OrderTypeMarket, price: "100000", quantity: "100"},
+			want: want{price: "100000", quantity: "100"},
+		},
+		"market order without price skips notional filters": {
+			args: args{symbol: btcusdtNotional(), orderType: connector.OrderTypeMarket, price: "0", quantity: "0.01"},
+			want: want{price: "0", quantity: "0.01"},
+		},
+		"market order skips notional filters of a symbol built without them": {
+			args: args{symbol: btcusdt(), orderType: connector.OrderTypeMarket, price: "100", quantity: "0.01"},
+			want: want{price: "100", quantity: "0.01"},
+		},
+		"both notional filters checked": {
+			args: args{symbol: btcusdtBothNotional(), price: "700", quantity: "0.01"},
+			want: want{price: "700", quantity: "0.01", filter: binance.FilterTypeMinNotional, err: "MIN_NOTIONAL would reject the order on BTCUSDT: notional 7 is below the minimum of 10"},
+		},
+		"market order rounds down to market step": {
+			args: args{symbol: btcusdtNotional(), orderType: connector.OrderTypeMarket, price: "0", quantity: "0.12345"},
+			want: want{price: "0", quantity: "0.1234"},
+		},
+		"market quantity above maximum": {
+			args: args{symbol: btcusdtNotional(), orderType: connector.OrderTypeMarket, price: "0", quantity: "200"},
+			want: want{
+				price: "0", quantity: "200", filter: binance.FilterTypeMarketLotSize,
+				err: "MARKET_LOT_SIZE would reject the order on BTCUSDT: quantity 200 is above the maximum of 120",
+			},
+		},
+		"limit order ignores market lot size": {
+			args: args{symbol: btcusdtNotional(), price: "65000", quantity: "200"},
+			want: want{
+				price: "65000", quantity: "200", filter: binance.FilterTypeNotional,
+				err: "NOTIONAL would reject the order on BTCUSDT: notional 13000000 is above the maximum of 9000000",
+			},
+		},
+		"notional filter below minimum": {
+			args: args{symbol: btcusdtNotional(), price: "100", quantity: "0.01"},
+			want: want{price: "100", quantity: "0.01", filter: binance.FilterTypeNotional, err: "NOTIONAL would reject the order on BTCUSDT: notional 1 is below the minimum of 5"},
+		},
+		"no filters": {
+			args: args{symbol: connector.Symbol{Name: "XYZ"}, price: "1.23456789", quantity: "0.1"},
+			want: want{price: "1.23456789", quantity: "0.1"},
+		},
+		"price below minimum": {
+			args: args{symbol: btcusdt(), price: "0.001", quantity: "1"},
+			want: want{price: "0.001", quantity: "1", filter: binance.FilterTypePrice, err: "PRICE_FILTER would reject the order on BTCUSDT: price 0.001 is below the minimum of 0.01"},
+		},
+		"price above maximum": {
+			args: args{symbol: btcusdt(), price: "2000000", quantity: "1"},
+			want: want{price: "2000000", quantity: "1", filter: binance.FilterTypePrice, err: "PRICE_FILTER would reject the order on BTCUSDT: price 2000000 is above the maximum of 1000000"},
+		},
+		"quantity rounds to zero": {
+			args: args{symbol: connector.Symbol{Name: "XYZ", Filters: connector.SymbolFilters{StepSize: decimal.RequireFromString("1")}}, price: "1", quantity: "0.5"},
+			want: want{price: "1", quantity: "0", filter: binance.FilterTypeLotSize, err: "LOT_SIZE would reject the order on XYZ: quantity 0.5 rounds down to 0"},
+		},
+		"quantity below minimum": {
+			args: args{symbol: btcusdt(), price: "65000", quantity: "0.000001"},
+			want: want{price: "65000", quantity: "0.000001", filter: binance.FilterTypeLotSize, err: "LOT_SIZE would reject the order on BTCUSDT: quantity 0.000001 is below the minimum of 0.00001"},
+		},
+		"quantity above maximum": {
+			args: args{symbol: btcusdt(), price: "1", quantity: "10000"},
+			want: want{price: "1", quantity: "10000", filter: binance.FilterTypeLotSize, err: "LOT_SIZE would reject the order on BTCUSDT: quantity 10000 is above the maximum of 9000"},
+		},
+		"notional below minimum": {
+			args: args{symbol: btcusdt(), price: "100", quantity: "0.01"},
+			want: want{price: "100", quantity: "0.01", filter: binance.FilterTypeMinNotional, err: "MIN_NOTIONAL would reject the order on BTCUSDT: notional 1 is below the minimum of 5"},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			orderType := tt.args.orderType
+			if orderType == "" {
+				orderType = connector.OrderTypeLimit
+			}
+
+			o, err := binance.NormalizeOrder(tt.args.symbol, orderType, decimal.RequireFromString(tt.args.price), decimal.RequireFromString(tt.args.quantity))
+
+			assert.Equal(t, tt.want.price, o.Price.String())
+			assert.Equal(t, tt.want.quantity, o.Quantity.String())
+
+			if tt.want.err != "" {
+				var filterErr *binance.FilterError
+
+				assert.True(t, errors.As(err, &filterErr))
+				assert.Equal(t, tt.want.filter, filterErr.Filter)
+				assert.EqualError(t, err, tt.want.err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNormalizer_Normalize(t *testing.T) {
+	t.Parallel()
+
+	n := binance.NewNormalizer(binance.NewCatalog(&fakeSymbolsGetter{symbols: []connector.Symbol{btcusdt()}}, time.Hour))
+
+	o, err := n.Normalize(context.Background(), "BTCUSDT", connector.OrderTypeLimit, decimal.RequireFromString("65000.129"), decimal.RequireFromString("0.0012345"))
+	assert.NoError(t, err)
+	assert.Equal(t, "65000.12", o.Price.String())
+	assert.Equal(t, "0.00123", o.Quantity.String())
+	assert.Equal(t, "79.9501476", o.Notional().String())
+
+	_, err = n.Normalize(context.Background(), "DOGEUSDT", connector.OrderTypeLimit, decimal.RequireFromString("1"), decimal.RequireFromString("1"))
+	assert.ErrorIs(t, err, binance.ErrSymbolNotFound)
+}
+
+func TestNormalizer_Normalize_Market(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		price string
+		err   string
+	}
+
+	tests := map[string]struct {
+		tickers *fakeTickers
+		want    want
+	}{
+		"valued at the last price": {
+			tickers: &fakeTickers{tickers: []connector.Ticker{{Symbol: "BTCUSDT", Price: decimal.RequireFromString("65000")}}},
+			want:    want{price: "65000"},
+		},
+		"below notional minimum at the last price": {
+			tickers: &fakeTickers{tickers: []connector.Ticker{{Symbol: "BTCUSDT", Price: decimal.RequireFromString("100")}}},
+			want:    want{err: "NOTIONAL would reject the order on BTCUSDT: notional 1 is below the minimum of 5"},
+		},
+		"ticker error": {
+			tickers: &fakeTickers{err: errors.New("ticker error")},
+			want:    want{err: "error getting last price: ticker error"},
+		},
+		"no ticker": {
+			tickers: &fakeTickers{},
+			want:    want{err: "error getting last price: no ticker for BTCUSDT"},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			catalog := binance.NewCatalog(&fakeSymbolsGetter{symbols: []connector.Symbol{btcusdtNotional()}}, time.Hour)
+			n := binance.NewNormalizer(catalog, binance.WithLastPrices(tt.tickers))
+
+			o, err := n.Normalize(context.Background(), "BTCUSDT", connector.OrderTypeMarket, decimal.Zero, decimal.RequireFromString("0.01"))
+			if tt.want.err != "" {
+				assert.EqualError(t, err, tt.want.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.price, o.Price.String())
+		})
+	}
+}
+
+func TestNormalizedService_PlaceOrder(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_binance.NewMockClient(ctrl)
+	mockOrderClient := mock_binance.NewMockCreateOrderClient(ctrl)
+	catalog := binance.NewCatalog(&fakeSymbolsGetter{symbols: []connector.Symbol{btcusdt()}}, time.Hour)
+	s := binance.NewNormalizedService(binance.NewService(mockClient), catalog)
+
+	mockClient.EXPECT().NewCreateOrderService(gomock.Any()).DoAndReturn(func(req connector.OrderRequest) binance.CreateOrderClient {
+		assert.Equal(t, "65000.12", req.Price.String())
+		assert.Equal(t, "0.00123", req.Quantity.String())
+
+		return mockOrderClient
+	})
+	mockOrderClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.CreateOrderResponseACK{Symbol: "BTCUSDT", OrderId: 1}, nil)
+
+	req := limitBuy()
+	req.Price, req.Quantity = decimal.RequireFromString("65000.1234567890123456"), decimal.RequireFromString("0.0012345")
+
+	o, err := s.PlaceOrder(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), o.ID)
+
+	req.Quantity = decimal.RequireFromString("0.000001")
+
+	_, err = s.PlaceOrder(context.Background(), req)
+
+	var filterErr *binance.FilterError
+
+	assert.True(t, errors.As(err, &filterErr), "an order rejected by a filter is not placed")
+}
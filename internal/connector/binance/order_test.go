@@ -0,0 +1,418 @@
+package binance_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	binance_connector "github.com/binance/binance-connector-go"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+	mock_binance "github.com/twk/trader-b/internal/connector/binance/mocks"
+)
+
+func limitBuy() connector.OrderRequest {
+	return connector.OrderRequest{
+		Symbol:   "BTCUSDT",
+		Side:     connector.SideBuy,
+		Type:     connector.OrderTypeLimit,
+		Quantity: decimal.RequireFromString("0.001"),
+		Price:    decimal.RequireFromString("65000"),
+	}
+}
+
+func TestService_PlaceOrder(t *testing.T) {
+	type fields struct {
+		mockOperation func(client *mock_binance.MockClient, orderClient *mock_binance.MockCreateOrderClient)
+	}
+
+	type want struct {
+		res *connector.Order
+		err error
+	}
+
+	withTimeInForce := limitBuy()
+	withTimeInForce.TimeInForce = "GTC"
+
+	tests := map[string]struct {
+		fields fields
+		want   want
+	}{
+		"Full response": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, orderClient *mock_binance.MockCreateOrderClient) {
+					client.EXPECT().NewCreateOrderService(withTimeInForce).Return(orderClient)
+					orderClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.CreateOrderResponseFULL{
+						Symbol: "BTCUSDT", OrderId: 1, ClientOrderId: "abc", TransactTime: 1700000000000, Price: "65000.00",
+						OrigQty: "0.001", ExecutedQty: "0", Status: "NEW", Type: "LIMIT", Side: "BUY",
+					}, nil)
+				},
+			},
+			want: want{
+				res: &connector.Order{
+					ID: 1, ClientOrderID: "abc", Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Status: "NEW",
+					Price: decimal.RequireFromString("65000.00"), Quantity: decimal.RequireFromString("0.001"), ExecutedQuantity: decimal.RequireFromString("0"),
+					Time: time.UnixMilli(1700000000000),
+				},
+			},
+		},
+		"Result response": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, orderClient *mock_binance.MockCreateOrderClient) {
+					client.EXPECT().NewCreateOrderService(withTimeInForce).Return(orderClient)
+					orderClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.CreateOrderResponseRESULT{
+						Symbol: "BTCUSDT", OrderId: 2, Status: "NEW", Type: "LIMIT", Side: "BUY", Price: "65000", OrigQty: "0.001",
+					}, nil)
+				},
+			},
+			want: want{
+				res: &connector.Order{
+					ID: 2, Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Status: "NEW",
+					Price: decimal.RequireFromString("65000"), Quantity: decimal.RequireFromString("0.001"),
+				},
+			},
+		},
+		"Ack response": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, orderClient *mock_binance.MockCreateOrderClient) {
+					client.EXPECT().NewCreateOrderService(withTimeInForce).Return(orderClient)
+					orderClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.CreateOrderResponseACK{Symbol: "BTCUSDT", OrderId: 3}, nil)
+				},
+			},
+			want: want{
+				res: &connector.Order{ID: 3, Symbol: "BTCUSDT"},
+			},
+		},
+		"Unexpected response": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, orderClient *mock_binance.MockCreateOrderClient) {
+					client.EXPECT().NewCreateOrderService(withTimeInForce).Return(orderClient)
+					orderClient.EXPECT().Do(gomock.Any()).Return("unexpected", nil)
+				},
+			},
+			want: want{
+				err: errors.New("unexpected order response type string"),
+			},
+		},
+		"Invalid price": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, orderClient *mock_binance.MockCreateOrderClient) {
+					client.EXPECT().NewCreateOrderService(withTimeInForce).Return(orderClient)
+					orderClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.CreateOrderResponseFULL{OrderId: 4, Price: "abc"}, nil)
+				},
+			},
+			want: want{
+				err: errors.New("error parsing order 4: error parsing decimal: can't convert abc to decimal"),
+			},
+		},
+		"DoError": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, orderClient *mock_binance.MockCreateOrderClient) {
+					client.EXPECT().NewCreateOrderService(withTimeInForce).Return(orderClient)
+					orderClient.EXPECT().Do(gomock.Any()).Return(nil, errors.New("do error"))
+				},
+			},
+			want: want{
+				err: errors.New("error placing order on BTCUSDT: do error"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock_binance.NewMockClient(ctrl)
+			mockOrderClient := mock_binance.NewMockCreateOrderClient(ctrl)
+			tt.fields.mockOperation(mockClient, mockOrderClient)
+			service := binance.NewService(mockClient)
+
+			res, err := service.PlaceOrder(context.Background(), limitBuy())
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.Equal(t, tt.want.res, res)
+		})
+	}
+}
+
+func TestService_CancelOrder(t *testing.T) {
+	type fields struct {
+		mockOperation func(client *mock_binance.MockClient, cancelClient *mock_binance.MockCancelOrderClient)
+	}
+
+	type want struct {
+		res *connector.Order
+		err error
+	}
+
+	tests := map[string]struct {
+		fields fields
+		want   want
+	}{
+		"Success": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, cancelClient *mock_binance.MockCancelOrderClient) {
+					client.EXPECT().NewCancelOrderService("BTCUSDT", int64(1)).Return(cancelClient)
+					cancelClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.CancelOrderResponse{
+						Symbol: "BTCUSDT", OrderId: 1, Status: "CANCELED", Side: "SELL", Type: "LIMIT", Price: "70000", OrigQty: "1", ExecutedQty: "0.5",
+					}, nil)
+				},
+			},
+			want: want{
+				res: &connector.Order{
+					ID: 1, Symbol: "BTCUSDT", Side: connector.SideSell, Type: connector.OrderTypeLimit, Status: "CANCELED",
+					Price: decimal.RequireFromString("70000"), Quantity: decimal.RequireFromString("1"), ExecutedQuantity: decimal.RequireFromString("0.5"),
+				},
+			},
+		},
+		"DoError": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, cancelClient *mock_binance.MockCancelOrderClient) {
+					client.EXPECT().NewCancelOrderService("BTCUSDT", int64(1)).Return(cancelClient)
+					cancelClient.EXPECT().Do(gomock.Any()).Return(nil, errors.New("do error"))
+				},
+			},
+			want: want{
+				err: errors.New("error cancelling order 1 on BTCUSDT: do error"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock_binance.NewMockClient(ctrl)
+			mockCancelClient := mock_binance.NewMockCancelOrderClient(ctrl)
+			tt.fields.mockOperation(mockClient, mockCancelClient)
+			service := binance.NewService(mockClient)
+
+			res, err := service.CancelOrder(context.Background(), "BTCUSDT", 1)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.Equal(t, tt.want.res, res)
+		})
+	}
+}
+
+func TestService_GetOrder(t *testing.T) {
+	type fields struct {
+		mockOperation func(client *mock_binance.MockClient, getClient *mock_binance.MockGetOrderClient)
+	}
+
+	type want struct {
+		res *connector.Order
+		err error
+	}
+
+	tests := map[string]struct {
+		fields fields
+		want   want
+	}{
+		"Success": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, getClient *mock_binance.MockGetOrderClient) {
+					client.EXPECT().NewGetOrderService("BTCUSDT", int64(1)).Return(getClient)
+					getClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.GetOrderResponse{
+						Symbol: "BTCUSDT", OrderId: 1, Status: "FILLED", Side: "BUY", Type: "MARKET", Price: "0", OrigQty: "1", ExecutedQty: "1", Time: 1700000000000,
+					}, nil)
+				},
+			},
+			want: want{
+				res: &connector.Order{
+					ID: 1, Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeMarket, Status: "FILLED",
+					Price: decimal.RequireFromString("0"), Quantity: decimal.RequireFromString("1"), ExecutedQuantity: decimal.RequireFromString("1"),
+					Time: time.UnixMilli(1700000000000),
+				},
+			},
+		},
+		"DoError": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, getClient *mock_binance.MockGetOrderClient) {
+					client.EXPECT().NewGetOrderService("BTCUSDT", int64(1)).Return(getClient)
+					getClient.EXPECT().Do(gomock.Any()).Return(nil, errors.New("do error"))
+				},
+			},
+			want: want{
+				err: errors.New("error getting order 1 on BTCUSDT: do error"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock_binance.NewMockClient(ctrl)
+			mockGetClient := mock_binance.NewMockGetOrderClient(ctrl)
+			tt.fields.mockOperation(mockClient, mockGetClient)
+			service := binance.NewService(mockClient)
+
+			res, err := service.GetOrder(context.Background(), "BTCUSDT", 1)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.Equal(t, tt.want.res, res)
+		})
+	}
+}
+
+func TestService_ListOpenOrders(t *testing.T) {
+	type fields struct {
+		mockOperation func(client *mock_binance.MockClient, openClient *mock_binance.MockOpenOrdersClient)
+	}
+
+	type want struct {
+		res []connector.Order
+		err error
+	}
+
+	tests := map[string]struct {
+		fields fields
+		want   want
+	}{
+		"Success": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, openClient *mock_binance.MockOpenOrdersClient) {
+					client.EXPECT().NewOpenOrdersService("").Return(openClient)
+					openClient.EXPECT().Do(gomock.Any()).Return([]*binance_connector.NewOpenOrdersResponse{
+						{Symbol: "BTCUSDT", OrderId: 1, Status: "NEW", Side: "BUY", Type: "LIMIT", Price: "60000", OrigQty: "1", ExecutedQty: "0"},
+					}, nil)
+				},
+			},
+			want: want{
+				res: []connector.Order{
+					{
+						ID: 1, Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Status: "NEW",
+						Price: decimal.RequireFromString("60000"), Quantity: decimal.RequireFromString("1"), ExecutedQuantity: decimal.RequireFromString("0"),
+					},
+				},
+			},
+		},
+		"Invalid quantity": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, openClient *mock_binance.MockOpenOrdersClient) {
+					client.EXPECT().NewOpenOrdersService("").Return(openClient)
+					openClient.EXPECT().Do(gomock.Any()).Return([]*binance_connector.NewOpenOrdersResponse{{OrderId: 1, OrigQty: "abc"}}, nil)
+				},
+			},
+			want: want{
+				err: errors.New("error parsing order 1: error parsing decimal: can't convert abc to decimal"),
+			},
+		},
+		"DoError": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, openClient *mock_binance.MockOpenOrdersClient) {
+					client.EXPECT().NewOpenOrdersService("").Return(openClient)
+					openClient.EXPECT().Do(gomock.Any()).Return(nil, errors.New("do error"))
+				},
+			},
+			want: want{
+				err: errors.New("error listing open orders: do error"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock_binance.NewMockClient(ctrl)
+			mockOpenClient := mock_binance.NewMockOpenOrdersClient(ctrl)
+			tt.fields.mockOperation(mockClient, mockOpenClient)
+			service := binance.NewService(mockClient)
+
+			res, err := service.ListOpenOrders(context.Background(), "")
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.Equal(t, tt.want.res, res)
+		})
+	}
+}
+
+func TestService_PlaceOrder_DryRun(t *testing.T) {
+	type fields struct {
+		mockOperation func(client *mock_binance.MockClient, testOrderClient *mock_binance.MockTestOrderClient)
+	}
+
+	type want struct {
+		res *connector.Order
+		err error
+	}
+
+	withTimeInForce := limitBuy()
+	withTimeInForce.TimeInForce = "GTC"
+
+	tests := map[string]struct {
+		fields fields
+		want   want
+	}{
+		"Success": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, testOrderClient *mock_binance.MockTestOrderClient) {
+					client.EXPECT().NewTestOrderService(withTimeInForce).Return(testOrderClient)
+					testOrderClient.EXPECT().Do(gomock.Any()).Return(&binance_connector.AccountOrderBookResponse{}, nil)
+				},
+			},
+			want: want{
+				res: &connector.Order{
+					Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Status: binance.OrderStatusDryRun,
+					Price: decimal.RequireFromString("65000"), Quantity: decimal.RequireFromString("0.001"),
+				},
+			},
+		},
+		"DoError": {
+			fields: fields{
+				mockOperation: func(client *mock_binance.MockClient, testOrderClient *mock_binance.MockTestOrderClient) {
+					client.EXPECT().NewTestOrderService(withTimeInForce).Return(testOrderClient)
+					testOrderClient.EXPECT().Do(gomock.Any()).Return(nil, errors.New("do error"))
+				},
+			},
+			want: want{
+				err: errors.New("error placing test order on BTCUSDT: do error"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock_binance.NewMockClient(ctrl)
+			mockTestOrderClient := mock_binance.NewMockTestOrderClient(ctrl)
+			tt.fields.mockOperation(mockClient, mockTestOrderClient)
+			service := binance.NewService(mockClient, binance.WithDryRun(true))
+
+			assert.True(t, service.DryRun())
+
+			res, err := service.PlaceOrder(context.Background(), limitBuy())
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.Equal(t, tt.want.res, res)
+		})
+	}
+}
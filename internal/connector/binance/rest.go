@@ -0,0 +1,40 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/binance/binance-connector-go/handlers"
+)
+
+// apiKeyHeader is the header of the API key of the signed requests.
+const apiKeyHeader = "X-MBX-APIKEY"
+
+// readResponse closes the response and reads its body, returning the error of a response with a non-OK status.
+// It is used by the endpoints the connector does not support well enough, which are called with the client of the application.
+func readResponse(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, apiError(resp.StatusCode, body)
+	}
+
+	return body, nil
+}
+
+// apiError returns the error of a response with a non-OK status, the Binance API error it holds when it can be decoded.
+func apiError(status int, body []byte) error {
+	apiErr := &handlers.APIError{}
+	if err := json.Unmarshal(body, apiErr); err != nil {
+		return fmt.Errorf("received non-OK HTTP status: %d", status)
+	}
+
+	return apiErr
+}
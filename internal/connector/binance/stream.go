@@ -0,0 +1,451 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// Default settings of the streamer. Binance closes every connection after 24 hours and pings every few minutes.
+const (
+	defaultMinBackoff    = time.Second
+	defaultMaxBackoff    = time.Minute
+	defaultMaxConnAge    = 23*time.Hour + 50*time.Minute
+	defaultReadTimeout   = 10 * time.Minute
+	combinedStreamPath   = "/stream"
+	depthUpdateFrequency = "100ms"
+)
+
+// StreamType is the type of a Binance market stream.
+type StreamType string
+
+// The supported market stream types.
+const (
+	StreamTrade      StreamType = "trade"
+	StreamBookTicker StreamType = "bookTicker"
+	StreamKline      StreamType = "kline"
+	StreamDepth      StreamType = "depth"
+)
+
+// ErrUnknownStream is returned for stream types and stream names that are not supported.
+var ErrUnknownStream = errors.New("unknown stream")
+
+// Stream selects a market stream of a symbol. Interval is only used by kline streams.
+type Stream struct {
+	Symbol   string
+	Type     StreamType
+	Interval string
+}
+
+// Name returns the name of the stream, e.g. btcusdt@kline_1m.
+func (s Stream) Name() string {
+	symbol := strings.ToLower(s.Symbol)
+
+	switch s.Type {
+	case StreamKline:
+		return symbol + "@kline_" + s.Interval
+	case StreamDepth:
+		return symbol + "@depth@" + depthUpdateFrequency
+	default:
+		return symbol + "@" + string(s.Type)
+	}
+}
+
+// ParseStreamType parses a stream type name, case insensitive.
+func ParseStreamType(s string) (StreamType, error) {
+	for _, t := range []StreamType{StreamTrade, StreamBookTicker, StreamKline, StreamDepth} {
+		if strings.EqualFold(s, string(t)) {
+			return t, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnknownStream, s)
+}
+
+// BookTicker is the best bid and ask of a symbol.
+type BookTicker struct {
+	Symbol   string
+	UpdateID uint64
+	BidPrice decimal.Decimal
+	BidQty   decimal.Decimal
+	AskPrice decimal.Decimal
+	AskQty   decimal.Decimal
+}
+
+// KlineUpdate is an update of the current kline of a symbol. Closed is true for the last update of the kline.
+type KlineUpdate struct {
+	Candle connector.Candle
+	Closed bool
+}
+
+// DepthUpdate is a diff of the order book of a symbol covering the update IDs [FirstUpdateID, FinalUpdateID].
+// A level with a zero quantity has to be removed from the book.
+type DepthUpdate struct {
+	Symbol        string
+	Time          time.Time
+	FirstUpdateID uint64
+	FinalUpdateID uint64
+	Bids          []connector.PriceLevel
+	Asks          []connector.PriceLevel
+}
+
+// StreamEvent is an event received from a market stream. Exactly one of the pointers is set, depending on the type of the stream.
+type StreamEvent struct {
+	Stream     string
+	Trade      *connector.Trade
+	BookTicker *BookTicker
+	Kline      *KlineUpdate
+	Depth      *DepthUpdate
+}
+
+// Streamer subscribes to the Binance market streams over a single combined stream connection.
+// It reconnects with an exponential backoff when the connection drops, and before the forced disconnect after 24 hours.
+type Streamer struct {
+	baseURL     string
+	dialer      *websocket.Dialer
+	log         *zap.Logger
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	maxConnAge  time.Duration
+	readTimeout time.Duration
+	onConnect   func()
+}
+
+// StreamerOption configures the streamer.
+type StreamerOption func(*Streamer)
+
+// WithBackoff sets the first and the maximum delay between reconnection attempts.
+func WithBackoff(minBackoff, maxBackoff time.Duration) StreamerOption {
+	return func(s *Streamer) {
+		s.minBackoff, s.maxBackoff = minBackoff, maxBackoff
+	}
+}
+
+// WithMaxConnectionAge sets how long a connection is used before it is replaced, ahead of the forced disconnect of Binance.
+func WithMaxConnectionAge(d time.Duration) StreamerOption {
+	return func(s *Streamer) {
+		s.maxConnAge = d
+	}
+}
+
+// WithReadTimeout sets how long the streamer waits for a message or a ping before it considers the connection dead.
+func WithReadTimeout(d time.Duration) StreamerOption {
+	return func(s *Streamer) {
+		s.readTimeout = d
+	}
+}
+
+// WithOnConnect sets a function called every time a connection is established, e.g. to take a snapshot once the events it would
+// miss are streamed.
+func WithOnConnect(fn func()) StreamerOption {
+	return func(s *Streamer) {
+		s.onConnect = fn
+	}
+}
+
+// WithStreamLogger sets the logger of the reconnections.
+func WithStreamLogger(l *zap.Logger) StreamerOption {
+	return func(s *Streamer) {
+		s.log = l
+	}
+}
+
+// NewStreamer creates a new streamer for the websocket base URL, e.g. wss://stream.binance.com:9443.
+func NewStreamer(baseURL string, opts ...StreamerOption) *Streamer {
+	s := &Streamer{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		dialer:      websocket.DefaultDialer,
+		log:         zap.NewNop(),
+		minBackoff:  defaultMinBackoff,
+		maxBackoff:  defaultMaxBackoff,
+		maxConnAge:  defaultMaxConnAge,
+		readTimeout: defaultReadTimeout,
+		onConnect:   func() {},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Run subscribes to the streams and calls fn with every event until the context is done.
+// Connection errors are retried forever, so Run only returns an error wrapping the context error, or an error of fn.
+func (s *Streamer) Run(ctx context.Context, streams []Stream, fn func(StreamEvent) error) error {
+	if len(streams) == 0 {
+		return fmt.Errorf("%w: no stream selected", ErrUnknownStream)
+	}
+
+	names := make([]string, 0, len(streams))
+	for _, st := range streams {
+		names = append(names, st.Name())
+	}
+
+	return s.run(ctx, s.baseURL+combinedStreamPath+"?streams="+strings.Join(names, "/"), names, func(msg []byte) error {
+		event, err := parseStreamEvent(msg)
+		if err != nil {
+			s.log.Warn("skipping invalid stream event", zap.Error(err), zap.ByteString("message", msg))
+			return nil
+		}
+
+		return fn(event)
+	})
+}
+
+// run reads the messages of the stream URL and calls handle with each of them, reconnecting until the context is done or handle fails.
+// The names identify the stream in the logs.
+func (s *Streamer) run(ctx context.Context, u string, names []string, handle func([]byte) error) error {
+	backoff := s.minBackoff
+
+	for {
+		received, err := s.serve(ctx, u, handle)
+		if ctx.Err() != nil {
+			return fmt.Errorf("stream stopped: %w", ctx.Err())
+		}
+
+		var handlerErr *handlerError
+		if errors.As(err, &handlerErr) {
+			return handlerErr.err
+		}
+
+		if received {
+			backoff = s.minBackoff
+		}
+
+		if err == nil {
+			s.log.Info("replacing websocket connection before the forced disconnect", zap.Strings("streams", names))
+			continue
+		}
+
+		s.log.Warn("websocket connection lost, reconnecting", zap.Error(err), zap.Duration("backoff", backoff))
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stream stopped: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, s.maxBackoff)
+	}
+}
+
+// handlerError wraps the errors of the event handler, which stop the streamer instead of triggering a reconnection.
+type handlerError struct {
+	err error
+}
+
+func (e *handlerError) Error() string {
+	return e.err.Error()
+}
+
+// serve reads the messages of one connection. It returns a nil error when the connection reached its maximum age,
+// and reports whether at least one message was received.
+func (s *Streamer) serve(ctx context.Context, u string, handle func([]byte) error) (bool, error) {
+	conn, _, err := s.dialer.DialContext(ctx, u, nil)
+	if err != nil {
+		return false, fmt.Errorf("error connecting to %s: %w", u, err)
+	}
+	defer conn.Close()
+
+	s.onConnect()
+
+	// Closing the connection unblocks the read when the context is done or the connection is too old.
+	expired := make(chan struct{})
+	timer := time.AfterFunc(s.maxConnAge, func() {
+		close(expired)
+		_ = conn.Close()
+	})
+
+	defer timer.Stop()
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	conn.SetPingHandler(func(data string) error {
+		_ = conn.SetReadDeadline(time.Now().Add(s.readTimeout))
+		if err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second)); err != nil {
+			return fmt.Errorf("error sending pong: %w", err)
+		}
+
+		return nil
+	})
+
+	received := false
+
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(s.readTimeout))
+
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-expired:
+				return received, nil
+			default:
+				return received, fmt.Errorf("error reading stream: %w", err)
+			}
+		}
+
+		received = true
+
+		if err := handle(msg); err != nil {
+			return received, &handlerError{err: err}
+		}
+	}
+}
+
+// combinedEvent is the envelope of the events of a combined stream.
+type combinedEvent struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// tradeEvent declares the ignored field M so that it is not decoded into IsBuyerMaker, as JSON keys are matched case insensitively.
+type tradeEvent struct {
+	Symbol       string          `json:"s"`
+	ID           uint64          `json:"t"`
+	Price        decimal.Decimal `json:"p"`
+	Quantity     decimal.Decimal `json:"q"`
+	Time         int64           `json:"T"`
+	IsBuyerMaker bool            `json:"m"`
+	Ignore       bool            `json:"M"`
+}
+
+type bookTickerEvent struct {
+	UpdateID uint64          `json:"u"`
+	Symbol   string          `json:"s"`
+	BidPrice decimal.Decimal `json:"b"`
+	BidQty   decimal.Decimal `json:"B"`
+	AskPrice decimal.Decimal `json:"a"`
+	AskQty   decimal.Decimal `json:"A"`
+}
+
+// klineEvent declares the trade IDs and the taker buy volumes of the kline so that they are not decoded into Low, Volume and QuoteVolume,
+// as JSON keys are matched case insensitively.
+type klineEvent struct {
+	Symbol string `json:"s"`
+	Kline  struct {
+		OpenTime            int64           `json:"t"`
+		CloseTime           int64           `json:"T"`
+		Interval            string          `json:"i"`
+		FirstTradeID        int64           `json:"f"`
+		LastTradeID         int64           `json:"L"`
+		Open                decimal.Decimal `json:"o"`
+		Close               decimal.Decimal `json:"c"`
+		High                decimal.Decimal `json:"h"`
+		Low                 decimal.Decimal `json:"l"`
+		Volume              decimal.Decimal `json:"v"`
+		QuoteVolume         decimal.Decimal `json:"q"`
+		TakerBuyVolume      decimal.Decimal `json:"V"`
+		TakerBuyQuoteVolume decimal.Decimal `json:"Q"`
+		Trades              uint64          `json:"n"`
+		Closed              bool            `json:"x"`
+		Ignore              string          `json:"B"`
+	} `json:"k"`
+}
+
+// depthEvent declares the event type so that it is not decoded into Time, as JSON keys are matched case insensitively.
+type depthEvent struct {
+	EventType     string              `json:"e"`
+	Time          int64               `json:"E"`
+	Symbol        string              `json:"s"`
+	FirstUpdateID uint64              `json:"U"`
+	FinalUpdateID uint64              `json:"u"`
+	Bids          [][]decimal.Decimal `json:"b"`
+	Asks          [][]decimal.Decimal `json:"a"`
+}
+
+func parseStreamEvent(msg []byte) (StreamEvent, error) {
+	var env combinedEvent
+	if err := json.Unmarshal(msg, &env); err != nil {
+		return StreamEvent{}, fmt.Errorf("error decoding stream event: %w", err)
+	}
+
+	event := StreamEvent{Stream: env.Stream}
+
+	_, kind, _ := strings.Cut(env.Stream, "@")
+
+	var err error
+
+	switch {
+	case kind == string(StreamTrade):
+		var e tradeEvent
+		if err = json.Unmarshal(env.Data, &e); err == nil {
+			event.Trade = &connector.Trade{
+				ID: e.ID, Symbol: e.Symbol, Price: e.Price, Quantity: e.Quantity, Time: time.UnixMilli(e.Time), IsBuyerMaker: e.IsBuyerMaker,
+			}
+		}
+	case kind == string(StreamBookTicker):
+		var e bookTickerEvent
+		if err = json.Unmarshal(env.Data, &e); err == nil {
+			event.BookTicker = &BookTicker{
+				Symbol: e.Symbol, UpdateID: e.UpdateID, BidPrice: e.BidPrice, BidQty: e.BidQty, AskPrice: e.AskPrice, AskQty: e.AskQty,
+			}
+		}
+	case strings.HasPrefix(kind, string(StreamKline)+"_"):
+		var e klineEvent
+		if err = json.Unmarshal(env.Data, &e); err == nil {
+			k := e.Kline
+			event.Kline = &KlineUpdate{Closed: k.Closed, Candle: connector.Candle{
+				Symbol: e.Symbol, Interval: k.Interval, OpenTime: time.UnixMilli(k.OpenTime).UTC(), CloseTime: time.UnixMilli(k.CloseTime).UTC(),
+				Open: k.Open, High: k.High, Low: k.Low, Close: k.Close, Volume: k.Volume, QuoteVolume: k.QuoteVolume, Trades: k.Trades,
+			}}
+		}
+	case strings.HasPrefix(kind, string(StreamDepth)):
+		event.Depth, err = toDepthUpdate(env.Data)
+	default:
+		return StreamEvent{}, fmt.Errorf("%w: %q", ErrUnknownStream, env.Stream)
+	}
+
+	if err != nil {
+		return StreamEvent{}, fmt.Errorf("error decoding %s event: %w", env.Stream, err)
+	}
+
+	return event, nil
+}
+
+func toDepthUpdate(data []byte) (*DepthUpdate, error) {
+	var e depthEvent
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("error decoding depth update: %w", err)
+	}
+
+	bids, err := toStreamPriceLevels(e.Bids)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing bids: %w", err)
+	}
+
+	asks, err := toStreamPriceLevels(e.Asks)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing asks: %w", err)
+	}
+
+	return &DepthUpdate{
+		Symbol: e.Symbol, Time: time.UnixMilli(e.Time), FirstUpdateID: e.FirstUpdateID, FinalUpdateID: e.FinalUpdateID, Bids: bids, Asks: asks,
+	}, nil
+}
+
+func toStreamPriceLevels(levels [][]decimal.Decimal) ([]connector.PriceLevel, error) {
+	res := make([]connector.PriceLevel, 0, len(levels))
+
+	for _, l := range levels {
+		if len(l) < priceLevelSize {
+			return nil, fmt.Errorf("invalid price level: %v", l)
+		}
+
+		res = append(res, connector.PriceLevel{Price: l[0], Quantity: l[1]})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,329 @@
+package binance_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+)
+
+const (
+	// The messages are the full payloads documented by Binance, whose keys differing only by case must not be mixed up.
+	tradeMessage = `{"stream":"btcusdt@trade","data":{"e":"trade","E":1700000000001,"s":"BTCUSDT","t":7,"p":"65000.01","q":"0.002",` +
+		`"T":1700000000000,"m":false,"M":true}}`
+	bookTickerMessage = `{"stream":"btcusdt@bookTicker","data":{"u":9,"s":"BTCUSDT","b":"65000","B":"1.5","a":"65000.1","A":"0.25"}}`
+	klineMessage      = `{"stream":"btcusdt@kline_1m","data":{"e":"kline","E":1700000100001,"s":"BTCUSDT","k":{"t":1700000040000,"T":1700000099999,` +
+		`"s":"BTCUSDT","i":"1m","f":100,"L":200,"o":"100","c":"100.5","h":"101","l":"99","v":"2","n":4,"x":true,"q":"201","V":"1","Q":"100.5",` +
+		`"B":"123456"}}}`
+	depthMessage = `{"stream":"btcusdt@depth@100ms","data":{"e":"depthUpdate","E":1700000000000,"s":"BTCUSDT","U":157,"u":160,` +
+		`"b":[["65000","1"],["64999","0"]],"a":[["65001","2"]]}}`
+)
+
+// newStreamServer starts a websocket server calling serve with every accepted connection and its number, starting at 1.
+func newStreamServer(t *testing.T, serve func(n int, conn *websocket.Conn)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var (
+		upgrader    websocket.Upgrader
+		connections atomic.Int32
+		wg          sync.WaitGroup
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stream" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		wg.Add(1)
+		defer wg.Done()
+		defer conn.Close()
+
+		serve(int(connections.Add(1)), conn)
+	}))
+
+	t.Cleanup(func() {
+		server.CloseClientConnections()
+		server.Close()
+		wg.Wait()
+	})
+
+	return server, &connections
+}
+
+func wsURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// holdOpen keeps the connection open until the client closes it.
+func holdOpen(conn *websocket.Conn) {
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func TestStream_Name(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		stream binance.Stream
+		want   string
+	}{
+		"trade":       {stream: binance.Stream{Symbol: "BTCUSDT", Type: binance.StreamTrade}, want: "btcusdt@trade"},
+		"book ticker": {stream: binance.Stream{Symbol: "BTCUSDT", Type: binance.StreamBookTicker}, want: "btcusdt@bookTicker"},
+		"kline":       {stream: binance.Stream{Symbol: "BTCUSDT", Type: binance.StreamKline, Interval: "1m"}, want: "btcusdt@kline_1m"},
+		"depth":       {stream: binance.Stream{Symbol: "BTCUSDT", Type: binance.StreamDepth}, want: "btcusdt@depth@100ms"},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.stream.Name())
+		})
+	}
+}
+
+func TestParseStreamType(t *testing.T) {
+	t.Parallel()
+
+	st, err := binance.ParseStreamType("BookTicker")
+	assert.NoError(t, err)
+	assert.Equal(t, binance.StreamBookTicker, st)
+
+	_, err = binance.ParseStreamType("aggTrade")
+	assert.EqualError(t, err, `unknown stream: "aggTrade"`)
+	assert.ErrorIs(t, err, binance.ErrUnknownStream)
+}
+
+func TestStreamer_Run(t *testing.T) {
+	var query string
+
+	server, _ := newStreamServer(t, func(_ int, conn *websocket.Conn) {
+		for _, msg := range []string{tradeMessage, `not json`, `{"stream":"btcusdt@aggTrade","data":{}}`, bookTickerMessage, klineMessage, depthMessage} {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(msg))
+		}
+
+		holdOpen(conn)
+	})
+
+	server.Config.Handler = func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			next.ServeHTTP(w, r)
+		})
+	}(server.Config.Handler)
+
+	streams := []binance.Stream{
+		{Symbol: "BTCUSDT", Type: binance.StreamTrade}, {Symbol: "BTCUSDT", Type: binance.StreamBookTicker},
+		{Symbol: "BTCUSDT", Type: binance.StreamKline, Interval: "1m"}, {Symbol: "BTCUSDT", Type: binance.StreamDepth},
+	}
+
+	var events []binance.StreamEvent
+
+	done := errors.New("done")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := binance.NewStreamer(wsURL(server)).Run(ctx, streams, func(e binance.StreamEvent) error {
+		events = append(events, e)
+		if len(events) == len(streams) {
+			return done
+		}
+
+		return nil
+	})
+	assert.Equal(t, done, err)
+	assert.Equal(t, "streams=btcusdt@trade/btcusdt@bookTicker/btcusdt@kline_1m/btcusdt@depth@100ms", query)
+	assert.Len(t, events, 4)
+
+	assert.Equal(t, &connector.Trade{
+		ID: 7, Symbol: "BTCUSDT", Price: decimal.RequireFromString("65000.01"), Quantity: decimal.RequireFromString("0.002"),
+		Time: time.UnixMilli(1700000000000), IsBuyerMaker: false,
+	}, events[0].Trade)
+	assert.Equal(t, &binance.BookTicker{
+		Symbol: "BTCUSDT", UpdateID: 9, BidPrice: decimal.RequireFromString("65000"), BidQty: decimal.RequireFromString("1.5"),
+		AskPrice: decimal.RequireFromString("65000.1"), AskQty: decimal.RequireFromString("0.25"),
+	}, events[1].BookTicker)
+	assert.True(t, events[2].Kline.Closed)
+	assert.Equal(t, "1m", events[2].Kline.Candle.Interval)
+	assert.Equal(t, "100.5", events[2].Kline.Candle.Close.String())
+	assert.Equal(t, "99", events[2].Kline.Candle.Low.String())
+	assert.Equal(t, "2", events[2].Kline.Candle.Volume.String())
+	assert.Equal(t, "201", events[2].Kline.Candle.QuoteVolume.String())
+	assert.Equal(t, uint64(4), events[2].Kline.Candle.Trades)
+	assert.Equal(t, time.UnixMilli(1700000040000).UTC(), events[2].Kline.Candle.OpenTime)
+	assert.Equal(t, uint64(157), events[3].Depth.FirstUpdateID)
+	assert.Equal(t, uint64(160), events[3].Depth.FinalUpdateID)
+	assert.Equal(t, []connector.PriceLevel{
+		{Price: decimal.RequireFromString("65000"), Quantity: decimal.RequireFromString("1")},
+		{Price: decimal.RequireFromString("64999"), Quantity: decimal.RequireFromString("0")},
+	}, events[3].Depth.Bids)
+	assert.Equal(t, "btcusdt@depth@100ms", events[3].Stream)
+}
+
+func TestStreamer_Run_Reconnects(t *testing.T) {
+	server, connections := newStreamServer(t, func(n int, conn *websocket.Conn) {
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(tradeMessage))
+
+		if n == 1 {
+			// Drop the first connection without a close frame.
+			return
+		}
+
+		holdOpen(conn)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := 0
+
+	err := binance.NewStreamer(wsURL(server), binance.WithBackoff(time.Millisecond, 10*time.Millisecond)).Run(ctx,
+		[]binance.Stream{{Symbol: "BTCUSDT", Type: binance.StreamTrade}}, func(binance.StreamEvent) error {
+			if received++; received == 2 {
+				cancel()
+			}
+
+			return nil
+		})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, received)
+	assert.Equal(t, int32(2), connections.Load())
+}
+
+func TestStreamer_Run_OnConnect(t *testing.T) {
+	server, _ := newStreamServer(t, func(n int, conn *websocket.Conn) {
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(tradeMessage))
+
+		if n == 1 {
+			return
+		}
+
+		holdOpen(conn)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var connects, received int
+
+	streamer := binance.NewStreamer(wsURL(server), binance.WithBackoff(time.Millisecond, 10*time.Millisecond),
+		binance.WithOnConnect(func() { connects++ }))
+
+	err := streamer.Run(ctx, []binance.Stream{{Symbol: "BTCUSDT", Type: binance.StreamTrade}}, func(binance.StreamEvent) error {
+		assert.Equal(t, received+1, connects, "the connection is reported before its events")
+
+		if received++; received == 2 {
+			cancel()
+		}
+
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, connects)
+}
+
+func TestStreamer_Run_RetriesFailedDials(t *testing.T) {
+	var attempts atomic.Int32
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(tradeMessage))
+		holdOpen(conn)
+	}))
+	defer server.Close()
+
+	stop := errors.New("stop")
+
+	err := binance.NewStreamer(wsURL(server), binance.WithBackoff(time.Millisecond, 2*time.Millisecond)).Run(context.Background(),
+		[]binance.Stream{{Symbol: "BTCUSDT", Type: binance.StreamTrade}}, func(binance.StreamEvent) error {
+			return stop
+		})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestStreamer_Run_ReplacesOldConnections(t *testing.T) {
+	server, connections := newStreamServer(t, func(_ int, conn *websocket.Conn) {
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(tradeMessage))
+		holdOpen(conn)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The backoff is longer than the test timeout, so the connections can only be replaced without a backoff.
+	s := binance.NewStreamer(wsURL(server), binance.WithMaxConnectionAge(20*time.Millisecond), binance.WithBackoff(time.Hour, time.Hour))
+
+	received := 0
+
+	err := s.Run(ctx, []binance.Stream{{Symbol: "BTCUSDT", Type: binance.StreamTrade}}, func(binance.StreamEvent) error {
+		if received++; received == 3 {
+			cancel()
+		}
+
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(3), connections.Load())
+}
+
+func TestStreamer_Run_ReadTimeout(t *testing.T) {
+	server, connections := newStreamServer(t, func(n int, conn *websocket.Conn) {
+		if n > 1 {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(tradeMessage))
+		}
+
+		// The first connection stays silent until the client gives up.
+		holdOpen(conn)
+	})
+
+	stop := errors.New("stop")
+	s := binance.NewStreamer(wsURL(server), binance.WithReadTimeout(20*time.Millisecond), binance.WithBackoff(time.Millisecond, time.Millisecond))
+
+	err := s.Run(context.Background(), []binance.Stream{{Symbol: "BTCUSDT", Type: binance.StreamTrade}}, func(binance.StreamEvent) error {
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, int32(2), connections.Load())
+}
+
+func TestStreamer_Run_NoStream(t *testing.T) {
+	err := binance.NewStreamer("ws://localhost").Run(context.Background(), nil, func(binance.StreamEvent) error { return nil })
+
+	assert.EqualError(t, err, "unknown stream: no stream selected")
+}
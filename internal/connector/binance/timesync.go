@@ -0,0 +1,136 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTimeSyncInterval is the interval of the syncs of Run when none is configured.
+const DefaultTimeSyncInterval = 5 * time.Minute
+
+// Binance rejects a signed request with -1021 when its timestamp is more than 1s ahead of the server time,
+// or older than the receive window, 5s by default.
+const (
+	MaxClockAhead  = time.Second
+	MaxClockBehind = 5 * time.Second
+)
+
+// serverClock gets the time of the Binance server.
+type serverClock interface {
+	GetServerTime(ctx context.Context) (time.Time, error)
+}
+
+// Drift is a measure of the local clock against the Binance server clock. The server time is compared with the local time
+// at the middle of the round trip of the request, so Offset is accurate to half of Latency.
+type Drift struct {
+	LocalTime  time.Time
+	ServerTime time.Time
+	// Offset is the server time minus the local time, positive when the local clock is behind the server clock.
+	Offset  time.Duration
+	Latency time.Duration
+}
+
+// Rejected reports whether the signed requests stamped by the local clock would be rejected by Binance.
+func (d Drift) Rejected() bool {
+	return d.Offset < -MaxClockAhead || d.Offset > MaxClockBehind
+}
+
+// TimeSync keeps the offset of the Binance server clock from the local clock, which corrects the timestamps of the signed requests.
+// The offset is zero until the first sync.
+type TimeSync struct {
+	server   serverClock
+	now      func() time.Time
+	interval time.Duration
+	mu       sync.RWMutex
+	drift    Drift
+}
+
+// TimeSyncOption configures the time sync.
+type TimeSyncOption func(*TimeSync)
+
+// WithTimeSyncInterval sets the interval of the syncs of Run. The default interval is kept when it is not positive.
+func WithTimeSyncInterval(d time.Duration) TimeSyncOption {
+	return func(t *TimeSync) {
+		if d > 0 {
+			t.interval = d
+		}
+	}
+}
+
+// WithTimeSyncClock sets the local clock of the time sync.
+func WithTimeSyncClock(now func() time.Time) TimeSyncOption {
+	return func(t *TimeSync) {
+		t.now = now
+	}
+}
+
+// NewTimeSync creates a time sync getting the server time from the server.
+func NewTimeSync(server serverClock, opts ...TimeSyncOption) *TimeSync {
+	t := &TimeSync{server: server, now: time.Now, interval: DefaultTimeSyncInterval}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Measure measures the drift of the local clock without changing the offset.
+func (t *TimeSync) Measure(ctx context.Context) (Drift, error) {
+	sent := t.now()
+
+	server, err := t.server.GetServerTime(ctx)
+	if err != nil {
+		return Drift{}, fmt.Errorf("error measuring clock drift: %w", err)
+	}
+
+	latency := t.now().Sub(sent)
+	local := sent.Add(latency / 2)
+
+	return Drift{LocalTime: local, ServerTime: server, Offset: server.Sub(local), Latency: latency}, nil
+}
+
+// Sync measures the drift of the local clock and corrects the timestamps of the signed requests by its offset.
+func (t *TimeSync) Sync(ctx context.Context) (Drift, error) {
+	d, err := t.Measure(ctx)
+	if err != nil {
+		return Drift{}, err
+	}
+
+	t.mu.Lock()
+	t.drift = d
+	t.mu.Unlock()
+
+	return d, nil
+}
+
+// Offset returns the offset of the server clock from the local clock measured by the last sync.
+func (t *TimeSync) Offset() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.drift.Offset
+}
+
+// Now returns the local time corrected by the offset, the time of the server clock.
+func (t *TimeSync) Now() time.Time {
+	return t.now().Add(t.Offset())
+}
+
+// Run syncs at every interval until the context is done, and calls fn with the result of every sync.
+// A failed sync keeps the previous offset.
+func (t *TimeSync) Run(ctx context.Context, fn func(Drift, error)) error {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("error syncing time: %w", ctx.Err())
+		case <-ticker.C:
+			fn(t.Sync(ctx))
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package binance_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector/binance"
+)
+
+// fakeServerClock returns the server time, or the error.
+type fakeServerClock struct {
+	time time.Time
+	err  error
+}
+
+func (c fakeServerClock) GetServerTime(_ context.Context) (time.Time, error) {
+	return c.time, c.err
+}
+
+// steppingClock returns the start time, then steps it forward at every call.
+func steppingClock(start time.Time, step time.Duration) func() time.Time {
+	calls := -1
+
+	return func() time.Time {
+		calls++
+
+		return start.Add(time.Duration(calls) * step)
+	}
+}
+
+func TestTimeSync_Measure(t *testing.T) {
+	t.Parallel()
+
+	local := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	type want struct {
+		drift binance.Drift
+		err   error
+	}
+
+	tests := map[string]struct {
+		server fakeServerClock
+		want   want
+	}{
+		"Local clock behind": {
+			server: fakeServerClock{time: local.Add(50*time.Millisecond + 2*time.Second)},
+			want: want{drift: binance.Drift{
+				LocalTime:  local.Add(50 * time.Millisecond),
+				ServerTime: local.Add(50*time.Millisecond + 2*time.Second),
+				Offset:     2 * time.Second,
+				Latency:    100 * time.Millisecond,
+			}},
+		},
+		"Local clock ahead": {
+			server: fakeServerClock{time: local.Add(-time.Second)},
+			want: want{drift: binance.Drift{
+				LocalTime:  local.Add(50 * time.Millisecond),
+				ServerTime: local.Add(-time.Second),
+				Offset:     -1050 * time.Millisecond,
+				Latency:    100 * time.Millisecond,
+			}},
+		},
+		"Server error": {
+			server: fakeServerClock{err: errors.New("server error")},
+			want:   want{err: fmt.Errorf("error measuring clock drift: %w", errors.New("server error"))},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := binance.NewTimeSync(tt.server, binance.WithTimeSyncClock(steppingClock(local, 100*time.Millisecond)))
+
+			d, err := ts.Measure(context.Background())
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.drift, d)
+			assert.Zero(t, ts.Offset(), "measuring does not change the offset")
+		})
+	}
+}
+
+func TestTimeSync_Sync(t *testing.T) {
+	t.Parallel()
+
+	local := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	server := &fakeServerClock{time: local.Add(3 * time.Second)}
+	ts := binance.NewTimeSync(server, binance.WithTimeSyncClock(steppingClock(local, 0)))
+
+	assert.Zero(t, ts.Offset())
+	assert.Equal(t, local, ts.Now())
+
+	d, err := ts.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3*time.Second, d.Offset)
+	assert.Equal(t, 3*time.Second, ts.Offset())
+	assert.Equal(t, local.Add(3*time.Second), ts.Now())
+
+	server.err = errors.New("server error")
+
+	_, err = ts.Sync(context.Background())
+	assert.EqualError(t, err, "error measuring clock drift: server error")
+	assert.Equal(t, 3*time.Second, ts.Offset(), "a failed sync keeps the offset")
+}
+
+func TestDrift_Rejected(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		offset time.Duration
+		want   bool
+	}{
+		"In sync":                         {offset: 0, want: false},
+		"Local clock 1s ahead":            {offset: -time.Second, want: false},
+		"Local clock more than 1s ahead":  {offset: -time.Second - time.Millisecond, want: true},
+		"Local clock 5s behind":           {offset: 5 * time.Second, want: false},
+		"Local clock more than 5s behind": {offset: 5*time.Second + time.Millisecond, want: true},
+		"Local clock a minute behind":     {offset: time.Minute, want: true},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, binance.Drift{Offset: tt.offset}.Rejected())
+		})
+	}
+}
+
+func TestTimeSync_Run(t *testing.T) {
+	t.Parallel()
+
+	ts := binance.NewTimeSync(fakeServerClock{time: time.Now().Add(time.Hour)}, binance.WithTimeSyncInterval(10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var syncs atomic.Int32
+
+	err := ts.Run(ctx, func(_ binance.Drift, err error) {
+		assert.NoError(t, err)
+
+		if syncs.Add(1) == 2 {
+			cancel()
+		}
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "error syncing time")
+	assert.Equal(t, int32(2), syncs.Load())
+	assert.InDelta(t, time.Hour, ts.Offset(), float64(time.Second))
+}
+
+func TestNewBinanceService_TimeSync(t *testing.T) {
+	t.Parallel()
+
+	const offset = time.Hour
+
+	tests := map[string]struct {
+		timeStatus int
+		want       time.Duration
+	}{
+		"synced on creation":              {timeStatus: http.StatusOK, want: offset},
+		"local clock when the sync fails": {timeStatus: http.StatusInternalServerError},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var timestamp atomic.Int64
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v3/time":
+					w.WriteHeader(tt.timeStatus)
+					_, _ = fmt.Fprintf(w, `{"serverTime":%d}`, time.Now().Add(offset).UnixMilli())
+				case "/api/v3/account":
+					ms, _ := strconv.ParseInt(r.URL.Query().Get("timestamp"), 10, 64)
+					timestamp.Store(ms)
+
+					_, _ = w.Write([]byte(`{"balances":[]}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			cfg := &config.Config{Connector: config.Connector{Binance: config.Binance{BaseURL: server.URL, APIKey: "key", SecretKey: "secret"}}}
+
+			s, err := binance.NewBinanceService(cfg)
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want, s.TimeSync().Offset(), float64(time.Second))
+
+			_, err = s.GetAccount(context.Background())
+			assert.NoError(t, err)
+			assert.InDelta(t, time.Now().Add(tt.want).UnixMilli(), timestamp.Load(), float64(time.Second.Milliseconds()))
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/account"
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// Default settings of the user data stream. Binance expires a listen key 60 minutes after its last keep-alive.
+const (
+	defaultKeepAliveInterval = 30 * time.Minute
+	closeListenKeyTimeout    = 5 * time.Second
+	userDataStreamPath       = "/ws/"
+	userDataStreamName       = "userData"
+)
+
+// The user data events handled by the user data stream.
+const (
+	eventExecutionReport  = "executionReport"
+	eventAccountPosition  = "outboundAccountPosition"
+	eventListenKeyExpired = "listenKeyExpired"
+)
+
+// errListenKeyExpired is returned internally when Binance expired the listen key, so a new one has to be created.
+var errListenKeyExpired = errors.New("listen key expired")
+
+// ExecutionReport is an update of an order of the account, sent when the order is created, traded, cancelled, rejected or expired.
+// Order holds the state of the order after the update, and the Last fields describe the trade of the update, if any.
+type ExecutionReport struct {
+	Order                   connector.Order
+	ExecutionType           string
+	RejectReason            string
+	TradeID                 int64
+	LastQuantity            decimal.Decimal
+	LastPrice               decimal.Decimal
+	CumulativeQuoteQuantity decimal.Decimal
+	Commission              decimal.Decimal
+	CommissionAsset         string
+	IsMaker                 bool
+	Time                    time.Time
+}
+
+// AccountPosition holds the balances of the assets changed by an account update.
+type AccountPosition struct {
+	Time     time.Time
+	Balances []connector.Balance
+}
+
+// UserDataEvent is an event received from the user data stream. Exactly one of the pointers is set.
+type UserDataEvent struct {
+	ExecutionReport *ExecutionReport
+	AccountPosition *AccountPosition
+}
+
+// ApplyTo applies the event to the account state. Orders that are no longer open are removed from the state.
+func (e UserDataEvent) ApplyTo(s *account.State) {
+	switch {
+	case e.ExecutionReport != nil:
+		o := e.ExecutionReport.Order
+		if isOpenOrderStatus(o.Status) {
+			s.UpsertOrder(e.ExecutionReport.Time, o)
+		} else {
+			s.RemoveOrder(e.ExecutionReport.Time, o.Symbol, o.ID)
+		}
+	case e.AccountPosition != nil:
+		s.UpdateBalances(e.AccountPosition.Time, e.AccountPosition.Balances)
+	}
+}
+
+func isOpenOrderStatus(status string) bool {
+	switch status {
+	case "NEW", "PARTIALLY_FILLED", "PENDING_NEW":
+		return true
+	default:
+		return false
+	}
+}
+
+// CreateListenKey creates a listen key of the user data stream.
+func (s *Service) CreateListenKey(ctx context.Context) (string, error) {
+	key, err := s.client.NewCreateListenKeyService().Do(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error creating listen key: %w", err)
+	}
+
+	return key, nil
+}
+
+// KeepAliveListenKey extends the validity of the listen key by 60 minutes.
+func (s *Service) KeepAliveListenKey(ctx context.Context, listenKey string) error {
+	if err := s.client.NewPingUserStreamService(listenKey).Do(ctx); err != nil {
+		return fmt.Errorf("error keeping listen key alive: %w", err)
+	}
+
+	return nil
+}
+
+// CloseListenKey closes the listen key, ending its user data stream.
+func (s *Service) CloseListenKey(ctx context.Context, listenKey string) error {
+	if err := s.client.NewCloseUserStreamService(listenKey).Do(ctx); err != nil {
+		return fmt.Errorf("error closing listen key: %w", err)
+	}
+
+	return nil
+}
+
+// RunUserData subscribes to the user data stream of the listen key and calls fn with every order and balance update,
+// reconnecting like Run until the context is done or fn fails. Other events are ignored.
+func (s *Streamer) RunUserData(ctx context.Context, listenKey string, fn func(UserDataEvent) error) error {
+	return s.run(ctx, s.baseURL+userDataStreamPath+listenKey, []string{userDataStreamName}, func(msg []byte) error {
+		event, ok, err := parseUserDataEvent(msg)
+		if errors.Is(err, errListenKeyExpired) {
+			return err
+		}
+
+		if err != nil {
+			s.log.Warn("skipping invalid user data event", zap.Error(err), zap.ByteString("message", msg))
+			return nil
+		}
+
+		if !ok {
+			return nil
+		}
+
+		return fn(event)
+	})
+}
+
+// listenKeyManager manages the listen keys of the user data stream. It is implemented by Service.
+type listenKeyManager interface {
+	CreateListenKey(ctx context.Context) (string, error)
+	KeepAliveListenKey(ctx context.Context, listenKey string) error
+	CloseListenKey(ctx context.Context, listenKey string) error
+}
+
+// UserDataStream publishes the order and balance updates of the account.
+// It creates a listen key, keeps it alive, and replaces it when Binance expires it.
+type UserDataStream struct {
+	listenKeys listenKeyManager
+	streamer   *Streamer
+	keepAlive  time.Duration
+	log        *zap.Logger
+}
+
+// UserDataStreamOption configures the user data stream.
+type UserDataStreamOption func(*UserDataStream)
+
+// WithKeepAliveInterval sets how often the listen key is kept alive.
+func WithKeepAliveInterval(d time.Duration) UserDataStreamOption {
+	return func(u *UserDataStream) {
+		u.keepAlive = d
+	}
+}
+
+// WithUserDataLogger sets the logger of the listen key renewals.
+func WithUserDataLogger(l *zap.Logger) UserDataStreamOption {
+	return func(u *UserDataStream) {
+		u.log = l
+	}
+}
+
+// NewUserDataStream creates a new user data stream.
+func NewUserDataStream(listenKeys listenKeyManager, streamer *Streamer, opts ...UserDataStreamOption) *UserDataStream {
+	u := &UserDataStream{
+		listenKeys: listenKeys,
+		streamer:   streamer,
+		keepAlive:  defaultKeepAliveInterval,
+		log:        zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	return u
+}
+
+// Run publishes the events of the user data stream on the channel until the context is done, and closes the channel when it returns.
+// It returns an error wrapping the context error, or the error of a listen key creation.
+func (u *UserDataStream) Run(ctx context.Context, events chan<- UserDataEvent) error {
+	defer close(events)
+
+	for {
+		listenKey, err := u.listenKeys.CreateListenKey(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = u.serve(ctx, listenKey, events)
+		if errors.Is(err, errListenKeyExpired) {
+			u.log.Warn("listen key expired, creating a new one")
+			continue
+		}
+
+		u.close(ctx, listenKey)
+
+		return err
+	}
+}
+
+// close closes the listen key even when the context is done, so that it does not outlive the stream.
+func (u *UserDataStream) close(ctx context.Context, listenKey string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), closeListenKeyTimeout)
+	defer cancel()
+
+	if err := u.listenKeys.CloseListenKey(ctx, listenKey); err != nil {
+		u.log.Warn("error closing listen key", zap.Error(err))
+	}
+}
+
+// serve streams the events of the listen key, keeping it alive until the stream stops.
+func (u *UserDataStream) serve(ctx context.Context, listenKey string, events chan<- UserDataEvent) error {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(u.keepAlive)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// A failed keep-alive is retried on the next tick. Binance sends listenKeyExpired if the key expires meanwhile.
+				if err := u.listenKeys.KeepAliveListenKey(ctx, listenKey); err != nil && ctx.Err() == nil {
+					u.log.Warn("error keeping listen key alive", zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	defer func() { <-done }()
+	defer cancel()
+
+	return u.streamer.RunUserData(ctx, listenKey, func(e UserDataEvent) error {
+		select {
+		case events <- e:
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("user data stream stopped: %w", ctx.Err())
+		}
+	})
+}
+
+// executionReportEvent holds the fields of an executionReport event. Every key of the event is declared,
+// as JSON keys are matched case insensitively and keys like c and C would otherwise be decoded into the same field,
+// which fails when their types differ, e.g. a and A.
+type executionReportEvent struct {
+	EventType               string          `json:"e"`
+	EventTime               int64           `json:"E"`
+	Symbol                  string          `json:"s"`
+	ClientOrderID           string          `json:"c"`
+	Side                    string          `json:"S"`
+	OrderType               string          `json:"o"`
+	TimeInForce             string          `json:"f"`
+	Quantity                decimal.Decimal `json:"q"`
+	Price                   decimal.Decimal `json:"p"`
+	StopPrice               decimal.Decimal `json:"P"`
+	TrailingDelta           int64           `json:"d"`
+	IcebergQuantity         decimal.Decimal `json:"F"`
+	OrderListID             int64           `json:"g"`
+	OrigClientOrderID       string          `json:"C"`
+	ExecutionType           string          `json:"x"`
+	Status                  string          `json:"X"`
+	RejectReason            string          `json:"r"`
+	OrderID                 int64           `json:"i"`
+	LastQuantity            decimal.Decimal `json:"l"`
+	CumulativeQuantity      decimal.Decimal `json:"z"`
+	LastPrice               decimal.Decimal `json:"L"`
+	Commission              decimal.Decimal `json:"n"`
+	CommissionAsset         string          `json:"N"`
+	TransactionTime         int64           `json:"T"`
+	TradeID                 int64           `json:"t"`
+	PreventedMatchID        int64           `json:"v"`
+	Ignore                  int64           `json:"I"`
+	IsWorking               bool            `json:"w"`
+	IsMaker                 bool            `json:"m"`
+	IgnoreM                 bool            `json:"M"`
+	CreationTime            int64           `json:"O"`
+	CumulativeQuoteQuantity decimal.Decimal `json:"Z"`
+	LastQuoteQuantity       decimal.Decimal `json:"Y"`
+	QuoteOrderQuantity      decimal.Decimal `json:"Q"`
+	TrailingTime            int64           `json:"D"`
+	WorkingTime             int64           `json:"W"`
+	StrategyID              int64           `json:"j"`
+	StrategyType            int64           `json:"J"`
+	SelfTradePrevention     string          `json:"V"`
+	TradeGroupID            int64           `json:"u"`
+	CounterOrderID          int64           `json:"U"`
+	PreventedQuantity       decimal.Decimal `json:"A"`
+	LastPreventedQuantity   decimal.Decimal `json:"B"`
+	AllocationID            int64           `json:"a"`
+	MatchType               string          `json:"b"`
+}
+
+// accountPositionEvent holds the fields of an outboundAccountPosition event.
+type accountPositionEvent struct {
+	EventType  string `json:"e"`
+	EventTime  int64  `json:"E"`
+	UpdateTime int64  `json:"u"`
+	Balances   []struct {
+		Asset  string          `json:"a"`
+		Free   decimal.Decimal `json:"f"`
+		Locked decimal.Decimal `json:"l"`
+	} `json:"B"`
+}
+
+// parseUserDataEvent parses an event of the user data stream. It returns false for the events that are not published,
+// and errListenKeyExpired when the listen key expired.
+func parseUserDataEvent(msg []byte) (UserDataEvent, bool, error) {
+	var kind struct {
+		EventType string `json:"e"`
+		EventTime int64  `json:"E"`
+	}
+
+	if err := json.Unmarshal(msg, &kind); err != nil {
+		return UserDataEvent{}, false, fmt.Errorf("error decoding user data event: %w", err)
+	}
+
+	switch kind.EventType {
+	case eventExecutionReport, eventAccountPosition:
+	case eventListenKeyExpired:
+		return UserDataEvent{}, false, errListenKeyExpired
+	default:
+		return UserDataEvent{}, false, nil
+	}
+
+	if kind.EventType == eventAccountPosition {
+		var e accountPositionEvent
+		if err := json.Unmarshal(msg, &e); err != nil {
+			return UserDataEvent{}, false, fmt.Errorf("error decoding %s event: %w", kind.EventType, err)
+		}
+
+		p := &AccountPosition{Time: time.UnixMilli(e.UpdateTime).UTC(), Balances: make([]connector.Balance, 0, len(e.Balances))}
+		for _, b := range e.Balances {
+			p.Balances = append(p.Balances, connector.Balance{Asset: b.Asset, Free: b.Free, Locked: b.Locked})
+		}
+
+		return UserDataEvent{AccountPosition: p}, true, nil
+	}
+
+	var e executionReportEvent
+	if err := json.Unmarshal(msg, &e); err != nil {
+		return UserDataEvent{}, false, fmt.Errorf("error decoding %s event: %w", kind.EventType, err)
+	}
+
+	// Cancelled orders are reported under the client order ID of the cancel request, the original one is in C.
+	clientOrderID := e.ClientOrderID
+	if e.OrigClientOrderID != "" {
+		clientOrderID = e.OrigClientOrderID
+	}
+
+	return UserDataEvent{ExecutionReport: &ExecutionReport{
+		Order: connector.Order{
+			ID: e.OrderID, ClientOrderID: clientOrderID, Symbol: e.Symbol, Side: connector.Side(e.Side), Type: connector.OrderType(e.OrderType),
+			Status: e.Status, Price: e.Price, Quantity: e.Quantity, ExecutedQuantity: e.CumulativeQuantity, Time: time.UnixMilli(e.CreationTime).UTC(),
+		},
+		ExecutionType: e.ExecutionType, RejectReason: e.RejectReason, TradeID: e.TradeID,
+		LastQuantity: e.LastQuantity, LastPrice: e.LastPrice, CumulativeQuoteQuantity: e.CumulativeQuoteQuantity,
+		Commission: e.Commission, CommissionAsset: e.CommissionAsset, IsMaker: e.IsMaker, Time: time.UnixMilli(e.TransactionTime).UTC(),
+	}}, true, nil
+}
@@ -0,0 +1,314 @@
+package binance_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/account"
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+	mock_binance "github.com/twk/trader-b/internal/connector/binance/mocks"
+)
+
+const (
+	newOrderMessage = `{"e":"executionReport","E":1700000000001,"s":"BTCUSDT","c":"my-order","S":"BUY","o":"LIMIT","f":"GTC","q":"0.002",` +
+		`"p":"65000","P":"0","F":"0","g":-1,"C":"","x":"NEW","X":"NEW","r":"NONE","i":7,"l":"0","z":"0","L":"0","n":"0","N":null,` +
+		`"T":1700000000000,"t":-1,"I":1,"w":true,"m":false,"M":false,"O":1700000000000,"Z":"0","Y":"0","Q":"0","W":1700000000000,"V":"NONE"}`
+	filledOrderMessage = `{"e":"executionReport","E":1700000001001,"s":"BTCUSDT","c":"my-order","S":"BUY","o":"LIMIT","f":"GTC","q":"0.002",` +
+		`"p":"65000","P":"0","F":"0","g":-1,"C":"","x":"TRADE","X":"FILLED","r":"NONE","i":7,"l":"0.002","z":"0.002","L":"64999.5","n":"0.000002",` +
+		`"N":"BTC","T":1700000001000,"t":99,"I":2,"w":false,"m":true,"M":true,"O":1700000000000,"Z":"129.999","Y":"129.999","Q":"0","W":1700000000000,"V":"NONE"}`
+	cancelledOrderMessage = `{"e":"executionReport","E":1700000002001,"s":"ETHUSDT","c":"cancel-request","S":"SELL","o":"LIMIT","f":"GTC","q":"1",` +
+		`"p":"3000","C":"other-order","x":"CANCELED","X":"CANCELED","r":"NONE","i":8,"l":"0","z":"0","L":"0","n":"0","N":null,` +
+		`"T":1700000002000,"t":-1,"O":1690000000000,"Z":"0"}`
+	preventedOrderMessage = `{"e":"executionReport","E":1700000003001,"s":"ETHBTC","c":"stp-order","S":"SELL","o":"LIMIT","f":"GTC","q":"1.00000000",` +
+		`"p":"0.01000000","P":"0.00000000","F":"0.00000000","g":-1,"C":"","x":"TRADE_PREVENTION","X":"EXPIRED_IN_MATCH","r":"NONE","i":9,` +
+		`"l":"0.00000000","z":"0.00000000","L":"0.00000000","n":"0","N":null,"T":1700000003000,"t":-1,"v":3,"I":14,"w":false,"m":false,"M":false,` +
+		`"O":1700000002000,"Z":"0.00000000","Y":"0.00000000","Q":"0.00000000","W":1700000002000,"V":"EXPIRE_MAKER","u":1,"U":37,` +
+		`"A":"1.00000000","B":"1.00000000"}`
+	accountPositionMessage = `{"e":"outboundAccountPosition","E":1700000001002,"u":1700000001000,` +
+		`"B":[{"a":"BTC","f":"0.001998","l":"0"},{"a":"USDT","f":"870.001","l":"0"}]}`
+	balanceUpdateMessage    = `{"e":"balanceUpdate","E":1700000001003,"a":"USDT","d":"100","T":1700000001003}`
+	listenKeyExpiredMessage = `{"e":"listenKeyExpired","E":1700000000000,"listenKey":"key-1"}`
+)
+
+// userDataServer serves the listen key endpoints and the user data streams of the listen keys key-1, key-2...
+// The stream of key-1 is expired by the server, and the stream of every other key receives the messages.
+type userDataServer struct {
+	mu         sync.Mutex
+	created    int
+	keepAlives []string
+	closed     []string
+	createErr  bool
+}
+
+func (s *userDataServer) start(t *testing.T, messages ...string) *httptest.Server {
+	t.Helper()
+
+	var (
+		upgrader websocket.Upgrader
+		wg       sync.WaitGroup
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/userDataStream", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if r.Header.Get("X-MBX-APIKEY") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPost:
+			if s.createErr {
+				w.WriteHeader(http.StatusTeapot)
+				_, _ = w.Write([]byte(`{"code":-1000,"msg":"unknown"}`))
+
+				return
+			}
+
+			s.created++
+			_, _ = fmt.Fprintf(w, `{"listenKey":"key-%d"}`, s.created)
+		case http.MethodPut:
+			s.keepAlives = append(s.keepAlives, r.URL.Query().Get("listenKey"))
+			_, _ = w.Write([]byte(`{}`))
+		case http.MethodDelete:
+			s.closed = append(s.closed, r.URL.Query().Get("listenKey"))
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+	mux.HandleFunc("/ws/", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		wg.Add(1)
+		defer wg.Done()
+		defer conn.Close()
+
+		if strings.TrimPrefix(r.URL.Path, "/ws/") == "key-1" {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(listenKeyExpiredMessage))
+		} else {
+			for _, m := range messages {
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(m))
+			}
+		}
+
+		holdOpen(conn)
+	})
+
+	server := httptest.NewServer(mux)
+
+	t.Cleanup(func() {
+		server.CloseClientConnections()
+		server.Close()
+		wg.Wait()
+	})
+
+	return server
+}
+
+func (s *userDataServer) calls() (created int, keepAlives, closed []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.created, append([]string(nil), s.keepAlives...), append([]string(nil), s.closed...)
+}
+
+func newUserDataStream(t *testing.T, server *httptest.Server) *binance.UserDataStream {
+	t.Helper()
+
+	cfg := &config.Config{Connector: config.Connector{Binance: config.Binance{BaseURL: server.URL, APIKey: "key", SecretKey: "secret"}}}
+
+	service, err := binance.NewBinanceService(cfg)
+	assert.NoError(t, err)
+
+	return binance.NewUserDataStream(service, binance.NewStreamer(wsURL(server), binance.WithBackoff(time.Millisecond, time.Millisecond)),
+		binance.WithKeepAliveInterval(10*time.Millisecond))
+}
+
+func TestUserDataStream_Run(t *testing.T) {
+	t.Parallel()
+
+	s := &userDataServer{}
+	server := s.start(t, newOrderMessage, `{"e":`, balanceUpdateMessage, filledOrderMessage, accountPositionMessage)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events := make(chan binance.UserDataEvent)
+	errc := make(chan error, 1)
+
+	go func() {
+		errc <- newUserDataStream(t, server).Run(ctx, events)
+	}()
+
+	state := account.New([]connector.Balance{{Asset: "USDT", Free: decimal.NewFromInt(1000)}}, nil)
+
+	e := <-events
+	e.ApplyTo(state)
+	assert.Equal(t, &binance.ExecutionReport{
+		Order: connector.Order{
+			ID: 7, ClientOrderID: "my-order", Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Status: "NEW",
+			Price: decimal.RequireFromString("65000"), Quantity: decimal.RequireFromString("0.002"), ExecutedQuantity: decimal.RequireFromString("0"),
+			Time: time.UnixMilli(1700000000000).UTC(),
+		},
+		ExecutionType: "NEW", RejectReason: "NONE", TradeID: -1, LastQuantity: decimal.RequireFromString("0"), LastPrice: decimal.RequireFromString("0"),
+		CumulativeQuoteQuantity: decimal.RequireFromString("0"), Commission: decimal.RequireFromString("0"), Time: time.UnixMilli(1700000000000).UTC(),
+	}, e.ExecutionReport)
+	assert.Len(t, state.OpenOrders("BTCUSDT"), 1)
+
+	e = <-events
+	e.ApplyTo(state)
+	assert.Equal(t, "FILLED", e.ExecutionReport.Order.Status)
+	assert.Equal(t, "0.002", e.ExecutionReport.LastQuantity.String())
+	assert.Equal(t, "64999.5", e.ExecutionReport.LastPrice.String())
+	assert.Equal(t, "BTC", e.ExecutionReport.CommissionAsset)
+	assert.Equal(t, int64(99), e.ExecutionReport.TradeID)
+	assert.True(t, e.ExecutionReport.IsMaker)
+	assert.Empty(t, state.OpenOrders(""))
+
+	e = <-events
+	e.ApplyTo(state)
+	assert.Equal(t, &binance.AccountPosition{
+		Time: time.UnixMilli(1700000001000).UTC(),
+		Balances: []connector.Balance{
+			{Asset: "BTC", Free: decimal.RequireFromString("0.001998"), Locked: decimal.RequireFromString("0")},
+			{Asset: "USDT", Free: decimal.RequireFromString("870.001"), Locked: decimal.RequireFromString("0")},
+		},
+	}, e.AccountPosition)
+	assert.Equal(t, e.AccountPosition.Balances, state.Balances())
+	assert.Equal(t, time.UnixMilli(1700000001000).UTC(), state.Updated())
+
+	assert.Eventually(t, func() bool {
+		_, keepAlives, _ := s.calls()
+		return len(keepAlives) > 0
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+
+	_, ok := <-events
+	assert.False(t, ok)
+	assert.ErrorIs(t, <-errc, context.Canceled)
+
+	created, keepAlives, closed := s.calls()
+	assert.Equal(t, 2, created)
+	assert.Equal(t, "key-2", keepAlives[len(keepAlives)-1])
+	assert.Equal(t, []string{"key-2"}, closed)
+}
+
+func TestUserDataStream_Run_CreateListenKeyError(t *testing.T) {
+	t.Parallel()
+
+	s := &userDataServer{createErr: true}
+	server := s.start(t)
+
+	events := make(chan binance.UserDataEvent)
+
+	err := newUserDataStream(t, server).Run(context.Background(), events)
+	assert.EqualError(t, err, "error creating listen key: <APIError> code=-1000, msg=unknown")
+
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestStreamer_RunUserData_CancelledOrder(t *testing.T) {
+	t.Parallel()
+
+	s := &userDataServer{}
+	server := s.start(t, cancelledOrderMessage)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	state := account.New(nil, []connector.Order{{ID: 8, Symbol: "ETHUSDT", Status: "NEW"}})
+	stop := errors.New("stop")
+
+	err := binance.NewStreamer(wsURL(server)).RunUserData(ctx, "key-2", func(e binance.UserDataEvent) error {
+		e.ApplyTo(state)
+
+		assert.Equal(t, "other-order", e.ExecutionReport.Order.ClientOrderID)
+		assert.Equal(t, connector.SideSell, e.ExecutionReport.Order.Side)
+		assert.Empty(t, e.ExecutionReport.CommissionAsset)
+
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Empty(t, state.OpenOrders(""))
+}
+
+func TestStreamer_RunUserData_PreventedOrder(t *testing.T) {
+	t.Parallel()
+
+	s := &userDataServer{}
+	server := s.start(t, preventedOrderMessage)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	state := account.New(nil, []connector.Order{{ID: 9, Symbol: "ETHBTC", Status: "NEW"}})
+	stop := errors.New("stop")
+
+	err := binance.NewStreamer(wsURL(server)).RunUserData(ctx, "key-2", func(e binance.UserDataEvent) error {
+		e.ApplyTo(state)
+
+		assert.Equal(t, "TRADE_PREVENTION", e.ExecutionReport.ExecutionType)
+		assert.Equal(t, "EXPIRED_IN_MATCH", e.ExecutionReport.Order.Status)
+		assert.Equal(t, "stp-order", e.ExecutionReport.Order.ClientOrderID)
+
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Empty(t, state.OpenOrders(""))
+}
+
+func TestService_ListenKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mock_binance.NewMockClient(ctrl)
+	createClient := mock_binance.NewMockListenKeyClient(ctrl)
+	pingClient := mock_binance.NewMockUserStreamClient(ctrl)
+	closeClient := mock_binance.NewMockUserStreamClient(ctrl)
+
+	client.EXPECT().NewCreateListenKeyService().Return(createClient).Times(2)
+	createClient.EXPECT().Do(gomock.Any()).Return("listen-key", nil)
+	createClient.EXPECT().Do(gomock.Any()).Return("", errors.New("do error"))
+	client.EXPECT().NewPingUserStreamService("listen-key").Return(pingClient).Times(2)
+	pingClient.EXPECT().Do(gomock.Any()).Return(nil)
+	pingClient.EXPECT().Do(gomock.Any()).Return(errors.New("do error"))
+	client.EXPECT().NewCloseUserStreamService("listen-key").Return(closeClient).Times(2)
+	closeClient.EXPECT().Do(gomock.Any()).Return(nil)
+	closeClient.EXPECT().Do(gomock.Any()).Return(errors.New("do error"))
+
+	service := binance.NewService(client)
+	ctx := context.Background()
+
+	key, err := service.CreateListenKey(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "listen-key", key)
+
+	_, err = service.CreateListenKey(ctx)
+	assert.EqualError(t, err, "error creating listen key: do error")
+
+	assert.NoError(t, service.KeepAliveListenKey(ctx, key))
+	assert.EqualError(t, service.KeepAliveListenKey(ctx, key), "error keeping listen key alive: do error")
+
+	assert.NoError(t, service.CloseListenKey(ctx, key))
+	assert.EqualError(t, service.CloseListenKey(ctx, key), "error closing listen key: do error")
+}
@@ -0,0 +1,27 @@
+package connector
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Candle represents an OHLCV candlestick of a symbol over one interval.
+type Candle struct {
+	Symbol      string
+	Interval    string
+	OpenTime    time.Time
+	CloseTime   time.Time
+	Open        decimal.Decimal
+	High        decimal.Decimal
+	Low         decimal.Decimal
+	Close       decimal.Decimal
+	Volume      decimal.Decimal
+	QuoteVolume decimal.Decimal
+	Trades      uint64
+}
+
+// Closed reports whether the interval of the candle has ended at the given time, so its values are final.
+func (c Candle) Closed(now time.Time) bool {
+	return c.CloseTime.Before(now)
+}
@@ -0,0 +1,119 @@
+// Package connector provides the exchange agnostic interface and domain types. Exchange specific connectors live in sub packages and convert their responses into the types defined here.
+package connector
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Exchange is the interface implemented by every exchange connector.
+type Exchange interface {
+	// Name returns the name of the exchange. It matches the key of the exchange in the connector configuration.
+	Name() string
+	// GetBalances gets the balances of the account.
+	GetBalances(ctx context.Context) ([]Balance, error)
+	// GetTickers gets the latest price of the given symbols.
+	GetTickers(ctx context.Context, symbols []string) ([]Ticker, error)
+	// GetOrderBook gets the order book of the symbol limited to the given depth.
+	GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error)
+	// GetTrades gets the most recent trades of the symbol.
+	GetTrades(ctx context.Context, symbol string, limit int) ([]Trade, error)
+	// GetSymbols gets the symbols listed on the exchange.
+	GetSymbols(ctx context.Context) ([]Symbol, error)
+}
+
+// Balance represents the balance of an asset.
+type Balance struct {
+	Asset  string
+	Free   decimal.Decimal
+	Locked decimal.Decimal
+}
+
+// Total returns the sum of the free and locked amounts.
+func (b Balance) Total() decimal.Decimal {
+	return b.Free.Add(b.Locked)
+}
+
+// Ticker represents the latest price of a symbol.
+type Ticker struct {
+	Symbol string
+	Price  decimal.Decimal
+}
+
+// PriceLevel represents a price level of an order book.
+type PriceLevel struct {
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+}
+
+// OrderBook represents a snapshot of the order book of a symbol.
+type OrderBook struct {
+	Symbol       string
+	LastUpdateID uint64
+	Bids         []PriceLevel
+	Asks         []PriceLevel
+}
+
+// Trade represents a public trade of a symbol.
+type Trade struct {
+	ID           uint64
+	Symbol       string
+	Price        decimal.Decimal
+	Quantity     decimal.Decimal
+	Time         time.Time
+	IsBuyerMaker bool
+}
+
+// Symbol represents a tradable pair listed on an exchange.
+type Symbol struct {
+	Name       string
+	BaseAsset  string
+	QuoteAsset string
+	Status     string
+	Filters    SymbolFilters
+}
+
+// SymbolFilters represents the trading rules of a symbol. A zero value means the rule is not enforced.
+// The market quantity rules apply to the market orders on top of the quantity rules. MinNotional and MaxNotional are the
+// strictest bounds of the NotionalFilters when the exchange has several of them.
+type SymbolFilters struct {
+	MinPrice       decimal.Decimal
+	MaxPrice       decimal.Decimal
+	TickSize       decimal.Decimal
+	MinQty         decimal.Decimal
+	MaxQty         decimal.Decimal
+	StepSize       decimal.Decimal
+	MarketMinQty   decimal.Decimal
+	MarketMaxQty   decimal.Decimal
+	MarketStepSize decimal.Decimal
+	MinNotional    decimal.Decimal
+	MaxNotional    decimal.Decimal
+	NotionalFilters []NotionalFilter
+}
+
+// NotionalFilter is a rule of the exchange on the notional of the orders, their price times their quantity,
+// e.g. MIN_NOTIONAL or NOTIONAL on Binance. The market orders are valued at the market price by the bounds applying to them.
+type NotionalFilter struct {
+	Name             string
+	MinNotional      decimal.Decimal
+	MaxNotional      decimal.Decimal
+	ApplyMinToMarket bool
+	ApplyMaxToMarket bool
+}
+
+// NonZeroBalances returns the balances that have a non-zero free or locked amount.
+func NonZeroBalances(balances []Balance) []Balance {
+	res := make([]Balance, 0, len(balances))
+
+	for _, b := range balances {
+		if b.Free.IsZero() && b.Locked.IsZero() {
+			continue
+		}
+
+		res = append(res, b)
+	}
+
+	return res
+}
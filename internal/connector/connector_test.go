@@ -0,0 +1,78 @@
+package connector_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+func TestNonZeroBalances(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		balances []connector.Balance
+	}
+
+	type want struct {
+		res []connector.Balance
+	}
+
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"filters zero balances": {
+			args: args{
+				balances: []connector.Balance{
+					{Asset: "BTC", Free: decimal.RequireFromString("0.5"), Locked: decimal.Zero},
+					{Asset: "ETH", Free: decimal.Zero, Locked: decimal.Zero},
+					{Asset: "BNB", Free: decimal.Zero, Locked: decimal.RequireFromString("1")},
+				},
+			},
+			want: want{
+				res: []connector.Balance{
+					{Asset: "BTC", Free: decimal.RequireFromString("0.5"), Locked: decimal.Zero},
+					{Asset: "BNB", Free: decimal.Zero, Locked: decimal.RequireFromString("1")},
+				},
+			},
+		},
+		"empty balances": {
+			args: args{},
+			want: want{
+				res: []connector.Balance{},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want.res, connector.NonZeroBalances(tt.args.balances))
+		})
+	}
+}
+
+func TestBalance_Total(t *testing.T) {
+	t.Parallel()
+
+	b := connector.Balance{Asset: "BTC", Free: decimal.RequireFromString("0.5"), Locked: decimal.RequireFromString("0.25")}
+
+	assert.True(t, decimal.RequireFromString("0.75").Equal(b.Total()))
+}
+
+func TestCandle_Closed(t *testing.T) {
+	t.Parallel()
+
+	open := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := connector.Candle{OpenTime: open, CloseTime: open.Add(time.Minute - time.Millisecond)}
+
+	assert.False(t, c.Closed(open.Add(30*time.Second)))
+	assert.True(t, c.Closed(open.Add(time.Minute)))
+}
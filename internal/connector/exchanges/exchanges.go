@@ -0,0 +1,20 @@
+// Package exchanges provides the registry of the exchange connectors built into the application.
+package exchanges
+
+import (
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+	"github.com/twk/trader-b/internal/connector/kraken"
+	"github.com/twk/trader-b/internal/connector/paper"
+)
+
+// NewRegistry creates a registry with every built-in exchange registered. The kraken and paper exchanges are only created when they
+// are enabled explicitly, so that the users of Binance alone need no Kraken credentials.
+func NewRegistry() *connector.Registry {
+	r := connector.NewRegistry()
+	r.Register(binance.Name, binance.NewExchange)
+	r.RegisterExplicit(kraken.Name, kraken.NewExchange)
+	r.RegisterExplicit(paper.Name, paper.NewFactory(r))
+
+	return r
+}
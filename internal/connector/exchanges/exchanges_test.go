@@ -0,0 +1,48 @@
+package exchanges_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector/binance"
+	"github.com/twk/trader-b/internal/connector/exchanges"
+	"github.com/twk/trader-b/internal/connector/kraken"
+	"github.com/twk/trader-b/internal/connector/paper"
+)
+
+func TestNewRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := exchanges.NewRegistry()
+
+	assert.Equal(t, []string{binance.Name, kraken.Name, paper.Name}, r.Names())
+
+	for _, name := range r.Names() {
+		e, err := r.Get(&config.Config{}, name)
+		assert.NoError(t, err)
+		assert.Equal(t, name, e.Name())
+	}
+}
+
+func TestNewRegistry_Exchanges(t *testing.T) {
+	t.Parallel()
+
+	r := exchanges.NewRegistry()
+
+	e, err := r.Exchanges(&config.Config{})
+	assert.NoError(t, err)
+
+	if assert.Len(t, e, 1, "the kraken and paper exchanges are not created unless they are enabled explicitly") {
+		assert.Equal(t, binance.Name, e[0].Name())
+	}
+
+	e, err = r.Exchanges(&config.Config{Connector: config.Connector{Exchanges: []string{kraken.Name, paper.Name}}})
+	assert.NoError(t, err)
+
+	if assert.Len(t, e, 2) {
+		assert.Equal(t, kraken.Name, e[0].Name())
+		assert.Equal(t, paper.Name, e[1].Name())
+	}
+}
@@ -0,0 +1,270 @@
+package kraken
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// Minimum sizes of the arrays of the responses.
+const (
+	priceLevelSize = 2
+	tradeSize      = 4
+	tradeIDIndex   = 6
+)
+
+// lastTradesKey is the key of the trades result holding the cursor of the next request, next to the trades of the pair.
+const lastTradesKey = "last"
+
+type assetResponse struct {
+	AltName string `json:"altname"`
+}
+
+type assetPairResponse struct {
+	AltName      string          `json:"altname"`
+	Base         string          `json:"base"`
+	Quote        string          `json:"quote"`
+	Status       string          `json:"status"`
+	LotDecimals  int32           `json:"lot_decimals"`
+	OrderMin     decimal.Decimal `json:"ordermin"`
+	CostMin      decimal.Decimal `json:"costmin"`
+	TickSize     decimal.Decimal `json:"tick_size"`
+	PairDecimals int32           `json:"pair_decimals"`
+}
+
+type balanceResponse struct {
+	Balance   decimal.Decimal `json:"balance"`
+	HoldTrade decimal.Decimal `json:"hold_trade"`
+}
+
+// tickerResponse holds the last trade of the ticker as [price, lot volume].
+type tickerResponse struct {
+	LastTrade []decimal.Decimal `json:"c"`
+}
+
+// depthResponse holds the levels of the book as [price, volume, timestamp].
+type depthResponse struct {
+	Asks [][]json.RawMessage `json:"asks"`
+	Bids [][]json.RawMessage `json:"bids"`
+}
+
+// normalizeAsset converts the alternative name of a Kraken asset into its common name.
+func normalizeAsset(altName string) string {
+	switch altName {
+	case "XBT":
+		return "BTC"
+	case "XDG":
+		return "DOGE"
+	default:
+		return altName
+	}
+}
+
+// assetName returns the common name of a Kraken asset, falling back to the asset name when it is not listed.
+func assetName(assets map[string]string, asset string) string {
+	if altName, ok := assets[asset]; ok && altName != "" {
+		return normalizeAsset(altName)
+	}
+
+	return normalizeAsset(asset)
+}
+
+func toBalances(assets map[string]string, res map[string]balanceResponse) ([]connector.Balance, error) {
+	merged := make(map[string]connector.Balance, len(res))
+
+	for k, b := range res {
+		if b.HoldTrade.GreaterThan(b.Balance) {
+			return nil, fmt.Errorf("invalid balance of %s: %s held by orders out of %s", k, b.HoldTrade, b.Balance)
+		}
+
+		asset := assetName(assets, k)
+		m := merged[asset]
+		merged[asset] = connector.Balance{
+			Asset:  asset,
+			Free:   m.Free.Add(b.Balance.Sub(b.HoldTrade)),
+			Locked: m.Locked.Add(b.HoldTrade),
+		}
+	}
+
+	balances := make([]connector.Balance, 0, len(merged))
+	for _, b := range merged {
+		balances = append(balances, b)
+	}
+
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Asset < balances[j].Asset })
+
+	return balances, nil
+}
+
+func toSymbols(assets map[string]string, res map[string]assetPairResponse) ([]connector.Symbol, error) {
+	symbols := make([]connector.Symbol, 0, len(res))
+
+	for k, p := range res {
+		if p.AltName == "" {
+			return nil, fmt.Errorf("invalid asset pair %s: missing altname", k)
+		}
+
+		symbols = append(symbols, connector.Symbol{
+			Name:       p.AltName,
+			BaseAsset:  assetName(assets, p.Base),
+			QuoteAsset: assetName(assets, p.Quote),
+			Status:     p.Status,
+			Filters: connector.SymbolFilters{
+				TickSize:    p.TickSize,
+				MinQty:      p.OrderMin,
+				StepSize:    decimal.New(1, -p.LotDecimals),
+				MinNotional: p.CostMin,
+			},
+		})
+	}
+
+	return symbols, nil
+}
+
+// toTickers returns the tickers in the order of the symbols. The tickers are keyed by pair name, e.g. XXBTZUSD,
+// while the symbols may be alternative names, e.g. XBTUSD, so the pairs are used to match both.
+func toTickers(symbols []string, pairs map[string]assetPairResponse, res map[string]tickerResponse) ([]connector.Ticker, error) {
+	byName := make(map[string]decimal.Decimal, 2*len(res))
+
+	for k, t := range res {
+		if len(t.LastTrade) == 0 {
+			return nil, fmt.Errorf("invalid ticker of %s: missing last trade", k)
+		}
+
+		byName[k] = t.LastTrade[0]
+		if p, ok := pairs[k]; ok {
+			byName[p.AltName] = t.LastTrade[0]
+		}
+	}
+
+	tickers := make([]connector.Ticker, 0, len(symbols))
+
+	for _, symbol := range symbols {
+		price, ok := byName[strings.ToUpper(symbol)]
+		if !ok {
+			return nil, fmt.Errorf("no ticker returned for %s", symbol)
+		}
+
+		tickers = append(tickers, connector.Ticker{Symbol: symbol, Price: price})
+	}
+
+	return tickers, nil
+}
+
+func toOrderBook(symbol string, res map[string]depthResponse) (*connector.OrderBook, error) {
+	ob := &connector.OrderBook{Symbol: symbol}
+
+	// The result holds a single pair, keyed by its pair name.
+	for _, d := range res {
+		var err error
+
+		if ob.Bids, err = toPriceLevels(d.Bids); err != nil {
+			return nil, fmt.Errorf("error parsing bids of %s: %w", symbol, err)
+		}
+
+		if ob.Asks, err = toPriceLevels(d.Asks); err != nil {
+			return nil, fmt.Errorf("error parsing asks of %s: %w", symbol, err)
+		}
+	}
+
+	return ob, nil
+}
+
+func toPriceLevels(levels [][]json.RawMessage) ([]connector.PriceLevel, error) {
+	res := make([]connector.PriceLevel, 0, len(levels))
+
+	for _, l := range levels {
+		if len(l) < priceLevelSize {
+			return nil, fmt.Errorf("invalid price level %s", l)
+		}
+
+		var price, quantity decimal.Decimal
+
+		if err := json.Unmarshal(l[0], &price); err != nil {
+			return nil, fmt.Errorf("error parsing price: %w", err)
+		}
+
+		if err := json.Unmarshal(l[1], &quantity); err != nil {
+			return nil, fmt.Errorf("error parsing quantity: %w", err)
+		}
+
+		res = append(res, connector.PriceLevel{Price: price, Quantity: quantity})
+	}
+
+	return res, nil
+}
+
+// toTrades converts the trades of the pair, given as [price, volume, time, side, order type, misc, trade id].
+func toTrades(symbol string, res map[string]json.RawMessage) ([]connector.Trade, error) {
+	var trades []connector.Trade
+
+	for k, v := range res {
+		if k == lastTradesKey {
+			continue
+		}
+
+		var raw [][]json.RawMessage
+		if err := json.Unmarshal(v, &raw); err != nil {
+			return nil, fmt.Errorf("error decoding trades of %s: %w", symbol, err)
+		}
+
+		trades = make([]connector.Trade, 0, len(raw))
+
+		for _, r := range raw {
+			t, err := toTrade(symbol, r)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing trade of %s: %w", symbol, err)
+			}
+
+			trades = append(trades, t)
+		}
+	}
+
+	return trades, nil
+}
+
+func toTrade(symbol string, r []json.RawMessage) (connector.Trade, error) {
+	if len(r) < tradeSize {
+		return connector.Trade{}, fmt.Errorf("invalid trade %s", r)
+	}
+
+	var (
+		t         = connector.Trade{Symbol: symbol}
+		timestamp decimal.Decimal
+		side      string
+	)
+
+	if err := json.Unmarshal(r[0], &t.Price); err != nil {
+		return connector.Trade{}, fmt.Errorf("error parsing price: %w", err)
+	}
+
+	if err := json.Unmarshal(r[1], &t.Quantity); err != nil {
+		return connector.Trade{}, fmt.Errorf("error parsing quantity: %w", err)
+	}
+
+	if err := json.Unmarshal(r[2], &timestamp); err != nil {
+		return connector.Trade{}, fmt.Errorf("error parsing time: %w", err)
+	}
+
+	if err := json.Unmarshal(r[3], &side); err != nil {
+		return connector.Trade{}, fmt.Errorf("error parsing side: %w", err)
+	}
+
+	if len(r) > tradeIDIndex {
+		if err := json.Unmarshal(r[tradeIDIndex], &t.ID); err != nil {
+			return connector.Trade{}, fmt.Errorf("error parsing trade id: %w", err)
+		}
+	}
+
+	// The time is in seconds with a fractional part, and a sell trade hit a resting buy order.
+	t.Time = time.UnixMicro(timestamp.Shift(6).IntPart()).UTC()
+	t.IsBuyerMaker = side == "s"
+
+	return t, nil
+}
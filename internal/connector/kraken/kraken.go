@@ -0,0 +1,273 @@
+// Package kraken provides the Kraken connector for the application. It is built on the HTTP client of the application
+// rather than an SDK, and converts the Kraken responses into the exchange agnostic types of the connector package.
+package kraken
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/twk/trader-b/internal/client"
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// Name is the name of the Kraken exchange. It matches the key of the Kraken connector configuration.
+const Name = "kraken"
+
+// DefaultBaseURL is the base URL of the Kraken REST API.
+const DefaultBaseURL = "https://api.kraken.com"
+
+// requestTimeout is the timeout of the requests made by the exchange created by NewExchange.
+const requestTimeout = 10 * time.Second
+
+// The paths of the Kraken endpoints.
+const (
+	assetsPath     = "/0/public/Assets"
+	assetPairsPath = "/0/public/AssetPairs"
+	tickerPath     = "/0/public/Ticker"
+	depthPath      = "/0/public/Depth"
+	tradesPath     = "/0/public/Trades"
+	balancePath    = "/0/private/BalanceEx"
+)
+
+// ErrUnexpectedStatus is returned when Kraken answers with a status other than 200 and without an error message.
+var ErrUnexpectedStatus = errors.New("unexpected status")
+
+var _ connector.Exchange = (*Service)(nil)
+
+// APIError is an error returned by the Kraken API, e.g. EQuery:Unknown asset pair.
+type APIError struct {
+	Errors []string
+}
+
+func (e *APIError) Error() string {
+	return "kraken api error: " + strings.Join(e.Errors, ", ")
+}
+
+// Service is a service for interacting with Kraken.
+type Service struct {
+	client      *client.Client
+	signed      *client.Client
+	baseURL     string
+	credentials config.Kraken
+	nonce       func() int64
+}
+
+// Option configures the service.
+type Option func(*Service)
+
+// WithCredentials sets the API key and secret key of the private endpoints.
+func WithCredentials(cfg config.Kraken) Option {
+	return func(s *Service) {
+		s.credentials = cfg
+	}
+}
+
+// WithNonce sets the nonce generator of the private requests. The nonces have to increase from one request to the next.
+func WithNonce(nonce func() int64) Option {
+	return func(s *Service) {
+		s.nonce = nonce
+	}
+}
+
+// NewService creates a new service using the Kraken API at the base URL.
+func NewService(c *client.Client, baseURL string, opts ...Option) *Service {
+	s := &Service{client: c, baseURL: strings.TrimSuffix(baseURL, "/"), nonce: new(nonceSource).next}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.signed = c.WithSigner(&signer{apiKey: s.credentials.APIKey.Value(), secretKey: s.credentials.SecretKey.Value(), nonce: s.nonce})
+
+	return s
+}
+
+// NewExchange creates the Kraken exchange from the configuration. It is the connector.Factory of Kraken.
+func NewExchange(cfg *config.Config) (connector.Exchange, error) {
+	k := cfg.Connector.Kraken
+
+	baseURL := k.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	c := client.NewClient(&http.Client{Timeout: requestTimeout}, client.WithRetry(cfg.Retry))
+
+	return NewService(c, baseURL, WithCredentials(k)), nil
+}
+
+// Name returns the name of the exchange.
+func (s *Service) Name() string {
+	return Name
+}
+
+// GetBalances gets the balances of the Kraken account. The amount held by open orders is reported as locked.
+func (s *Service) GetBalances(ctx context.Context) ([]connector.Balance, error) {
+	var res map[string]balanceResponse
+	if err := s.private(ctx, balancePath, url.Values{}, &res); err != nil {
+		return nil, fmt.Errorf("error getting balances: %w", err)
+	}
+
+	assets, err := s.assets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBalances(assets, res)
+}
+
+// GetTickers gets the last trade price of the given pairs from Kraken. The pairs are named like the symbols of GetSymbols, e.g. XBTUSD.
+func (s *Service) GetTickers(ctx context.Context, symbols []string) ([]connector.Ticker, error) {
+	if len(symbols) == 0 {
+		return []connector.Ticker{}, nil
+	}
+
+	pairs := url.Values{"pair": {strings.Join(symbols, ",")}}
+
+	var names map[string]assetPairResponse
+	if err := s.public(ctx, assetPairsPath, pairs, &names); err != nil {
+		return nil, fmt.Errorf("error getting pairs of %s: %w", pairs.Get("pair"), err)
+	}
+
+	var res map[string]tickerResponse
+	if err := s.public(ctx, tickerPath, pairs, &res); err != nil {
+		return nil, fmt.Errorf("error getting tickers of %s: %w", pairs.Get("pair"), err)
+	}
+
+	return toTickers(symbols, names, res)
+}
+
+// GetOrderBook gets the order book of the pair from Kraken. Kraken does not number the updates of its books, so LastUpdateID is zero.
+func (s *Service) GetOrderBook(ctx context.Context, symbol string, limit int) (*connector.OrderBook, error) {
+	var res map[string]depthResponse
+	if err := s.public(ctx, depthPath, url.Values{"pair": {symbol}, "count": {strconv.Itoa(limit)}}, &res); err != nil {
+		return nil, fmt.Errorf("error getting order book of %s: %w", symbol, err)
+	}
+
+	return toOrderBook(symbol, res)
+}
+
+// GetTrades gets the most recent trades of the pair from Kraken.
+func (s *Service) GetTrades(ctx context.Context, symbol string, limit int) ([]connector.Trade, error) {
+	var res map[string]json.RawMessage
+	if err := s.public(ctx, tradesPath, url.Values{"pair": {symbol}, "count": {strconv.Itoa(limit)}}, &res); err != nil {
+		return nil, fmt.Errorf("error getting trades of %s: %w", symbol, err)
+	}
+
+	return toTrades(symbol, res)
+}
+
+// GetSymbols gets the pairs listed on Kraken, named by their alternative names, e.g. XBTUSD, with normalized asset names, e.g. BTC.
+func (s *Service) GetSymbols(ctx context.Context) ([]connector.Symbol, error) {
+	assets, err := s.assets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res map[string]assetPairResponse
+	if err := s.public(ctx, assetPairsPath, nil, &res); err != nil {
+		return nil, fmt.Errorf("error getting asset pairs: %w", err)
+	}
+
+	symbols, err := toSymbols(assets, res)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].Name < symbols[j].Name })
+
+	return symbols, nil
+}
+
+// assets gets the alternative names of the Kraken assets keyed by asset name, e.g. XBT for XXBT.
+func (s *Service) assets(ctx context.Context) (map[string]string, error) {
+	var res map[string]assetResponse
+	if err := s.public(ctx, assetsPath, nil, &res); err != nil {
+		return nil, fmt.Errorf("error getting assets: %w", err)
+	}
+
+	names := make(map[string]string, len(res))
+	for k, a := range res {
+		names[k] = a.AltName
+	}
+
+	return names, nil
+}
+
+// public calls a public endpoint and decodes its result.
+func (s *Service) public(ctx context.Context, path string, query url.Values, result interface{}) error {
+	u := s.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	resp, err := s.client.Get(ctx, u)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %w", path, err)
+	}
+
+	return decode(resp, result)
+}
+
+// private calls a private endpoint with a form signed by the credentials and decodes its result.
+func (s *Service) private(ctx context.Context, path string, form url.Values, result interface{}) error {
+	if err := s.credentials.ValidateCredentials(); err != nil {
+		return fmt.Errorf("error validating kraken config: %w", err)
+	}
+
+	resp, err := s.signed.Post(ctx, s.baseURL+path, nil, form)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %w", path, err)
+	}
+
+	return decode(resp, result)
+}
+
+// envelope is the envelope of every Kraken response.
+type envelope struct {
+	Error  []string        `json:"error"`
+	Result json.RawMessage `json:"result"`
+}
+
+// decode closes the response and decodes its result, returning the errors of the envelope as an APIError.
+func decode(resp *http.Response, result interface{}) error {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%w %d: %s", ErrUnexpectedStatus, resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+
+	if len(env.Error) > 0 {
+		return &APIError{Errors: env.Error}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w %d: %s", ErrUnexpectedStatus, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.Unmarshal(env.Result, result); err != nil {
+		return fmt.Errorf("error decoding result: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,349 @@
+package kraken_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/client"
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/kraken"
+)
+
+const (
+	apiKey = "api-key"
+	// secretKey is the secret key of the example of the Kraken documentation.
+	secretKey = "kQH5HW/8p1uGOVjbgWA7FunAmGO8lsSUXNsu3eow76sz84Q18fWxnyRzBHCd3pd5nE9qa99HAZtuZuj6F1huXg=="
+	nonce     = 1616492376594
+)
+
+// fixtures maps the paths of the Kraken endpoints to the recorded responses served for them.
+var fixtures = map[string]string{
+	"/0/public/Assets":     "assets.json",
+	"/0/public/AssetPairs": "asset_pairs.json",
+	"/0/public/Ticker":     "ticker.json",
+	"/0/public/Depth":      "depth.json",
+	"/0/public/Trades":     "trades.json",
+	"/0/private/BalanceEx": "balance_ex.json",
+}
+
+// newServer serves the recorded responses, replacing the fixtures of the overrides, and rejects private requests
+// that are not signed with the API key and secret key.
+func newServer(t *testing.T, status int, overrides map[string]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture, ok := overrides[r.URL.Path]
+		if !ok {
+			fixture, ok = fixtures[r.URL.Path]
+		}
+
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.Method == http.MethodPost && !validSignature(r) {
+			fixture = "invalid_key.json"
+		}
+
+		body, err := os.ReadFile(filepath.Join("testdata", fixture))
+		if err != nil {
+			t.Errorf("error reading fixture %s: %v", fixture, err)
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(status)
+		_, _ = w.Write(body)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// validSignature checks the headers of a private request against the signature computed as documented by Kraken.
+func validSignature(r *http.Request) bool {
+	if err := r.ParseForm(); err != nil {
+		return false
+	}
+
+	key, err := base64.StdEncoding.DecodeString(secretKey)
+	if err != nil {
+		return false
+	}
+
+	digest := sha256.Sum256([]byte(r.PostForm.Get("nonce") + r.PostForm.Encode()))
+	mac := hmac.New(sha512.New, key)
+	mac.Write([]byte(r.URL.Path))
+	mac.Write(digest[:])
+
+	return r.Header.Get("API-Key") == apiKey &&
+		r.Header.Get("Content-Type") == "application/x-www-form-urlencoded" &&
+		r.PostForm.Get("nonce") == "1616492376594" &&
+		r.Header.Get("API-Sign") == base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newService(url string, credentials config.Kraken) *kraken.Service {
+	return kraken.NewService(client.NewClient(&http.Client{Timeout: time.Second}), url,
+		kraken.WithCredentials(credentials), kraken.WithNonce(func() int64 { return nonce }))
+}
+
+func TestService_GetBalances(t *testing.T) {
+	type want struct {
+		balances []connector.Balance
+		err      string
+	}
+
+	tests := map[string]struct {
+		credentials config.Kraken
+		status      int
+		overrides   map[string]string
+		want        want
+	}{
+		"Merges the balances per normalized asset": {
+			credentials: config.Kraken{APIKey: apiKey, SecretKey: secretKey},
+			status:      http.StatusOK,
+			want: want{
+				balances: []connector.Balance{
+					{Asset: "BTC", Free: decimal.RequireFromString("0.4"), Locked: decimal.RequireFromString("0.1")},
+					{Asset: "ETH", Free: decimal.Zero, Locked: decimal.Zero},
+					{Asset: "USD", Free: decimal.RequireFromString("1000"), Locked: decimal.Zero},
+					{Asset: "XBT.F", Free: decimal.RequireFromString("0.01"), Locked: decimal.Zero},
+				},
+			},
+		},
+		"Wrong API key": {
+			credentials: config.Kraken{APIKey: "other", SecretKey: secretKey},
+			status:      http.StatusOK,
+			want:        want{err: "error getting balances: kraken api error: EAPI:Invalid key"},
+		},
+		"Invalid secret key": {
+			credentials: config.Kraken{APIKey: apiKey, SecretKey: "not base64"},
+			status:      http.StatusOK,
+			want: want{err: "error getting balances: error calling /0/private/BalanceEx: failed to sign request: " +
+				"error decoding kraken secret key: illegal base64 data at input byte 3"},
+		},
+		"Missing credentials": {
+			status: http.StatusOK,
+			want: want{err: "error getting balances: error validating kraken config: missing credentials: " +
+				"connector.kraken.api_key, connector.kraken.secret_key must be set for private kraken endpoints"},
+		},
+		"Assets error": {
+			credentials: config.Kraken{APIKey: apiKey, SecretKey: secretKey},
+			status:      http.StatusOK,
+			overrides:   map[string]string{"/0/public/Assets": "unknown_pair.json"},
+			want:        want{err: "error getting assets: kraken api error: EQuery:Unknown asset pair"},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newServer(t, tt.status, tt.overrides)
+
+			balances, err := newService(server.URL, tt.credentials).GetBalances(context.Background())
+			if tt.want.err != "" {
+				assert.EqualError(t, err, tt.want.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, balances, len(tt.want.balances))
+
+			for i, b := range tt.want.balances {
+				assert.Equal(t, b.Asset, balances[i].Asset)
+				assert.True(t, b.Free.Equal(balances[i].Free), "free of %s: %s", b.Asset, balances[i].Free)
+				assert.True(t, b.Locked.Equal(balances[i].Locked), "locked of %s: %s", b.Asset, balances[i].Locked)
+			}
+		})
+	}
+}
+
+func TestService_GetSymbols(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t, http.StatusOK, nil)
+
+	symbols, err := newService(server.URL, config.Kraken{}).GetSymbols(context.Background())
+	assert.NoError(t, err)
+
+	names := make([]string, 0, len(symbols))
+	for _, s := range symbols {
+		names = append(names, s.Name)
+	}
+
+	assert.Equal(t, []string{"ETHUSD", "XBTUSD", "XBTUSDT", "XDGUSD"}, names)
+
+	btc := symbols[1]
+	assert.Equal(t, "BTC", btc.BaseAsset)
+	assert.Equal(t, "USD", btc.QuoteAsset)
+	assert.Equal(t, "online", btc.Status)
+	assert.Equal(t, "0.1", btc.Filters.TickSize.String())
+	assert.Equal(t, "0.0001", btc.Filters.MinQty.String())
+	assert.Equal(t, "0.00000001", btc.Filters.StepSize.String())
+	assert.Equal(t, "0.5", btc.Filters.MinNotional.String())
+
+	assert.Equal(t, "USDT", symbols[2].QuoteAsset)
+	assert.Equal(t, "DOGE", symbols[3].BaseAsset)
+	assert.Equal(t, "cancel_only", symbols[3].Status)
+}
+
+func TestService_GetTickers(t *testing.T) {
+	type want struct {
+		tickers []connector.Ticker
+		err     string
+	}
+
+	tests := map[string]struct {
+		symbols   []string
+		status    int
+		overrides map[string]string
+		want      want
+	}{
+		"Matches the tickers by alternative name": {
+			symbols: []string{"XBTUSD", "ethusd"},
+			status:  http.StatusOK,
+			want: want{tickers: []connector.Ticker{
+				{Symbol: "XBTUSD", Price: decimal.RequireFromString("65000")},
+				{Symbol: "ethusd", Price: decimal.RequireFromString("3200")},
+			}},
+		},
+		"Matches the tickers by pair name": {
+			symbols: []string{"XXBTZUSD"},
+			status:  http.StatusOK,
+			want:    want{tickers: []connector.Ticker{{Symbol: "XXBTZUSD", Price: decimal.RequireFromString("65000")}}},
+		},
+		"No symbols": {
+			symbols: []string{},
+			status:  http.StatusOK,
+			want:    want{tickers: []connector.Ticker{}},
+		},
+		"Missing ticker": {
+			symbols: []string{"XBTUSDT"},
+			status:  http.StatusOK,
+			want:    want{err: "no ticker returned for XBTUSDT"},
+		},
+		"Unknown pair": {
+			symbols:   []string{"FOOBAR"},
+			status:    http.StatusBadRequest,
+			overrides: map[string]string{"/0/public/AssetPairs": "unknown_pair.json"},
+			want:      want{err: "error getting pairs of FOOBAR: kraken api error: EQuery:Unknown asset pair"},
+		},
+		"Unexpected status": {
+			symbols:   []string{"XBTUSD"},
+			status:    http.StatusBadGateway,
+			overrides: map[string]string{"/0/public/AssetPairs": "bad_gateway.html"},
+			want:      want{err: "error getting pairs of XBTUSD: unexpected status 502: <html>bad gateway</html>"},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newServer(t, tt.status, tt.overrides)
+
+			tickers, err := newService(server.URL, config.Kraken{}).GetTickers(context.Background(), tt.symbols)
+			if tt.want.err != "" {
+				assert.EqualError(t, err, tt.want.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, tickers, len(tt.want.tickers))
+
+			for i, tk := range tt.want.tickers {
+				assert.Equal(t, tk.Symbol, tickers[i].Symbol)
+				assert.True(t, tk.Price.Equal(tickers[i].Price), "price of %s: %s", tk.Symbol, tickers[i].Price)
+			}
+		})
+	}
+}
+
+func TestService_GetOrderBook(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t, http.StatusOK, nil)
+
+	ob, err := newService(server.URL, config.Kraken{}).GetOrderBook(context.Background(), "XBTUSD", 2)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "XBTUSD", ob.Symbol)
+	assert.Equal(t, []string{"64999.9@2"}, levels(ob.Bids))
+	assert.Equal(t, []string{"65000.1@1.25", "65000.2@0.5"}, levels(ob.Asks))
+}
+
+func TestService_GetTrades(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t, http.StatusOK, nil)
+
+	trades, err := newService(server.URL, config.Kraken{}).GetTrades(context.Background(), "XBTUSD", 2)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []connector.Trade{
+		{
+			ID: 70001, Symbol: "XBTUSD", Price: decimal.RequireFromString("65000.00000"), Quantity: decimal.RequireFromString("0.00100000"),
+			Time: time.Date(2023, 11, 14, 22, 13, 20, 123400000, time.UTC),
+		},
+		{
+			ID: 70002, Symbol: "XBTUSD", Price: decimal.RequireFromString("64999.90000"), Quantity: decimal.RequireFromString("0.25000000"),
+			Time: time.Date(2023, 11, 14, 22, 13, 21, 500000000, time.UTC), IsBuyerMaker: true,
+		},
+	}, trades)
+}
+
+func TestService_Name(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, kraken.Name, newService("", config.Kraken{}).Name())
+}
+
+func TestNewExchange(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t, http.StatusOK, nil)
+
+	cfg := &config.Config{Connector: config.Connector{Kraken: config.Kraken{BaseURL: server.URL}}}
+
+	e, err := kraken.NewExchange(cfg)
+	assert.NoError(t, err)
+
+	_, err = e.GetSymbols(context.Background())
+	assert.NoError(t, err)
+
+	var apiErr *kraken.APIError
+
+	_, err = e.GetBalances(context.Background())
+	assert.False(t, errors.As(err, &apiErr), "credentials are validated before the request is sent")
+}
+
+func levels(l []connector.PriceLevel) []string {
+	res := make([]string, 0, len(l))
+	for _, p := range l {
+		res = append(res, p.Price.String()+"@"+p.Quantity.String())
+	}
+
+	return res
+}
@@ -0,0 +1,73 @@
+package kraken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/twk/trader-b/internal/client"
+)
+
+var _ client.Signer = (*signer)(nil)
+
+// signer signs the private requests of Kraken. It adds a nonce to the form and sets the API-Sign header to the base64
+// encoded HMAC-SHA512 of the URI path followed by the SHA-256 of the nonce and the form, keyed by the base64 decoded secret key.
+type signer struct {
+	apiKey    string
+	secretKey string
+	nonce     func() int64
+}
+
+// Sign signs the request.
+func (s *signer) Sign(r *client.Request) error {
+	key, err := base64.StdEncoding.DecodeString(s.secretKey)
+	if err != nil {
+		return fmt.Errorf("error decoding kraken secret key: %w", err)
+	}
+
+	nonce := strconv.FormatInt(s.nonce(), 10)
+
+	body := url.Values{"nonce": {nonce}}.Encode()
+	if r.Body != "" {
+		body += "&" + r.Body
+	}
+
+	digest := sha256.Sum256([]byte(nonce + body))
+
+	mac := hmac.New(sha512.New, key)
+	mac.Write([]byte(r.URL.Path))
+	mac.Write(digest[:])
+
+	r.Body = body
+	r.Header.Set("API-Key", s.apiKey)
+	r.Header.Set("API-Sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+
+	return nil
+}
+
+// nonceSource returns the nonces of the private requests. Kraken rejects a nonce that is not greater than the previous one
+// of the API key, so the nonces are unix milliseconds that are increased when two requests are made within the same millisecond.
+type nonceSource struct {
+	last atomic.Int64
+}
+
+func (n *nonceSource) next() int64 {
+	for {
+		last := n.last.Load()
+
+		nonce := time.Now().UnixMilli()
+		if nonce <= last {
+			nonce = last + 1
+		}
+
+		if n.last.CompareAndSwap(last, nonce) {
+			return nonce
+		}
+	}
+}
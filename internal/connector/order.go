@@ -0,0 +1,115 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/config"
+)
+
+// Side is the side of an order.
+type Side string
+
+// Sides of an order.
+const (
+	SideBuy  Side = "BUY"
+	SideSell Side = "SELL"
+)
+
+// OrderType is the type of an order.
+type OrderType string
+
+// Types of an order.
+const (
+	OrderTypeLimit  OrderType = "LIMIT"
+	OrderTypeMarket OrderType = "MARKET"
+)
+
+// The statuses of an order, as named by Binance.
+const (
+	OrderStatusNew             = "NEW"
+	OrderStatusPartiallyFilled = "PARTIALLY_FILLED"
+	OrderStatusFilled          = "FILLED"
+	OrderStatusCanceled        = "CANCELED"
+	OrderStatusExpired         = "EXPIRED"
+	OrderStatusRejected        = "REJECTED"
+)
+
+// Trader is the interface implemented by connectors able to trade.
+type Trader interface {
+	// PlaceOrder places a new order.
+	PlaceOrder(ctx context.Context, req OrderRequest) (*Order, error)
+	// CancelOrder cancels an open order.
+	CancelOrder(ctx context.Context, symbol string, orderID int64) (*Order, error)
+	// GetOrder gets an order.
+	GetOrder(ctx context.Context, symbol string, orderID int64) (*Order, error)
+	// ListOpenOrders lists the open orders of the symbol, or of every symbol when it is empty.
+	ListOpenOrders(ctx context.Context, symbol string) ([]Order, error)
+}
+
+// OrderRequest represents a new order to be placed. The price is ignored for market orders.
+type OrderRequest struct {
+	Symbol        string
+	Side          Side
+	Type          OrderType
+	Quantity      decimal.Decimal
+	Price         decimal.Decimal
+	TimeInForce   string
+	ClientOrderID string
+}
+
+// Order represents an order placed on an exchange.
+type Order struct {
+	ID               int64
+	ClientOrderID    string
+	Symbol           string
+	Side             Side
+	Type             OrderType
+	Status           string
+	Price            decimal.Decimal
+	Quantity         decimal.Decimal
+	ExecutedQuantity decimal.Decimal
+	Time             time.Time
+}
+
+// NewOrderRequest creates the order request of the order configuration. The side and type are case insensitive,
+// and the price is only parsed for limit orders.
+func NewOrderRequest(o config.Order) (OrderRequest, error) {
+	req := OrderRequest{
+		Symbol:      strings.ToUpper(o.Symbol),
+		Side:        Side(strings.ToUpper(o.Side)),
+		Type:        OrderType(strings.ToUpper(o.Type)),
+		TimeInForce: strings.ToUpper(o.TimeInForce),
+	}
+
+	if req.Side != SideBuy && req.Side != SideSell {
+		return req, fmt.Errorf("invalid order side %q", o.Side)
+	}
+
+	quantity, err := decimal.NewFromString(o.Quantity)
+	if err != nil {
+		return req, fmt.Errorf("invalid order quantity %q: %w", o.Quantity, err)
+	}
+
+	req.Quantity = quantity
+
+	switch req.Type {
+	case OrderTypeMarket:
+		return req, nil
+	case OrderTypeLimit:
+		price, err := decimal.NewFromString(o.Price)
+		if err != nil {
+			return req, fmt.Errorf("invalid order price %q: %w", o.Price, err)
+		}
+
+		req.Price = price
+
+		return req, nil
+	default:
+		return req, fmt.Errorf("invalid order type %q", o.Type)
+	}
+}
@@ -0,0 +1,72 @@
+package connector_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+)
+
+func TestNewOrderRequest(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		req connector.OrderRequest
+		err string
+	}
+
+	tests := map[string]struct {
+		order config.Order
+		want  want
+	}{
+		"Limit order": {
+			order: config.Order{Symbol: "btcusdt", Side: "buy", Type: "limit", Quantity: "0.5", Price: "64000", TimeInForce: "ioc"},
+			want: want{req: connector.OrderRequest{
+				Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit,
+				Quantity: decimal.RequireFromString("0.5"), Price: decimal.RequireFromString("64000"), TimeInForce: "IOC",
+			}},
+		},
+		"Market order ignores the price": {
+			order: config.Order{Symbol: "BTCUSDT", Side: "SELL", Type: "MARKET", Quantity: "1", Price: "not a price"},
+			want: want{req: connector.OrderRequest{
+				Symbol: "BTCUSDT", Side: connector.SideSell, Type: connector.OrderTypeMarket, Quantity: decimal.RequireFromString("1"),
+			}},
+		},
+		"Invalid side": {
+			order: config.Order{Side: "hold", Type: "MARKET", Quantity: "1"},
+			want:  want{err: `invalid order side "hold"`},
+		},
+		"Invalid quantity": {
+			order: config.Order{Side: "BUY", Type: "MARKET", Quantity: ""},
+			want:  want{err: `invalid order quantity "": can't convert  to decimal`},
+		},
+		"Invalid price": {
+			order: config.Order{Side: "BUY", Type: "LIMIT", Quantity: "1", Price: "x"},
+			want:  want{err: `invalid order price "x": can't convert x to decimal`},
+		},
+		"Invalid type": {
+			order: config.Order{Side: "BUY", Type: "STOP", Quantity: "1"},
+			want:  want{err: `invalid order type "STOP"`},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := connector.NewOrderRequest(tt.order)
+			if tt.want.err != "" {
+				assert.EqualError(t, err, tt.want.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.req, req)
+		})
+	}
+}
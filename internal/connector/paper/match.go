@@ -0,0 +1,86 @@
+package paper
+
+import (
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// levels returns the levels of the book an order of the side is matched against, the asks for a buy and the bids for a sell.
+func levels(book *connector.OrderBook, side connector.Side) []connector.PriceLevel {
+	if side == connector.SideBuy {
+		return book.Asks
+	}
+
+	return book.Bids
+}
+
+// crosses reports whether an order of the side at the limit price matches a level at the price. A zero limit matches any price.
+func crosses(side connector.Side, price, limit decimal.Decimal) bool {
+	if limit.IsZero() {
+		return true
+	}
+
+	if side == connector.SideBuy {
+		return price.LessThanOrEqual(limit)
+	}
+
+	return price.GreaterThanOrEqual(limit)
+}
+
+// better reports whether the price a is better than the price b for an order of the side, higher for a buy and lower for a sell.
+func better(side connector.Side, a, b decimal.Decimal) bool {
+	if side == connector.SideBuy {
+		return a.GreaterThan(b)
+	}
+
+	return a.LessThan(b)
+}
+
+// take matches the quantity of an order of the side at the limit price against the levels, walking them from the best price
+// while they cross the limit. It returns the matched quantity of every level and the levels left once they are taken.
+func take(side connector.Side, levels []connector.PriceLevel, qty, limit decimal.Decimal) ([]connector.PriceLevel, []connector.PriceLevel) {
+	var fills []connector.PriceLevel
+
+	for i, l := range levels {
+		if !qty.IsPositive() || !crosses(side, l.Price, limit) {
+			return fills, levels[i:]
+		}
+
+		if !l.Quantity.IsPositive() {
+			continue
+		}
+
+		matched := decimal.Min(qty, l.Quantity)
+		fills = append(fills, connector.PriceLevel{Price: l.Price, Quantity: matched})
+		qty = qty.Sub(matched)
+
+		if matched.LessThan(l.Quantity) {
+			rest := append([]connector.PriceLevel{{Price: l.Price, Quantity: l.Quantity.Sub(matched)}}, levels[i+1:]...)
+
+			return fills, rest
+		}
+	}
+
+	return fills, nil
+}
+
+// quantity returns the total quantity of the levels.
+func quantity(levels []connector.PriceLevel) decimal.Decimal {
+	total := decimal.Zero
+	for _, l := range levels {
+		total = total.Add(l.Quantity)
+	}
+
+	return total
+}
+
+// notional returns the total amount of the levels in the quote asset.
+func notional(levels []connector.PriceLevel) decimal.Decimal {
+	total := decimal.Zero
+	for _, l := range levels {
+		total = total.Add(l.Price.Mul(l.Quantity))
+	}
+
+	return total
+}
@@ -0,0 +1,758 @@
+// Package paper provides a paper trading exchange, which simulates the orders of the connector.Trader interface with virtual balances.
+// The orders are matched against the order books of a market, the live order books of another exchange or recorded ones,
+// and the balances, orders and fills are persisted between runs.
+package paper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/binance"
+)
+
+// Name is the name of the paper exchange. It matches the key of the paper connector configuration.
+const Name = "paper"
+
+// The time in force of the limit orders. GTC orders rest in the book until they are filled or cancelled, IOC orders expire
+// once they are matched against the book, and FOK orders expire without any fill unless they are filled entirely.
+const (
+	TimeInForceGTC = "GTC"
+	TimeInForceIOC = "IOC"
+	TimeInForceFOK = "FOK"
+)
+
+// bookDepth is the depth of the order books the orders are matched against.
+const bookDepth = 100
+
+var (
+	// ErrInvalidOrder is returned when an order request is not valid.
+	ErrInvalidOrder = errors.New("invalid order")
+	// ErrInsufficientBalance is returned when the free balance does not cover the amount an order spends and locks.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrUnknownOrder is returned when no order of the symbol has the ID.
+	ErrUnknownOrder = errors.New("unknown order")
+	// ErrOrderNotOpen is returned when cancelling an order that is filled, cancelled or expired.
+	ErrOrderNotOpen = errors.New("order is not open")
+	// ErrUnknownSymbol is returned when the market does not list the symbol of an order.
+	ErrUnknownSymbol = errors.New("unknown symbol")
+)
+
+var (
+	_ connector.Exchange = (*Exchange)(nil)
+	_ connector.Trader   = (*Exchange)(nil)
+)
+
+// Market provides the market data of the paper exchange. Every connector.Exchange is a market.
+// The levels of the order books are sorted from the best price.
+type Market interface {
+	GetTickers(ctx context.Context, symbols []string) ([]connector.Ticker, error)
+	GetOrderBook(ctx context.Context, symbol string, limit int) (*connector.OrderBook, error)
+	GetTrades(ctx context.Context, symbol string, limit int) ([]connector.Trade, error)
+	GetSymbols(ctx context.Context) ([]connector.Symbol, error)
+}
+
+// Fees are the fractions of the traded amounts charged to the orders filled against the book (Taker)
+// and to the resting orders filled once the book crosses their price (Maker).
+type Fees struct {
+	Maker decimal.Decimal
+	Taker decimal.Decimal
+}
+
+// Exchange is the paper trading exchange. An order takes the liquidity of the order book up to its limit price when it is placed,
+// and the remaining quantity of a GTC limit order rests with its funds locked until the book crosses its price, which is checked
+// whenever the orders or the balances are read. An order is matched at most once against every update of the book, so the liquidity
+// it took is not taken again until the book is updated, and an order resting on a recorded book is never filled. The books of
+// the markets not numbering their updates, like Kraken, are considered updated by every trade.
+// The fees are charged in the received asset, like Binance does without BNB.
+type Exchange struct {
+	market   Market
+	fees     Fees
+	path     string
+	balances map[string]decimal.Decimal
+	now      func() time.Time
+	mu       sync.Mutex
+	state    *state
+	symbols  map[string]connector.Symbol
+}
+
+// Option configures the paper exchange.
+type Option func(*Exchange)
+
+// WithFees sets the fees of the orders. No fee is charged by default.
+func WithFees(f Fees) Option {
+	return func(e *Exchange) {
+		e.fees = f
+	}
+}
+
+// WithStatePath persists the state of the exchange in the file, so it is kept between runs. The state is only kept in memory by default.
+func WithStatePath(path string) Option {
+	return func(e *Exchange) {
+		e.path = path
+	}
+}
+
+// WithBalances sets the initial balances, used when no state was persisted yet.
+func WithBalances(balances map[string]decimal.Decimal) Option {
+	return func(e *Exchange) {
+		e.balances = balances
+	}
+}
+
+// WithClock sets the clock of the exchange.
+func WithClock(now func() time.Time) Option {
+	return func(e *Exchange) {
+		e.now = now
+	}
+}
+
+// New creates a paper exchange matching the orders against the market, and loads its persisted state.
+func New(market Market, opts ...Option) (*Exchange, error) {
+	e := &Exchange{market: market, now: time.Now}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	s, err := loadState(e.path, e.balances)
+	if err != nil {
+		return nil, err
+	}
+
+	e.state = s
+
+	return e, nil
+}
+
+// NewFactory returns the factory of the paper exchange. The live order books are got from the source exchange created by the registry.
+func NewFactory(r *connector.Registry) connector.Factory {
+	return func(cfg *config.Config) (connector.Exchange, error) {
+		p := cfg.Connector.Paper
+
+		market, err := newMarket(cfg, r)
+		if err != nil {
+			return nil, err
+		}
+
+		balances, err := ParseBalances(p.Balances)
+		if err != nil {
+			return nil, err
+		}
+
+		fees, err := parseFees(p)
+		if err != nil {
+			return nil, err
+		}
+
+		return New(market, WithFees(fees), WithStatePath(p.StatePath), WithBalances(balances))
+	}
+}
+
+func newMarket(cfg *config.Config, r *connector.Registry) (Market, error) {
+	p := cfg.Connector.Paper
+	if p.BooksPath != "" {
+		return LoadBooks(p.BooksPath)
+	}
+
+	source := p.Source
+	if source == "" {
+		source = binance.Name
+	}
+
+	if source == Name {
+		return nil, fmt.Errorf("invalid paper source %q: the paper exchange cannot be its own source", source)
+	}
+
+	e, err := r.Get(cfg, source)
+	if err != nil {
+		return nil, fmt.Errorf("error creating paper source: %w", err)
+	}
+
+	return e, nil
+}
+
+func parseFees(p config.Paper) (Fees, error) {
+	var (
+		fees Fees
+		err  error
+	)
+
+	if fees.Maker, err = parseFee(p.MakerFee); err != nil {
+		return Fees{}, fmt.Errorf("invalid maker fee: %w", err)
+	}
+
+	if fees.Taker, err = parseFee(p.TakerFee); err != nil {
+		return Fees{}, fmt.Errorf("invalid taker fee: %w", err)
+	}
+
+	return fees, nil
+}
+
+func parseFee(s string) (decimal.Decimal, error) {
+	if s == "" {
+		return decimal.Zero, nil
+	}
+
+	fee, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("error parsing %q: %w", s, err)
+	}
+
+	if fee.IsNegative() || fee.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return decimal.Zero, fmt.Errorf("%s is not a fraction in [0, 1)", s)
+	}
+
+	return fee, nil
+}
+
+// ParseBalances parses balances given as comma separated ASSET=AMOUNT pairs, e.g. USDT=10000,BTC=0.5.
+func ParseBalances(s string) (map[string]decimal.Decimal, error) {
+	balances := make(map[string]decimal.Decimal)
+
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		asset, amount, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(asset) == "" {
+			return nil, fmt.Errorf("invalid balance %q: expected ASSET=AMOUNT", pair)
+		}
+
+		d, err := decimal.NewFromString(strings.TrimSpace(amount))
+		if err != nil {
+			return nil, fmt.Errorf("invalid balance %q: %w", pair, err)
+		}
+
+		if d.IsNegative() {
+			return nil, fmt.Errorf("invalid balance %q: the amount is negative", pair)
+		}
+
+		balances[strings.ToUpper(strings.TrimSpace(asset))] = d
+	}
+
+	return balances, nil
+}
+
+// Name returns the name of the exchange.
+func (e *Exchange) Name() string {
+	return Name
+}
+
+// GetBalances gets the virtual balances, once the open orders crossed by the order books are filled.
+func (e *Exchange) GetBalances(ctx context.Context) ([]connector.Balance, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.matchAndSave(ctx); err != nil {
+		return nil, err
+	}
+
+	assets := make([]string, 0, len(e.state.Balances))
+	for asset := range e.state.Balances {
+		assets = append(assets, asset)
+	}
+
+	sort.Strings(assets)
+
+	res := make([]connector.Balance, 0, len(assets))
+	for _, asset := range assets {
+		b := e.state.Balances[asset]
+		res = append(res, connector.Balance{Asset: asset, Free: b.Free, Locked: b.Locked})
+	}
+
+	return res, nil
+}
+
+// GetTickers gets the latest price of the given symbols from the market.
+func (e *Exchange) GetTickers(ctx context.Context, symbols []string) ([]connector.Ticker, error) {
+	tickers, err := e.market.GetTickers(ctx, symbols)
+	if err != nil {
+		return nil, fmt.Errorf("error getting paper tickers: %w", err)
+	}
+
+	return tickers, nil
+}
+
+// GetOrderBook gets the order book of the symbol from the market. The resting paper orders are not part of it.
+func (e *Exchange) GetOrderBook(ctx context.Context, symbol string, limit int) (*connector.OrderBook, error) {
+	book, err := e.market.GetOrderBook(ctx, symbol, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting paper order book: %w", err)
+	}
+
+	return book, nil
+}
+
+// GetTrades gets the most recent trades of the symbol from the market.
+func (e *Exchange) GetTrades(ctx context.Context, symbol string, limit int) ([]connector.Trade, error) {
+	trades, err := e.market.GetTrades(ctx, symbol, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting paper trades: %w", err)
+	}
+
+	return trades, nil
+}
+
+// GetSymbols gets the symbols listed on the market.
+func (e *Exchange) GetSymbols(ctx context.Context) ([]connector.Symbol, error) {
+	symbols, err := e.market.GetSymbols(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting paper symbols: %w", err)
+	}
+
+	return symbols, nil
+}
+
+// PlaceOrder places a paper order. It is filled against the order book of the symbol up to its limit price,
+// and the remaining quantity of a GTC limit order rests with its funds locked.
+func (e *Exchange) PlaceOrder(ctx context.Context, req connector.OrderRequest) (*connector.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	o, err := e.newOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	book, update, err := e.book(ctx, o.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("error getting paper order book: %w", err)
+	}
+
+	// The resting orders are matched first against the same book, so they take the liquidity they were waiting for.
+	remaining := e.matchBook(e.openOrders(o.Symbol)[o.Symbol], book, update)
+
+	fills, _ := take(o.Side, remaining[o.Side], o.Quantity, o.Price)
+	if o.TimeInForce == TimeInForceFOK && quantity(fills).LessThan(o.Quantity) {
+		fills = nil
+	}
+
+	if err := e.checkBalance(o, fills); err != nil {
+		return nil, err
+	}
+
+	e.state.NextOrderID++
+	o.ID = e.state.NextOrderID
+	o.BookUpdateID = update
+
+	for _, f := range fills {
+		e.fill(o, f.Price, f.Quantity, false)
+	}
+
+	e.settle(o)
+	e.state.Orders = append(e.state.Orders, o)
+
+	if err := e.state.save(e.path); err != nil {
+		return nil, err
+	}
+
+	res := o.connector()
+
+	return &res, nil
+}
+
+// CancelOrder cancels an open paper order and unlocks the funds of its remaining quantity.
+func (e *Exchange) CancelOrder(ctx context.Context, symbol string, orderID int64) (*connector.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.match(ctx, symbol); err != nil {
+		return nil, err
+	}
+
+	o, err := e.order(symbol, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !o.open() {
+		return nil, fmt.Errorf("%w: order %d is %s", ErrOrderNotOpen, o.ID, o.Status)
+	}
+
+	asset, amount := o.locked()
+	b := e.balance(asset)
+	b.Locked = b.Locked.Sub(amount)
+	b.Free = b.Free.Add(amount)
+	o.Status = connector.OrderStatusCanceled
+
+	if err := e.state.save(e.path); err != nil {
+		return nil, err
+	}
+
+	res := o.connector()
+
+	return &res, nil
+}
+
+// GetOrder gets a paper order, once it is matched against the order book when it is open.
+func (e *Exchange) GetOrder(ctx context.Context, symbol string, orderID int64) (*connector.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.matchAndSave(ctx, symbol); err != nil {
+		return nil, err
+	}
+
+	o, err := e.order(symbol, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := o.connector()
+
+	return &res, nil
+}
+
+// ListOpenOrders lists the open paper orders of the symbol, or of every symbol when it is empty,
+// once they are matched against the order books.
+func (e *Exchange) ListOpenOrders(ctx context.Context, symbol string) ([]connector.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var symbols []string
+	if symbol != "" {
+		symbols = append(symbols, symbol)
+	}
+
+	if err := e.matchAndSave(ctx, symbols...); err != nil {
+		return nil, err
+	}
+
+	res := make([]connector.Order, 0)
+
+	for _, o := range e.state.Orders {
+		if o.open() && (symbol == "" || o.Symbol == symbol) {
+			res = append(res, o.connector())
+		}
+	}
+
+	return res, nil
+}
+
+// Match fills the open orders of the symbols, or of every symbol when none is given, whose limit price is crossed by the order books.
+func (e *Exchange) Match(ctx context.Context, symbols ...string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.matchAndSave(ctx, symbols...)
+}
+
+// Fills returns the fills of the paper orders of the symbol, or of every symbol when it is empty, from the oldest.
+func (e *Exchange) Fills(symbol string) []Fill {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	res := make([]Fill, 0, len(e.state.Fills))
+
+	for _, f := range e.state.Fills {
+		if symbol == "" || f.Symbol == symbol {
+			res = append(res, f)
+		}
+	}
+
+	return res
+}
+
+// newOrder validates the request and creates the order, not yet numbered.
+func (e *Exchange) newOrder(ctx context.Context, req connector.OrderRequest) (*order, error) {
+	if req.Side != connector.SideBuy && req.Side != connector.SideSell {
+		return nil, fmt.Errorf("%w: invalid side %q", ErrInvalidOrder, req.Side)
+	}
+
+	if !req.Quantity.IsPositive() {
+		return nil, fmt.Errorf("%w: the quantity must be positive", ErrInvalidOrder)
+	}
+
+	o := &order{
+		ClientOrderID: req.ClientOrderID,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		Quantity:      req.Quantity,
+		Status:        connector.OrderStatusNew,
+		Time:          e.now(),
+	}
+
+	switch req.Type {
+	case connector.OrderTypeMarket:
+	case connector.OrderTypeLimit:
+		if !req.Price.IsPositive() {
+			return nil, fmt.Errorf("%w: the price of a limit order must be positive", ErrInvalidOrder)
+		}
+
+		o.Price = req.Price
+
+		o.TimeInForce = req.TimeInForce
+		if o.TimeInForce == "" {
+			o.TimeInForce = TimeInForceGTC
+		}
+
+		if o.TimeInForce != TimeInForceGTC && o.TimeInForce != TimeInForceIOC && o.TimeInForce != TimeInForceFOK {
+			return nil, fmt.Errorf("%w: invalid time in force %q", ErrInvalidOrder, req.TimeInForce)
+		}
+	default:
+		return nil, fmt.Errorf("%w: invalid type %q", ErrInvalidOrder, req.Type)
+	}
+
+	s, err := e.symbol(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	o.BaseAsset, o.QuoteAsset = s.BaseAsset, s.QuoteAsset
+
+	return o, nil
+}
+
+// symbol returns the symbol listed on the market under the name. The symbols are got once.
+func (e *Exchange) symbol(ctx context.Context, name string) (connector.Symbol, error) {
+	if e.symbols == nil {
+		symbols, err := e.market.GetSymbols(ctx)
+		if err != nil {
+			return connector.Symbol{}, fmt.Errorf("error getting paper symbols: %w", err)
+		}
+
+		e.symbols = make(map[string]connector.Symbol, len(symbols))
+		for _, s := range symbols {
+			e.symbols[s.Name] = s
+		}
+	}
+
+	s, ok := e.symbols[name]
+	if !ok {
+		return connector.Symbol{}, fmt.Errorf("%w: %s", ErrUnknownSymbol, name)
+	}
+
+	return s, nil
+}
+
+// order returns the order of the symbol with the ID.
+func (e *Exchange) order(symbol string, orderID int64) (*order, error) {
+	for _, o := range e.state.Orders {
+		if o.ID == orderID && o.Symbol == symbol {
+			return o, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s %d", ErrUnknownOrder, symbol, orderID)
+}
+
+// balance returns the balance of the asset, added when it is missing.
+func (e *Exchange) balance(asset string) *balance {
+	b, ok := e.state.Balances[asset]
+	if !ok {
+		b = &balance{}
+		e.state.Balances[asset] = b
+	}
+
+	return b
+}
+
+// checkBalance returns an error when the free balance does not cover the amount the order spends on the fills
+// and locks for its remaining quantity. Like on Binance, a sell requires its whole quantity even when it cannot be filled.
+func (e *Exchange) checkBalance(o *order, fills []connector.PriceLevel) error {
+	var (
+		asset    string
+		required decimal.Decimal
+	)
+
+	remaining := decimal.Zero
+	if o.rests() {
+		remaining = o.Quantity.Sub(quantity(fills))
+	}
+
+	switch o.Side {
+	case connector.SideBuy:
+		asset, required = o.QuoteAsset, notional(fills).Add(o.Price.Mul(remaining))
+	case connector.SideSell:
+		asset, required = o.BaseAsset, o.Quantity
+	}
+
+	if free := e.balance(asset).Free; free.LessThan(required) {
+		return fmt.Errorf("%w: the order requires %s %s, %s is free", ErrInsufficientBalance, required, asset, free)
+	}
+
+	return nil
+}
+
+// fill executes the quantity of the order at the price. A maker fill is paid with the funds locked by the order.
+func (e *Exchange) fill(o *order, price, qty decimal.Decimal, maker bool) {
+	rate := e.fees.Taker
+	if maker {
+		rate = e.fees.Maker
+	}
+
+	base, quote := e.balance(o.BaseAsset), e.balance(o.QuoteAsset)
+	amount := price.Mul(qty)
+	f := Fill{OrderID: o.ID, Symbol: o.Symbol, Side: o.Side, Price: price, Quantity: qty, Maker: maker, Time: e.now()}
+
+	switch o.Side {
+	case connector.SideBuy:
+		if maker {
+			quote.Locked = quote.Locked.Sub(amount)
+		} else {
+			quote.Free = quote.Free.Sub(amount)
+		}
+
+		f.Fee, f.FeeAsset = qty.Mul(rate), o.BaseAsset
+		base.Free = base.Free.Add(qty.Sub(f.Fee))
+	case connector.SideSell:
+		if maker {
+			base.Locked = base.Locked.Sub(qty)
+		} else {
+			base.Free = base.Free.Sub(qty)
+		}
+
+		f.Fee, f.FeeAsset = amount.Mul(rate), o.QuoteAsset
+		quote.Free = quote.Free.Add(amount.Sub(f.Fee))
+	}
+
+	o.ExecutedQuantity = o.ExecutedQuantity.Add(qty)
+	e.state.Fills = append(e.state.Fills, f)
+}
+
+// settle sets the status of an order once it is matched against the book when it is placed,
+// and locks the funds of the remaining quantity when it rests.
+func (e *Exchange) settle(o *order) {
+	switch {
+	case o.ExecutedQuantity.Equal(o.Quantity):
+		o.Status = connector.OrderStatusFilled
+	case !o.rests():
+		o.Status = connector.OrderStatusExpired
+	default:
+		if o.ExecutedQuantity.IsPositive() {
+			o.Status = connector.OrderStatusPartiallyFilled
+		}
+
+		asset, amount := o.locked()
+		b := e.balance(asset)
+		b.Free = b.Free.Sub(amount)
+		b.Locked = b.Locked.Add(amount)
+	}
+}
+
+// matchAndSave matches the open orders of the symbols and persists the state.
+func (e *Exchange) matchAndSave(ctx context.Context, symbols ...string) error {
+	if err := e.match(ctx, symbols...); err != nil {
+		return err
+	}
+
+	return e.state.save(e.path)
+}
+
+// match fills the open orders of the symbols, or of every symbol when none is given, whose limit price is crossed by the order books.
+func (e *Exchange) match(ctx context.Context, symbols ...string) error {
+	open := e.openOrders(symbols...)
+
+	names := make([]string, 0, len(open))
+	for name := range open {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		book, update, err := e.book(ctx, name)
+		if err != nil {
+			return fmt.Errorf("error matching open orders: %w", err)
+		}
+
+		e.matchBook(open[name], book, update)
+	}
+
+	return nil
+}
+
+// openOrders returns the open orders of the symbols, or of every symbol when none is given, keyed by symbol.
+func (e *Exchange) openOrders(symbols ...string) map[string][]*order {
+	open := make(map[string][]*order)
+
+	for _, o := range e.state.Orders {
+		if o.open() && (len(symbols) == 0 || slices.Contains(symbols, o.Symbol)) {
+			open[o.Symbol] = append(open[o.Symbol], o)
+		}
+	}
+
+	return open
+}
+
+// book gets the order book of the symbol and its update, which identifies the liquidity of the book. The markets not numbering
+// the updates of their books, like Kraken, are considered updated by every trade, so the update is the time of the last trade
+// in nanoseconds, and zero when there is no trade.
+func (e *Exchange) book(ctx context.Context, symbol string) (*connector.OrderBook, uint64, error) {
+	book, err := e.market.GetOrderBook(ctx, symbol, bookDepth)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if book.LastUpdateID != 0 {
+		return book, book.LastUpdateID, nil
+	}
+
+	trades, err := e.market.GetTrades(ctx, symbol, 1)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var update uint64
+
+	for _, t := range trades {
+		update = max(update, uint64(t.Time.UnixNano()))
+	}
+
+	return book, update, nil
+}
+
+// matchBook fills the orders of a symbol whose limit price is crossed by its book, unless they were already matched against its update,
+// and returns the levels of the book left on each side. The orders of a side take the liquidity of the book in price priority,
+// then in time priority, and are filled at their limit price.
+func (e *Exchange) matchBook(orders []*order, book *connector.OrderBook, update uint64) map[connector.Side][]connector.PriceLevel {
+	sort.SliceStable(orders, func(i, j int) bool {
+		if orders[i].Side != orders[j].Side {
+			return orders[i].Side == connector.SideBuy
+		}
+
+		return better(orders[i].Side, orders[i].Price, orders[j].Price)
+	})
+
+	remaining := map[connector.Side][]connector.PriceLevel{
+		connector.SideBuy:  levels(book, connector.SideBuy),
+		connector.SideSell: levels(book, connector.SideSell),
+	}
+
+	for _, o := range orders {
+		if o.BookUpdateID == update {
+			continue
+		}
+
+		o.BookUpdateID = update
+
+		var fills []connector.PriceLevel
+
+		fills, remaining[o.Side] = take(o.Side, remaining[o.Side], o.Quantity.Sub(o.ExecutedQuantity), o.Price)
+
+		for _, f := range fills {
+			e.fill(o, o.Price, f.Quantity, true)
+		}
+
+		if o.ExecutedQuantity.Equal(o.Quantity) {
+			o.Status = connector.OrderStatusFilled
+		} else if o.ExecutedQuantity.IsPositive() {
+			o.Status = connector.OrderStatusPartiallyFilled
+		}
+	}
+
+	return remaining
+}
@@ -0,0 +1,512 @@
+package paper_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/connector/paper"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func levels(pairs ...string) []connector.PriceLevel {
+	res := make([]connector.PriceLevel, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		res = append(res, connector.PriceLevel{Price: d(pairs[i]), Quantity: d(pairs[i+1])})
+	}
+
+	return res
+}
+
+// fakeMarket serves the book of BTCUSDT, which the tests move to fill the resting orders.
+type fakeMarket struct {
+	book   *connector.OrderBook
+	trades []connector.Trade
+	books  int
+	err    error
+}
+
+func newFakeMarket() *fakeMarket {
+	return &fakeMarket{book: &connector.OrderBook{
+		Symbol:       "BTCUSDT",
+		LastUpdateID: 1,
+		Bids:         levels("64000", "0.5", "63900", "1"),
+		Asks:         levels("64010", "0.2", "64100", "1"),
+	}}
+}
+
+func (m *fakeMarket) GetTickers(_ context.Context, _ []string) ([]connector.Ticker, error) {
+	return []connector.Ticker{{Symbol: "BTCUSDT", Price: d("64005")}}, m.err
+}
+
+func (m *fakeMarket) GetOrderBook(_ context.Context, _ string, _ int) (*connector.OrderBook, error) {
+	m.books++
+
+	return m.book, m.err
+}
+
+func (m *fakeMarket) GetTrades(_ context.Context, _ string, _ int) ([]connector.Trade, error) {
+	return m.trades, m.err
+}
+
+func (m *fakeMarket) GetSymbols(_ context.Context) ([]connector.Symbol, error) {
+	return []connector.Symbol{{Name: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT"}}, m.err
+}
+
+func newExchange(t *testing.T, m paper.Market, opts ...paper.Option) *paper.Exchange {
+	t.Helper()
+
+	opts = append([]paper.Option{
+		paper.WithBalances(map[string]decimal.Decimal{"USDT": d("100000"), "BTC": d("1")}),
+		paper.WithFees(paper.Fees{Maker: d("0.001"), Taker: d("0.002")}),
+		paper.WithClock(func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }),
+	}, opts...)
+
+	e, err := paper.New(m, opts...)
+	assert.NoError(t, err)
+
+	return e
+}
+
+// balances returns the free and locked amounts of the balances keyed by asset.
+func balances(t *testing.T, e *paper.Exchange) map[string][2]string {
+	t.Helper()
+
+	b, err := e.GetBalances(context.Background())
+	assert.NoError(t, err)
+
+	res := make(map[string][2]string, len(b))
+	for _, balance := range b {
+		res[balance.Asset] = [2]string{balance.Free.String(), balance.Locked.String()}
+	}
+
+	return res
+}
+
+func TestExchange_PlaceOrder(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		status   string
+		executed string
+		balances map[string][2]string
+		fills    int
+		err      error
+	}
+
+	tests := map[string]struct {
+		req  connector.OrderRequest
+		want want
+	}{
+		"Market buy walks the asks": {
+			req: connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeMarket, Quantity: d("0.1")},
+			want: want{
+				status:   connector.OrderStatusFilled,
+				executed: "0.1",
+				balances: map[string][2]string{"USDT": {"93599", "0"}, "BTC": {"1.0998", "0"}},
+				fills:    1,
+			},
+		},
+		"Market sell walks the bids and charges the fee in the quote asset": {
+			req: connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideSell, Type: connector.OrderTypeMarket, Quantity: d("0.6")},
+			want: want{
+				status:   connector.OrderStatusFilled,
+				executed: "0.6",
+				balances: map[string][2]string{"USDT": {"138313.22", "0"}, "BTC": {"0.4", "0"}},
+				fills:    2,
+			},
+		},
+		"Limit buy crossing the book rests partially filled": {
+			req: connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Quantity: d("0.3"), Price: d("64050")},
+			want: want{
+				status:   connector.OrderStatusPartiallyFilled,
+				executed: "0.2",
+				balances: map[string][2]string{"USDT": {"80793", "6405"}, "BTC": {"1.1996", "0"}},
+				fills:    1,
+			},
+		},
+		"Limit sell below the book rests": {
+			req: connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideSell, Type: connector.OrderTypeLimit, Quantity: d("0.4"), Price: d("65000")},
+			want: want{
+				status:   connector.OrderStatusNew,
+				executed: "0",
+				balances: map[string][2]string{"USDT": {"100000", "0"}, "BTC": {"0.6", "0.4"}},
+			},
+		},
+		"IOC limit expires the remaining quantity": {
+			req: connector.OrderRequest{
+				Symbol: "BTCUSDT", Side: connector.SideSell, Type: connector.OrderTypeLimit, Quantity: d("0.8"), Price: d("63950"), TimeInForce: paper.TimeInForceIOC,
+			},
+			want: want{
+				status:   connector.OrderStatusExpired,
+				executed: "0.5",
+				balances: map[string][2]string{"USDT": {"131936", "0"}, "BTC": {"0.5", "0"}},
+				fills:    1,
+			},
+		},
+		"FOK limit expires without any fill": {
+			req: connector.OrderRequest{
+				Symbol: "BTCUSDT", Side: connector.SideSell, Type: connector.OrderTypeLimit, Quantity: d("0.8"), Price: d("63950"), TimeInForce: paper.TimeInForceFOK,
+			},
+			want: want{
+				status:   connector.OrderStatusExpired,
+				executed: "0",
+				balances: map[string][2]string{"USDT": {"100000", "0"}, "BTC": {"1", "0"}},
+			},
+		},
+		"Insufficient quote balance": {
+			req:  connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Quantity: d("2"), Price: d("60000")},
+			want: want{err: paper.ErrInsufficientBalance},
+		},
+		"Insufficient base balance": {
+			req:  connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideSell, Type: connector.OrderTypeMarket, Quantity: d("2")},
+			want: want{err: paper.ErrInsufficientBalance},
+		},
+		"Zero quantity": {
+			req:  connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeMarket},
+			want: want{err: paper.ErrInvalidOrder},
+		},
+		"Limit without price": {
+			req:  connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Quantity: d("1")},
+			want: want{err: paper.ErrInvalidOrder},
+		},
+		"Invalid time in force": {
+			req:  connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Quantity: d("1"), Price: d("1"), TimeInForce: "GTD"},
+			want: want{err: paper.ErrInvalidOrder},
+		},
+		"Unknown symbol": {
+			req:  connector.OrderRequest{Symbol: "ETHUSDT", Side: connector.SideBuy, Type: connector.OrderTypeMarket, Quantity: d("1")},
+			want: want{err: paper.ErrUnknownSymbol},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			e := newExchange(t, newFakeMarket())
+
+			o, err := e.PlaceOrder(context.Background(), tt.req)
+			if tt.want.err != nil {
+				assert.ErrorIs(t, err, tt.want.err)
+				assert.Equal(t, map[string][2]string{"USDT": {"100000", "0"}, "BTC": {"1", "0"}}, balances(t, e), "a rejected order keeps the balances")
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1), o.ID)
+			assert.Equal(t, tt.want.status, o.Status)
+			assert.Equal(t, tt.want.executed, o.ExecutedQuantity.String())
+			assert.Equal(t, tt.want.balances, balances(t, e))
+			assert.Len(t, e.Fills("BTCUSDT"), tt.want.fills)
+		})
+	}
+}
+
+func TestExchange_MatchesRestingOrders(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := newFakeMarket()
+	e := newExchange(t, m)
+
+	o, err := e.PlaceOrder(ctx, connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Quantity: d("0.5"), Price: d("63000")})
+	assert.NoError(t, err)
+	assert.Equal(t, connector.OrderStatusNew, o.Status)
+	assert.Equal(t, map[string][2]string{"USDT": {"68500", "31500"}, "BTC": {"1", "0"}}, balances(t, e))
+
+	m.book = &connector.OrderBook{Symbol: "BTCUSDT", LastUpdateID: 2, Asks: levels("62900", "0.2", "63000", "0.1", "63100", "1")}
+
+	o, err = e.GetOrder(ctx, "BTCUSDT", o.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, connector.OrderStatusPartiallyFilled, o.Status)
+	assert.Equal(t, "0.3", o.ExecutedQuantity.String())
+	assert.Equal(t, map[string][2]string{"USDT": {"68500", "12600"}, "BTC": {"1.2997", "0"}}, balances(t, e))
+
+	for _, f := range e.Fills("BTCUSDT") {
+		assert.True(t, f.Maker)
+		assert.Equal(t, "63000", f.Price.String(), "a resting order is filled at its limit price")
+		assert.Equal(t, "BTC", f.FeeAsset)
+	}
+
+	open, err := e.ListOpenOrders(ctx, "")
+	assert.NoError(t, err)
+	assert.Len(t, open, 1)
+
+	o, err = e.CancelOrder(ctx, "BTCUSDT", o.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, connector.OrderStatusCanceled, o.Status)
+	assert.Equal(t, map[string][2]string{"USDT": {"81100", "0"}, "BTC": {"1.2997", "0"}}, balances(t, e))
+
+	_, err = e.CancelOrder(ctx, "BTCUSDT", o.ID)
+	assert.ErrorIs(t, err, paper.ErrOrderNotOpen)
+
+	_, err = e.CancelOrder(ctx, "BTCUSDT", 42)
+	assert.ErrorIs(t, err, paper.ErrUnknownOrder)
+
+	open, err = e.ListOpenOrders(ctx, "BTCUSDT")
+	assert.NoError(t, err)
+	assert.Empty(t, open)
+}
+
+func TestExchange_MatchesInPricePriority(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := newFakeMarket()
+	e := newExchange(t, m)
+
+	first, err := e.PlaceOrder(ctx, connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideSell, Type: connector.OrderTypeLimit, Quantity: d("0.3"), Price: d("64500")})
+	assert.NoError(t, err)
+
+	second, err := e.PlaceOrder(ctx, connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideSell, Type: connector.OrderTypeLimit, Quantity: d("0.3"), Price: d("64400")})
+	assert.NoError(t, err)
+
+	m.book = &connector.OrderBook{Symbol: "BTCUSDT", LastUpdateID: 2, Bids: levels("64600", "0.4")}
+
+	assert.NoError(t, e.Match(ctx))
+
+	first, err = e.GetOrder(ctx, "BTCUSDT", first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.1", first.ExecutedQuantity.String())
+
+	second, err = e.GetOrder(ctx, "BTCUSDT", second.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, connector.OrderStatusFilled, second.Status)
+
+	m.err = errors.New("market down")
+
+	_, err = e.GetBalances(ctx)
+	assert.EqualError(t, err, "error matching open orders: market down")
+}
+
+func TestExchange_PlaceOrder_MatchesRestingOrdersFirst(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := newFakeMarket()
+	e := newExchange(t, m)
+
+	resting, err := e.PlaceOrder(ctx, connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Quantity: d("0.5"), Price: d("63000")})
+	assert.NoError(t, err)
+
+	m.book = &connector.OrderBook{Symbol: "BTCUSDT", LastUpdateID: 2, Asks: levels("62900", "0.2")}
+	m.books = 0
+
+	o, err := e.PlaceOrder(ctx, connector.OrderRequest{
+		Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Quantity: d("0.2"), Price: d("63000"), TimeInForce: paper.TimeInForceIOC,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, m.books, "the resting orders and the new order are matched against the same book")
+	assert.Equal(t, connector.OrderStatusExpired, o.Status, "the liquidity taken by the resting order is not taken again")
+
+	resting, err = e.GetOrder(ctx, "BTCUSDT", resting.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.2", resting.ExecutedQuantity.String())
+}
+
+func TestExchange_MatchesUnnumberedBooksOnTrades(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := newFakeMarket()
+	m.book.LastUpdateID = 0
+	e := newExchange(t, m)
+
+	o, err := e.PlaceOrder(ctx, connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Quantity: d("0.5"), Price: d("63000")})
+	assert.NoError(t, err)
+
+	m.book = &connector.OrderBook{Symbol: "BTCUSDT", Asks: levels("62900", "0.2")}
+
+	executed := func() string {
+		t.Helper()
+
+		o, err := e.GetOrder(ctx, "BTCUSDT", o.ID)
+		assert.NoError(t, err)
+
+		return o.ExecutedQuantity.String()
+	}
+
+	assert.Equal(t, "0", executed(), "the book is not updated without a trade")
+
+	m.trades = []connector.Trade{{ID: 1, Symbol: "BTCUSDT", Time: time.Date(2024, 3, 1, 12, 0, 1, 0, time.UTC)}}
+
+	assert.Equal(t, "0.2", executed())
+	assert.Equal(t, "0.2", executed(), "the book is not updated until the next trade")
+
+	m.trades = append(m.trades, connector.Trade{ID: 2, Symbol: "BTCUSDT", Time: time.Date(2024, 3, 1, 12, 0, 2, 0, time.UTC)})
+
+	assert.Equal(t, "0.4", executed())
+}
+
+func TestExchange_PersistsState(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "paper.json")
+	m := newFakeMarket()
+	e := newExchange(t, m, paper.WithStatePath(path))
+
+	_, err := e.PlaceOrder(ctx, connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeLimit, Quantity: d("0.5"), Price: d("63000")})
+	assert.NoError(t, err)
+
+	reloaded, err := paper.New(m, paper.WithStatePath(path), paper.WithBalances(map[string]decimal.Decimal{"USDT": d("1")}))
+	assert.NoError(t, err)
+	assert.Equal(t, balances(t, e), balances(t, reloaded), "the initial balances are ignored once the state is persisted")
+
+	open, err := reloaded.ListOpenOrders(ctx, "BTCUSDT")
+	assert.NoError(t, err)
+	assert.Len(t, open, 1)
+
+	o, err := reloaded.PlaceOrder(ctx, connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeMarket, Quantity: d("0.1")})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), o.ID)
+
+	_, err = paper.New(m, paper.WithStatePath(t.TempDir()))
+	assert.ErrorContains(t, err, "error reading paper state")
+}
+
+func TestNewFactory(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		balances map[string][2]string
+		err      string
+	}
+
+	tests := map[string]struct {
+		paper config.Paper
+		want  want
+	}{
+		"Recorded books": {
+			paper: config.Paper{BooksPath: "testdata/books.json", Balances: "USDT=1000", TakerFee: "0.001"},
+			want:  want{balances: map[string][2]string{"USDT": {"680", "0"}, "ETH": {"0.0999", "0"}}},
+		},
+		"Invalid fee": {
+			paper: config.Paper{BooksPath: "testdata/books.json", MakerFee: "1.5"},
+			want:  want{err: "invalid maker fee: 1.5 is not a fraction in [0, 1)"},
+		},
+		"Invalid balances": {
+			paper: config.Paper{BooksPath: "testdata/books.json", Balances: "USDT"},
+			want:  want{err: `invalid balance "USDT": expected ASSET=AMOUNT`},
+		},
+		"Missing books": {
+			paper: config.Paper{BooksPath: "testdata/missing.json"},
+			want:  want{err: "error reading recorded order books: open testdata/missing.json: no such file or directory"},
+		},
+		"Paper source": {
+			paper: config.Paper{Source: paper.Name},
+			want:  want{err: `invalid paper source "paper": the paper exchange cannot be its own source`},
+		},
+		"Unknown source": {
+			paper: config.Paper{Source: "unknown"},
+			want:  want{err: "error creating paper source: unknown exchange: unknown"},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			f := paper.NewFactory(connector.NewRegistry())
+
+			e, err := f(&config.Config{Connector: config.Connector{Paper: tt.paper}})
+			if tt.want.err != "" {
+				assert.EqualError(t, err, tt.want.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, paper.Name, e.Name())
+
+			_, err = e.(connector.Trader).PlaceOrder(context.Background(), connector.OrderRequest{
+				Symbol: "ETHUSDT", Side: connector.SideBuy, Type: connector.OrderTypeMarket, Quantity: d("0.1"),
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.balances, balances(t, e.(*paper.Exchange)))
+		})
+	}
+}
+
+func TestLoadBooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	m, err := paper.LoadBooks("testdata/books.json")
+	assert.NoError(t, err)
+
+	tickers, err := m.GetTickers(ctx, nil)
+	assert.NoError(t, err)
+	assert.Len(t, tickers, 2)
+	assert.Equal(t, "64005", tickers[0].Price.String(), "the ticker price is the middle of the best bid and ask")
+	assert.Equal(t, "3200", tickers[1].Price.String(), "the ticker price is the best price of the only side")
+
+	book, err := m.GetOrderBook(ctx, "BTCUSDT", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &connector.OrderBook{Symbol: "BTCUSDT", Bids: levels("64000", "0.5"), Asks: levels("64010", "0.2")}, book)
+
+	_, err = m.GetOrderBook(ctx, "XRPUSDT", 1)
+	assert.ErrorIs(t, err, paper.ErrUnknownSymbol)
+
+	symbols, err := m.GetSymbols(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, connector.Symbol{Name: "ETHUSDT", BaseAsset: "ETH", QuoteAsset: "USDT", Status: "TRADING"}, symbols[1])
+
+	trades, err := m.GetTrades(ctx, "BTCUSDT", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, trades)
+}
+
+func TestParseBalances(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		balances map[string]decimal.Decimal
+		err      string
+	}
+
+	tests := map[string]struct {
+		s    string
+		want want
+	}{
+		"Balances":        {s: "usdt=10000, BTC=0.5", want: want{balances: map[string]decimal.Decimal{"USDT": d("10000"), "BTC": d("0.5")}}},
+		"Empty":           {s: "", want: want{balances: map[string]decimal.Decimal{}}},
+		"Missing amount":  {s: "USDT", want: want{err: `invalid balance "USDT": expected ASSET=AMOUNT`}},
+		"Missing asset":   {s: "=1", want: want{err: `invalid balance "=1": expected ASSET=AMOUNT`}},
+		"Invalid amount":  {s: "USDT=lots", want: want{err: `invalid balance "USDT=lots": can't convert lots to decimal`}},
+		"Negative amount": {s: "USDT=-1", want: want{err: `invalid balance "USDT=-1": the amount is negative`}},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			balances, err := paper.ParseBalances(tt.s)
+			if tt.want.err != "" {
+				assert.EqualError(t, err, tt.want.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.balances, balances)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package paper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// symbolStatusTrading is the status of the recorded symbols.
+const symbolStatusTrading = "TRADING"
+
+// bookSides is the number of sides of a book, whose best prices are averaged by the middle price.
+const bookSides = 2
+
+// recordedBook is a recorded order book. The levels are [price, quantity] pairs sorted from the best price, like in the Binance depth.
+type recordedBook struct {
+	Symbol     string               `json:"symbol"`
+	BaseAsset  string               `json:"base_asset"`
+	QuoteAsset string               `json:"quote_asset"`
+	Bids       [][2]decimal.Decimal `json:"bids"`
+	Asks       [][2]decimal.Decimal `json:"asks"`
+}
+
+// RecordedMarket is a market serving recorded snapshots of the order books, so the paper orders can be matched offline.
+// The ticker price of a symbol is the middle of its best bid and ask, and no trade is recorded.
+type RecordedMarket struct {
+	books   map[string]*connector.OrderBook
+	symbols []connector.Symbol
+}
+
+var _ Market = (*RecordedMarket)(nil)
+
+// LoadBooks loads the recorded order books of the JSON file at the path, an array of books with their symbol, base and quote assets,
+// bids and asks.
+func LoadBooks(path string) (*RecordedMarket, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading recorded order books: %w", err)
+	}
+
+	var books []recordedBook
+	if err := json.Unmarshal(b, &books); err != nil {
+		return nil, fmt.Errorf("error decoding recorded order books %s: %w", path, err)
+	}
+
+	m := &RecordedMarket{books: make(map[string]*connector.OrderBook, len(books))}
+
+	for _, rb := range books {
+		m.books[rb.Symbol] = &connector.OrderBook{Symbol: rb.Symbol, Bids: recordedLevels(rb.Bids), Asks: recordedLevels(rb.Asks)}
+		m.symbols = append(m.symbols, connector.Symbol{
+			Name: rb.Symbol, BaseAsset: rb.BaseAsset, QuoteAsset: rb.QuoteAsset, Status: symbolStatusTrading,
+		})
+	}
+
+	return m, nil
+}
+
+func recordedLevels(levels [][2]decimal.Decimal) []connector.PriceLevel {
+	res := make([]connector.PriceLevel, 0, len(levels))
+	for _, l := range levels {
+		res = append(res, connector.PriceLevel{Price: l[0], Quantity: l[1]})
+	}
+
+	return res
+}
+
+// GetTickers gets the middle price of the books of the given symbols, or of every book when none is given.
+// The symbols without a recorded book or with an empty book are left out.
+func (m *RecordedMarket) GetTickers(_ context.Context, symbols []string) ([]connector.Ticker, error) {
+	if len(symbols) == 0 {
+		for _, s := range m.symbols {
+			symbols = append(symbols, s.Name)
+		}
+	}
+
+	tickers := make([]connector.Ticker, 0, len(symbols))
+
+	for _, symbol := range symbols {
+		book, ok := m.books[symbol]
+		if !ok {
+			continue
+		}
+
+		if price, ok := midPrice(book); ok {
+			tickers = append(tickers, connector.Ticker{Symbol: symbol, Price: price})
+		}
+	}
+
+	return tickers, nil
+}
+
+// GetOrderBook gets the recorded book of the symbol limited to the given depth, the whole book when it is not positive.
+func (m *RecordedMarket) GetOrderBook(_ context.Context, symbol string, limit int) (*connector.OrderBook, error) {
+	book, ok := m.books[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: no recorded order book of %s", ErrUnknownSymbol, symbol)
+	}
+
+	res := *book
+	if limit > 0 {
+		res.Bids = res.Bids[:min(limit, len(res.Bids))]
+		res.Asks = res.Asks[:min(limit, len(res.Asks))]
+	}
+
+	return &res, nil
+}
+
+// GetTrades returns no trade, as no trade is recorded.
+func (m *RecordedMarket) GetTrades(_ context.Context, _ string, _ int) ([]connector.Trade, error) {
+	return []connector.Trade{}, nil
+}
+
+// GetSymbols gets the symbols of the recorded books.
+func (m *RecordedMarket) GetSymbols(_ context.Context) ([]connector.Symbol, error) {
+	return m.symbols, nil
+}
+
+// midPrice returns the middle of the best bid and ask of the book, or the best price of its only side.
+func midPrice(book *connector.OrderBook) (decimal.Decimal, bool) {
+	switch {
+	case len(book.Bids) > 0 && len(book.Asks) > 0:
+		return book.Bids[0].Price.Add(book.Asks[0].Price).Div(decimal.NewFromInt(bookSides)), true
+	case len(book.Bids) > 0:
+		return book.Bids[0].Price, true
+	case len(book.Asks) > 0:
+		return book.Asks[0].Price, true
+	default:
+		return decimal.Zero, false
+	}
+}
@@ -0,0 +1,157 @@
+package paper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// stateFileMode is the mode of the state file, which is only read by its owner.
+const stateFileMode = 0o600
+
+// Fill is an execution of a paper order. The fee is charged in the received asset, the base asset of a buy and the quote asset of a sell.
+type Fill struct {
+	OrderID  int64           `json:"order_id"`
+	Symbol   string          `json:"symbol"`
+	Side     connector.Side  `json:"side"`
+	Price    decimal.Decimal `json:"price"`
+	Quantity decimal.Decimal `json:"quantity"`
+	Fee      decimal.Decimal `json:"fee"`
+	FeeAsset string          `json:"fee_asset"`
+	Maker    bool            `json:"maker"`
+	Time     time.Time       `json:"time"`
+}
+
+// balance is the virtual balance of an asset.
+type balance struct {
+	Free   decimal.Decimal `json:"free"`
+	Locked decimal.Decimal `json:"locked"`
+}
+
+// order is a paper order. The price of a market order is zero. BookUpdateID is the update of the book the order was last matched against,
+// see Exchange.book.
+type order struct {
+	ID               int64               `json:"id"`
+	ClientOrderID    string              `json:"client_order_id,omitempty"`
+	Symbol           string              `json:"symbol"`
+	BaseAsset        string              `json:"base_asset"`
+	QuoteAsset       string              `json:"quote_asset"`
+	Side             connector.Side      `json:"side"`
+	Type             connector.OrderType `json:"type"`
+	TimeInForce      string              `json:"time_in_force,omitempty"`
+	Status           string              `json:"status"`
+	Price            decimal.Decimal     `json:"price"`
+	Quantity         decimal.Decimal     `json:"quantity"`
+	ExecutedQuantity decimal.Decimal     `json:"executed_quantity"`
+	Time             time.Time           `json:"time"`
+	BookUpdateID     uint64              `json:"book_update_id"`
+}
+
+// open reports whether the order rests in the book.
+func (o *order) open() bool {
+	return o.Status == connector.OrderStatusNew || o.Status == connector.OrderStatusPartiallyFilled
+}
+
+// rests reports whether the remaining quantity of the order rests in the book once it is matched when it is placed.
+func (o *order) rests() bool {
+	return o.Type == connector.OrderTypeLimit && o.TimeInForce == TimeInForceGTC
+}
+
+// locked returns the asset and the amount locked by the remaining quantity of the order.
+func (o *order) locked() (string, decimal.Decimal) {
+	remaining := o.Quantity.Sub(o.ExecutedQuantity)
+	if o.Side == connector.SideBuy {
+		return o.QuoteAsset, o.Price.Mul(remaining)
+	}
+
+	return o.BaseAsset, remaining
+}
+
+func (o *order) connector() connector.Order {
+	return connector.Order{
+		ID:               o.ID,
+		ClientOrderID:    o.ClientOrderID,
+		Symbol:           o.Symbol,
+		Side:             o.Side,
+		Type:             o.Type,
+		Status:           o.Status,
+		Price:            o.Price,
+		Quantity:         o.Quantity,
+		ExecutedQuantity: o.ExecutedQuantity,
+		Time:             o.Time,
+	}
+}
+
+// state is the persisted state of the paper exchange.
+type state struct {
+	NextOrderID int64               `json:"next_order_id"`
+	Balances    map[string]*balance `json:"balances"`
+	Orders      []*order            `json:"orders"`
+	Fills       []Fill              `json:"fills"`
+}
+
+func newState(balances map[string]decimal.Decimal) *state {
+	s := &state{Balances: make(map[string]*balance, len(balances))}
+	for asset, amount := range balances {
+		s.Balances[asset] = &balance{Free: amount}
+	}
+
+	return s
+}
+
+// loadState loads the state persisted at the path, or creates a state with the initial balances when the path is empty
+// or the file does not exist yet.
+func loadState(path string, balances map[string]decimal.Decimal) (*state, error) {
+	if path == "" {
+		return newState(balances), nil
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return newState(balances), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading paper state: %w", err)
+	}
+
+	s := newState(nil)
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("error decoding paper state %s: %w", path, err)
+	}
+
+	if s.Balances == nil {
+		s.Balances = make(map[string]*balance)
+	}
+
+	return s, nil
+}
+
+// save persists the state at the path, unless it is empty. The file is replaced at once, so a failed save keeps the previous state.
+func (s *state) save(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding paper state: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, stateFileMode); err != nil {
+		return fmt.Errorf("error writing paper state: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error writing paper state: %w", err)
+	}
+
+	return nil
+}
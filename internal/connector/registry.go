@@ -0,0 +1,93 @@
+package connector
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/twk/trader-b/internal/config"
+)
+
+// ErrUnknownExchange is returned when an exchange is not registered.
+var ErrUnknownExchange = errors.New("unknown exchange")
+
+// Factory creates an exchange from the configuration.
+type Factory func(cfg *config.Config) (Exchange, error)
+
+// Registry holds the exchange factories keyed by exchange name.
+type Registry struct {
+	factories map[string]Factory
+	// explicit holds the names of the exchanges only created when they are enabled explicitly.
+	explicit map[string]bool
+}
+
+// NewRegistry creates a new registry.
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory), explicit: make(map[string]bool)}
+}
+
+// Register registers the factory under the exchange name. Registering the same name twice replaces the factory.
+func (r *Registry) Register(name string, f Factory) {
+	r.factories[name] = f
+	delete(r.explicit, name)
+}
+
+// RegisterExplicit registers the factory of an exchange that is only created by Exchanges when it is enabled explicitly,
+// e.g. a simulated exchange whose virtual balances must not be mixed with the real ones.
+func (r *Registry) RegisterExplicit(name string, f Factory) {
+	r.factories[name] = f
+	r.explicit[name] = true
+}
+
+// Names returns the sorted names of the registered exchanges.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Get creates the exchange registered under the name.
+func (r *Registry) Get(cfg *config.Config, name string) (Exchange, error) {
+	f, ok := r.factories[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownExchange, name)
+	}
+
+	e, err := f(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating exchange %s: %w", name, err)
+	}
+
+	return e, nil
+}
+
+// Exchanges creates the exchanges enabled in the connector configuration.
+// Every exchange registered with Register is created when no exchange is enabled explicitly.
+func (r *Registry) Exchanges(cfg *config.Config) ([]Exchange, error) {
+	names := cfg.Connector.Exchanges
+	if len(names) == 0 {
+		for _, name := range r.Names() {
+			if !r.explicit[name] {
+				names = append(names, name)
+			}
+		}
+	}
+
+	exchanges := make([]Exchange, 0, len(names))
+
+	for _, name := range names {
+		e, err := r.Get(cfg, name)
+		if err != nil {
+			return nil, err
+		}
+
+		exchanges = append(exchanges, e)
+	}
+
+	return exchanges, nil
+}
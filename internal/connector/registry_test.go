@@ -0,0 +1,119 @@
+package connector_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+)
+
+type fakeExchange struct {
+	name string
+}
+
+func (f *fakeExchange) Name() string { return f.name }
+
+func (f *fakeExchange) GetBalances(_ context.Context) ([]connector.Balance, error) { return nil, nil }
+
+func (f *fakeExchange) GetTickers(_ context.Context, _ []string) ([]connector.Ticker, error) {
+	return nil, nil
+}
+
+func (f *fakeExchange) GetOrderBook(_ context.Context, _ string, _ int) (*connector.OrderBook, error) {
+	return nil, nil
+}
+
+func (f *fakeExchange) GetTrades(_ context.Context, _ string, _ int) ([]connector.Trade, error) {
+	return nil, nil
+}
+
+func (f *fakeExchange) GetSymbols(_ context.Context) ([]connector.Symbol, error) { return nil, nil }
+
+func newFakeFactory(name string) connector.Factory {
+	return func(_ *config.Config) (connector.Exchange, error) {
+		return &fakeExchange{name: name}, nil
+	}
+}
+
+func TestRegistry_Exchanges(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		cfg *config.Config
+	}
+
+	type want struct {
+		names []string
+		err   error
+	}
+
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"all registered exchanges": {
+			args: args{cfg: &config.Config{}},
+			want: want{names: []string{"alpha", "beta"}},
+		},
+		"enabled exchanges": {
+			args: args{cfg: &config.Config{Connector: config.Connector{Exchanges: []string{"beta"}}}},
+			want: want{names: []string{"beta"}},
+		},
+		"explicitly enabled exchange": {
+			args: args{cfg: &config.Config{Connector: config.Connector{Exchanges: []string{"alpha", "simulated"}}}},
+			want: want{names: []string{"alpha", "simulated"}},
+		},
+		"unknown exchange": {
+			args: args{cfg: &config.Config{Connector: config.Connector{Exchanges: []string{"gamma"}}}},
+			want: want{err: errors.New("unknown exchange: gamma")},
+		},
+		"factory error": {
+			args: args{cfg: &config.Config{Connector: config.Connector{Exchanges: []string{"broken"}}}},
+			want: want{err: errors.New("error creating exchange broken: boom")},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := connector.NewRegistry()
+			r.Register("beta", newFakeFactory("beta"))
+			r.Register("alpha", newFakeFactory("alpha"))
+			r.RegisterExplicit("simulated", newFakeFactory("simulated"))
+
+			if tt.args.cfg.Connector.Exchanges != nil {
+				r.Register("broken", func(_ *config.Config) (connector.Exchange, error) {
+					return nil, errors.New("boom")
+				})
+			}
+
+			exchanges, err := r.Exchanges(tt.args.cfg)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			names := make([]string, 0, len(exchanges))
+			for _, e := range exchanges {
+				names = append(names, e.Name())
+			}
+
+			assert.Equal(t, tt.want.names, names)
+		})
+	}
+}
+
+func TestRegistry_Get_UnknownExchange(t *testing.T) {
+	t.Parallel()
+
+	_, err := connector.NewRegistry().Get(&config.Config{}, "unknown")
+
+	assert.ErrorIs(t, err, connector.ErrUnknownExchange)
+}
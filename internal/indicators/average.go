@@ -0,0 +1,112 @@
+package indicators
+
+// SMA is the simple moving average of the values of the period.
+type SMA struct {
+	window *window
+	period int
+	sum    float64
+	count  int
+}
+
+// NewSMA creates a simple moving average of the period.
+func NewSMA(period int) (*SMA, error) {
+	if err := validatePeriods(period); err != nil {
+		return nil, err
+	}
+
+	return &SMA{window: newWindow(period), period: period}, nil
+}
+
+// Update adds the value and returns the average, defined once period values are added.
+func (s *SMA) Update(v float64) (float64, bool) {
+	if old, full := s.window.push(v); full {
+		s.sum -= old
+	} else {
+		s.count++
+	}
+
+	s.sum += v
+
+	return s.Value()
+}
+
+// Value returns the average, defined once period values are added.
+func (s *SMA) Value() (float64, bool) {
+	if s.count < s.period {
+		return 0, false
+	}
+
+	return s.sum / float64(s.period), true
+}
+
+// SMASeries returns the simple moving average of the values.
+func SMASeries(values []float64, period int) ([]float64, error) {
+	s, err := NewSMA(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return series(values, s.Update), nil
+}
+
+// EMA is the exponential moving average of the values with a smoothing of 2 / (period + 1), seeded with the simple moving average of the
+// first period values.
+type EMA struct {
+	seed  *SMA
+	alpha float64
+	value float64
+	ready bool
+}
+
+// NewEMA creates an exponential moving average of the period.
+func NewEMA(period int) (*EMA, error) {
+	seed, err := NewSMA(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EMA{seed: seed, alpha: 2 / float64(period+1)}, nil
+}
+
+// Update adds the value and returns the average, defined once period values are added.
+func (e *EMA) Update(v float64) (float64, bool) {
+	if e.ready {
+		e.value += e.alpha * (v - e.value)
+		return e.value, true
+	}
+
+	e.value, e.ready = e.seed.Update(v)
+
+	return e.value, e.ready
+}
+
+// Value returns the average, defined once period values are added.
+func (e *EMA) Value() (float64, bool) {
+	return e.value, e.ready
+}
+
+// EMASeries returns the exponential moving average of the values.
+func EMASeries(values []float64, period int) ([]float64, error) {
+	e, err := NewEMA(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return series(values, e.Update), nil
+}
+
+// series returns the values of the update of every input, NaN while they are not defined.
+func series[T any](inputs []T, update func(T) (float64, bool)) []float64 {
+	res := make([]float64, 0, len(inputs))
+
+	for _, in := range inputs {
+		v, ok := update(in)
+		if !ok {
+			v = nan()
+		}
+
+		res = append(res, v)
+	}
+
+	return res
+}
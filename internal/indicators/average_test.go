@@ -0,0 +1,81 @@
+package indicators_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/indicators"
+)
+
+func TestSMASeries(t *testing.T) {
+	t.Parallel()
+
+	got, err := indicators.SMASeries([]float64{1, 2, 3, 4, 5, 6}, 3)
+	assert.NoError(t, err)
+	assertSeries(t, []float64{2, 3, 4, 5}, got, delta)
+
+	_, err = indicators.SMASeries(nil, 0)
+	assert.ErrorIs(t, err, indicators.ErrInvalidPeriod)
+}
+
+func TestSMA(t *testing.T) {
+	t.Parallel()
+
+	s, err := indicators.NewSMA(2)
+	assert.NoError(t, err)
+
+	_, ok := s.Value()
+	assert.False(t, ok)
+
+	_, ok = s.Update(10)
+	assert.False(t, ok)
+
+	v, ok := s.Update(20)
+	assert.True(t, ok)
+	assert.InDelta(t, 15, v, delta)
+
+	v, ok = s.Update(40)
+	assert.True(t, ok)
+	assert.InDelta(t, 30, v, delta)
+
+	v, ok = s.Value()
+	assert.True(t, ok)
+	assert.InDelta(t, 30, v, delta)
+}
+
+func TestEMASeries(t *testing.T) {
+	t.Parallel()
+
+	// The values published by StockCharts are rounded to 2 decimals.
+	want := []float64{
+		22.22, 22.21, 22.24, 22.27, 22.33, 22.52, 22.80, 22.97, 23.13, 23.28, 23.34, 23.43, 23.51, 23.53, 23.47, 23.40, 23.39,
+		23.26, 23.23, 23.08, 22.92,
+	}
+
+	got, err := indicators.EMASeries(emaCloses(), 10)
+	assert.NoError(t, err)
+	assertSeries(t, want, got, 0.005)
+
+	_, err = indicators.NewEMA(-1)
+	assert.ErrorIs(t, err, indicators.ErrInvalidPeriod)
+}
+
+func TestEMA(t *testing.T) {
+	t.Parallel()
+
+	e, err := indicators.NewEMA(10)
+	assert.NoError(t, err)
+
+	batch, err := indicators.EMASeries(emaCloses(), 10)
+	assert.NoError(t, err)
+
+	for i, c := range emaCloses() {
+		v, ok := e.Update(c)
+		assert.Equal(t, i >= 9, ok)
+
+		if ok {
+			assert.InDelta(t, batch[i], v, delta)
+		}
+	}
+}
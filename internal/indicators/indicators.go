@@ -0,0 +1,100 @@
+// Package indicators provides technical indicators of price series, each with a streaming implementation updated one value at a time
+// and a batch implementation over a whole series. The values are float64, as the indicators are statistics rather than prices.
+//
+// A streaming indicator reports whether it has seen enough values to be defined, and the batch implementations return a value per
+// input aligned with it, NaN while the indicator is not defined yet.
+package indicators
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// ErrInvalidPeriod is returned when the period of an indicator is not positive.
+var ErrInvalidPeriod = errors.New("invalid period")
+
+// Bar is the high, low and close prices and the volume of a candle, the inputs of the indicators of the price range.
+type Bar struct {
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// BarOf returns the bar of the candle.
+func BarOf(c connector.Candle) Bar {
+	return Bar{High: c.High.InexactFloat64(), Low: c.Low.InexactFloat64(), Close: c.Close.InexactFloat64(), Volume: c.Volume.InexactFloat64()}
+}
+
+// Bars returns the bars of the candles.
+func Bars(candles []connector.Candle) []Bar {
+	res := make([]Bar, 0, len(candles))
+	for _, c := range candles {
+		res = append(res, BarOf(c))
+	}
+
+	return res
+}
+
+// Closes returns the close prices of the candles.
+func Closes(candles []connector.Candle) []float64 {
+	res := make([]float64, 0, len(candles))
+	for _, c := range candles {
+		res = append(res, c.Close.InexactFloat64())
+	}
+
+	return res
+}
+
+func validatePeriods(periods ...int) error {
+	for _, p := range periods {
+		if p < 1 {
+			return fmt.Errorf("%w: %d", ErrInvalidPeriod, p)
+		}
+	}
+
+	return nil
+}
+
+// window holds the last values of a series, up to its period.
+type window struct {
+	values []float64
+	next   int
+	full   bool
+}
+
+func newWindow(period int) *window {
+	return &window{values: make([]float64, period)}
+}
+
+// push adds the value, and returns the value it replaces once the window is full.
+func (w *window) push(v float64) (float64, bool) {
+	old, full := w.values[w.next], w.full
+	w.values[w.next] = v
+	w.next++
+
+	if w.next == len(w.values) {
+		w.next, w.full = 0, true
+	}
+
+	return old, full
+}
+
+// each calls fn with the values of the window, in no particular order.
+func (w *window) each(fn func(float64)) {
+	n := w.next
+	if w.full {
+		n = len(w.values)
+	}
+
+	for _, v := range w.values[:n] {
+		fn(v)
+	}
+}
+
+func nan() float64 {
+	return math.NaN()
+}
@@ -0,0 +1,83 @@
+package indicators_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/indicators"
+)
+
+// delta is the tolerance of the comparisons with the reference values, given with 6 decimals.
+const delta = 1e-6
+
+// rsiCloses are the closes of the RSI example of Wilder, as published by StockCharts.
+func rsiCloses() []float64 {
+	return []float64{
+		44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08, 45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03,
+		46.41, 46.22, 45.64, 46.21, 46.25, 45.71, 46.45, 45.78, 45.35, 44.03, 44.18, 44.22, 44.57, 43.42, 42.66, 43.13,
+	}
+}
+
+// emaCloses are the closes of the 10 day EMA example of StockCharts.
+func emaCloses() []float64 {
+	return []float64{
+		22.27, 22.19, 22.08, 22.17, 22.18, 22.13, 22.23, 22.43, 22.24, 22.29, 22.15, 22.39, 22.38, 22.61, 23.36,
+		24.05, 23.75, 23.83, 23.95, 23.63, 23.82, 23.87, 23.65, 23.19, 23.10, 23.33, 22.68, 23.10, 22.40, 22.17,
+	}
+}
+
+// bars are the bars the reference values of the range and volume indicators are computed from.
+func bars() []indicators.Bar {
+	values := [][4]float64{
+		{48.70, 47.79, 48.16, 100}, {48.72, 48.14, 48.61, 120}, {48.90, 48.39, 48.75, 90}, {48.87, 48.37, 48.63, 110},
+		{48.82, 48.24, 48.74, 80}, {49.05, 48.64, 49.03, 130}, {49.20, 48.94, 49.07, 150}, {49.35, 48.86, 49.32, 95},
+		{49.92, 49.50, 49.91, 160}, {50.19, 49.87, 50.13, 170}, {50.12, 49.20, 49.53, 140}, {49.66, 48.90, 49.50, 100},
+		{49.88, 49.43, 49.75, 105}, {50.19, 49.73, 50.03, 115}, {50.36, 49.26, 50.31, 125}, {50.57, 50.09, 50.52, 135},
+		{50.65, 50.30, 50.41, 90}, {50.43, 49.21, 49.34, 180}, {49.63, 48.98, 49.37, 150}, {50.33, 49.61, 50.23, 130},
+	}
+
+	res := make([]indicators.Bar, 0, len(values))
+	for _, v := range values {
+		res = append(res, indicators.Bar{High: v[0], Low: v[1], Close: v[2], Volume: v[3]})
+	}
+
+	return res
+}
+
+func closes(bars []indicators.Bar) []float64 {
+	res := make([]float64, 0, len(bars))
+	for _, b := range bars {
+		res = append(res, b.Close)
+	}
+
+	return res
+}
+
+// assertSeries asserts the values match the expected ones from the first defined value, and are NaN before it.
+func assertSeries(t *testing.T, want []float64, got []float64, tolerance float64) {
+	t.Helper()
+
+	first := len(got) - len(want)
+	for i, v := range got[:first] {
+		assert.True(t, math.IsNaN(v), "value %d is defined: %v", i, v)
+	}
+
+	assert.InDeltaSlice(t, want, got[first:], tolerance)
+}
+
+func TestBars(t *testing.T) {
+	t.Parallel()
+
+	candles := []connector.Candle{{
+		High: decimal.RequireFromString("101.5"), Low: decimal.RequireFromString("99"), Close: decimal.RequireFromString("100.25"),
+		Volume: decimal.RequireFromString("3"), OpenTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}}
+
+	assert.Equal(t, []indicators.Bar{{High: 101.5, Low: 99, Close: 100.25, Volume: 3}}, indicators.Bars(candles))
+	assert.Equal(t, []float64{100.25}, indicators.Closes(candles))
+}
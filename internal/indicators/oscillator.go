@@ -0,0 +1,235 @@
+package indicators
+
+import "math"
+
+// percent is the scale of the indicators given in percent.
+const percent = 100
+
+// RSI is the relative strength index of the values, with the average gains and losses smoothed by Wilder's method.
+type RSI struct {
+	period int
+	prev   float64
+	count  int
+	gain   float64
+	loss   float64
+}
+
+// NewRSI creates a relative strength index of the period.
+func NewRSI(period int) (*RSI, error) {
+	if err := validatePeriods(period); err != nil {
+		return nil, err
+	}
+
+	return &RSI{period: period}, nil
+}
+
+// Update adds the value and returns the index, defined once period changes, so period + 1 values, are added.
+func (r *RSI) Update(v float64) (float64, bool) {
+	r.count++
+	if r.count == 1 {
+		r.prev = v
+		return 0, false
+	}
+
+	change := v - r.prev
+	r.prev = v
+	gain, loss := math.Max(change, 0), math.Max(-change, 0)
+	n := float64(r.period)
+
+	if r.count <= r.period+1 {
+		// The first averages are the simple averages of the first changes.
+		r.gain += gain / n
+		r.loss += loss / n
+	} else {
+		r.gain = (r.gain*(n-1) + gain) / n
+		r.loss = (r.loss*(n-1) + loss) / n
+	}
+
+	return r.Value()
+}
+
+// Value returns the index, defined once period + 1 values are added.
+func (r *RSI) Value() (float64, bool) {
+	if r.count <= r.period {
+		return 0, false
+	}
+
+	if r.loss == 0 {
+		if r.gain == 0 {
+			return percent / 2, true
+		}
+
+		return percent, true
+	}
+
+	return percent - percent/(1+r.gain/r.loss), true
+}
+
+// RSISeries returns the relative strength index of the values.
+func RSISeries(values []float64, period int) ([]float64, error) {
+	r, err := NewRSI(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return series(values, r.Update), nil
+}
+
+// MACDValue is a value of the moving average convergence divergence. MACD is the difference of the fast and slow exponential moving
+// averages, Signal its exponential moving average, and Histogram the difference of both.
+type MACDValue struct {
+	MACD      float64
+	Signal    float64
+	Histogram float64
+}
+
+// MACD is the moving average convergence divergence of the values, 12, 26 and 9 being the usual periods.
+type MACD struct {
+	fast   *EMA
+	slow   *EMA
+	signal *EMA
+	value  MACDValue
+	ready  bool
+}
+
+// NewMACD creates a moving average convergence divergence of the periods of the fast, slow and signal averages.
+func NewMACD(fast, slow, signal int) (*MACD, error) {
+	if err := validatePeriods(fast, slow, signal); err != nil {
+		return nil, err
+	}
+
+	m := &MACD{}
+	m.fast, _ = NewEMA(fast)
+	m.slow, _ = NewEMA(slow)
+	m.signal, _ = NewEMA(signal)
+
+	return m, nil
+}
+
+// Update adds the value and returns the MACD, defined once the signal average is, after slow + signal - 1 values.
+func (m *MACD) Update(v float64) (MACDValue, bool) {
+	fast, fastOK := m.fast.Update(v)
+	slow, slowOK := m.slow.Update(v)
+
+	if !fastOK || !slowOK {
+		return MACDValue{}, false
+	}
+
+	macd := fast - slow
+
+	signal, ok := m.signal.Update(macd)
+	if !ok {
+		return MACDValue{}, false
+	}
+
+	m.value, m.ready = MACDValue{MACD: macd, Signal: signal, Histogram: macd - signal}, true
+
+	return m.value, true
+}
+
+// Value returns the MACD, defined once the signal average is.
+func (m *MACD) Value() (MACDValue, bool) {
+	return m.value, m.ready
+}
+
+// MACDSeries returns the moving average convergence divergence of the values, with NaN fields while it is not defined.
+func MACDSeries(values []float64, fast, slow, signal int) ([]MACDValue, error) {
+	m, err := NewMACD(fast, slow, signal)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]MACDValue, 0, len(values))
+
+	for _, v := range values {
+		value, ok := m.Update(v)
+		if !ok {
+			value = MACDValue{MACD: nan(), Signal: nan(), Histogram: nan()}
+		}
+
+		res = append(res, value)
+	}
+
+	return res, nil
+}
+
+// StochasticValue is a value of the stochastic oscillator. K is the position of the close price in the range of the period, in percent,
+// and D its simple moving average.
+type StochasticValue struct {
+	K float64
+	D float64
+}
+
+// Stochastic is the stochastic oscillator of bars, 14 and 3 being the usual periods. K is 0 when the range of the period is empty.
+type Stochastic struct {
+	highs *window
+	lows  *window
+	d     *SMA
+	value StochasticValue
+	ready bool
+}
+
+// NewStochastic creates a stochastic oscillator with the periods of the range and of the average of K.
+func NewStochastic(period, smoothing int) (*Stochastic, error) {
+	if err := validatePeriods(period, smoothing); err != nil {
+		return nil, err
+	}
+
+	d, _ := NewSMA(smoothing)
+
+	return &Stochastic{highs: newWindow(period), lows: newWindow(period), d: d}, nil
+}
+
+// Update adds the bar and returns the oscillator, defined once period + smoothing - 1 bars are added.
+func (s *Stochastic) Update(b Bar) (StochasticValue, bool) {
+	s.highs.push(b.High)
+	s.lows.push(b.Low)
+
+	if !s.lows.full {
+		return StochasticValue{}, false
+	}
+
+	high, low := math.Inf(-1), math.Inf(1)
+	s.highs.each(func(v float64) { high = math.Max(high, v) })
+	s.lows.each(func(v float64) { low = math.Min(low, v) })
+
+	var k float64
+	if high > low {
+		k = percent * (b.Close - low) / (high - low)
+	}
+
+	d, ok := s.d.Update(k)
+	if !ok {
+		return StochasticValue{}, false
+	}
+
+	s.value, s.ready = StochasticValue{K: k, D: d}, true
+
+	return s.value, true
+}
+
+// Value returns the oscillator, defined once period + smoothing - 1 bars are added.
+func (s *Stochastic) Value() (StochasticValue, bool) {
+	return s.value, s.ready
+}
+
+// StochasticSeries returns the stochastic oscillator of the bars, with NaN fields while it is not defined.
+func StochasticSeries(bars []Bar, period, smoothing int) ([]StochasticValue, error) {
+	s, err := NewStochastic(period, smoothing)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]StochasticValue, 0, len(bars))
+
+	for _, b := range bars {
+		value, ok := s.Update(b)
+		if !ok {
+			value = StochasticValue{K: nan(), D: nan()}
+		}
+
+		res = append(res, value)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,111 @@
+package indicators_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/indicators"
+)
+
+func TestRSISeries(t *testing.T) {
+	t.Parallel()
+
+	// The values of Wilder's example computed without rounding the averages, which StockCharts rounds to 2 decimals.
+	want := []float64{
+		70.46, 66.25, 66.48, 69.35, 66.29, 57.92, 62.88, 63.21, 56.01, 62.34, 54.67, 50.39, 40.02, 41.49, 41.90, 45.50, 37.32,
+		33.09, 37.79,
+	}
+
+	got, err := indicators.RSISeries(rsiCloses(), 14)
+	assert.NoError(t, err)
+	assertSeries(t, want, got, 0.005)
+}
+
+func TestRSI(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		values []float64
+		want   float64
+	}{
+		"Only gains":  {values: []float64{1, 2, 3}, want: 100},
+		"Only losses": {values: []float64{3, 2, 1}, want: 0},
+		"Flat":        {values: []float64{1, 1, 1}, want: 50},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := indicators.NewRSI(2)
+			assert.NoError(t, err)
+
+			for _, v := range tt.values {
+				r.Update(v)
+			}
+
+			v, ok := r.Value()
+			assert.True(t, ok)
+			assert.InDelta(t, tt.want, v, delta)
+		})
+	}
+}
+
+func TestMACDSeries(t *testing.T) {
+	t.Parallel()
+
+	got, err := indicators.MACDSeries(closes(bars()), 3, 6, 4)
+	assert.NoError(t, err)
+	assert.Len(t, got, len(bars()))
+
+	// The MACD is defined once the slow average is, on the 6th close, and its signal 3 closes later.
+	for _, v := range got[:8] {
+		assert.True(t, math.IsNaN(v.MACD) && math.IsNaN(v.Signal) && math.IsNaN(v.Histogram))
+	}
+
+	assert.InDelta(t, 0.314836, got[8].MACD, delta)
+	assert.InDelta(t, 0.224106, got[8].Signal, delta)
+
+	last := got[len(got)-1]
+	assert.InDelta(t, 0.020667, last.MACD, delta)
+	assert.InDelta(t, 0.002400, last.Signal, delta)
+	assert.InDelta(t, 0.018268, last.Histogram, delta)
+
+	_, err = indicators.NewMACD(12, 26, 0)
+	assert.ErrorIs(t, err, indicators.ErrInvalidPeriod)
+}
+
+func TestStochasticSeries(t *testing.T) {
+	t.Parallel()
+
+	got, err := indicators.StochasticSeries(bars(), 5, 3)
+	assert.NoError(t, err)
+
+	for _, v := range got[:6] {
+		assert.True(t, math.IsNaN(v.K) && math.IsNaN(v.D))
+	}
+
+	want := []indicators.StochasticValue{{K: 9.027778, D: 62.922526}, {K: 23.353293, D: 38.371628}, {K: 74.850299, D: 35.743790}}
+	for i, w := range want {
+		v := got[len(got)-len(want)+i]
+		assert.InDelta(t, w.K, v.K, delta)
+		assert.InDelta(t, w.D, v.D, delta)
+	}
+}
+
+func TestStochastic_EmptyRange(t *testing.T) {
+	t.Parallel()
+
+	s, err := indicators.NewStochastic(2, 1)
+	assert.NoError(t, err)
+
+	s.Update(indicators.Bar{High: 10, Low: 10, Close: 10})
+
+	v, ok := s.Update(indicators.Bar{High: 10, Low: 10, Close: 10})
+	assert.True(t, ok)
+	assert.Equal(t, indicators.StochasticValue{}, v)
+}
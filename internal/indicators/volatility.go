@@ -0,0 +1,133 @@
+package indicators
+
+import "math"
+
+// Bands is a value of the Bollinger bands: the simple moving average of the period, and the bands a number of standard deviations above
+// and below it.
+type Bands struct {
+	Upper  float64
+	Middle float64
+	Lower  float64
+}
+
+// Bollinger is the Bollinger bands of the values, 20 and 2 being the usual period and width. The standard deviation is the population
+// standard deviation of the values of the period.
+type Bollinger struct {
+	window *window
+	sma    *SMA
+	width  float64
+	value  Bands
+	ready  bool
+}
+
+// NewBollinger creates Bollinger bands of the period, width standard deviations away from the average.
+func NewBollinger(period int, width float64) (*Bollinger, error) {
+	sma, err := NewSMA(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bollinger{window: newWindow(period), sma: sma, width: width}, nil
+}
+
+// Update adds the value and returns the bands, defined once period values are added.
+func (b *Bollinger) Update(v float64) (Bands, bool) {
+	b.window.push(v)
+
+	mean, ok := b.sma.Update(v)
+	if !ok {
+		return Bands{}, false
+	}
+
+	var sum float64
+
+	b.window.each(func(x float64) { sum += (x - mean) * (x - mean) })
+
+	sd := math.Sqrt(sum / float64(len(b.window.values)))
+	b.value, b.ready = Bands{Upper: mean + b.width*sd, Middle: mean, Lower: mean - b.width*sd}, true
+
+	return b.value, true
+}
+
+// Value returns the bands, defined once period values are added.
+func (b *Bollinger) Value() (Bands, bool) {
+	return b.value, b.ready
+}
+
+// BollingerSeries returns the Bollinger bands of the values, with NaN fields while they are not defined.
+func BollingerSeries(values []float64, period int, width float64) ([]Bands, error) {
+	b, err := NewBollinger(period, width)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Bands, 0, len(values))
+
+	for _, v := range values {
+		value, ok := b.Update(v)
+		if !ok {
+			value = Bands{Upper: nan(), Middle: nan(), Lower: nan()}
+		}
+
+		res = append(res, value)
+	}
+
+	return res, nil
+}
+
+// ATR is the average true range of bars, smoothed by Wilder's method from the simple average of the first period true ranges.
+// The true range of a bar is its range extended to the previous close price.
+type ATR struct {
+	period    int
+	count     int
+	prevClose float64
+	value     float64
+}
+
+// NewATR creates an average true range of the period.
+func NewATR(period int) (*ATR, error) {
+	if err := validatePeriods(period); err != nil {
+		return nil, err
+	}
+
+	return &ATR{period: period}, nil
+}
+
+// Update adds the bar and returns the average, defined once period bars are added.
+func (a *ATR) Update(b Bar) (float64, bool) {
+	tr := b.High - b.Low
+	if a.count > 0 {
+		tr = math.Max(tr, math.Max(math.Abs(b.High-a.prevClose), math.Abs(b.Low-a.prevClose)))
+	}
+
+	a.count++
+	a.prevClose = b.Close
+	n := float64(a.period)
+
+	if a.count <= a.period {
+		a.value += tr / n
+	} else {
+		a.value = (a.value*(n-1) + tr) / n
+	}
+
+	return a.Value()
+}
+
+// Value returns the average, defined once period bars are added.
+func (a *ATR) Value() (float64, bool) {
+	if a.count < a.period {
+		return 0, false
+	}
+
+	return a.value, true
+}
+
+// ATRSeries returns the average true range of the bars.
+func ATRSeries(bars []Bar, period int) ([]float64, error) {
+	a, err := NewATR(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return series(bars, a.Update), nil
+}
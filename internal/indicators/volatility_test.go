@@ -0,0 +1,60 @@
+package indicators_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/indicators"
+)
+
+func TestBollingerSeries(t *testing.T) {
+	t.Parallel()
+
+	got, err := indicators.BollingerSeries(closes(bars()), 5, 2)
+	assert.NoError(t, err)
+
+	for _, v := range got[:4] {
+		assert.True(t, math.IsNaN(v.Upper) && math.IsNaN(v.Middle) && math.IsNaN(v.Lower))
+	}
+
+	want := []indicators.Bands{
+		{Upper: 50.968962, Middle: 50.122, Lower: 49.275038},
+		{Upper: 51.035600, Middle: 49.990, Lower: 48.944400},
+		{Upper: 51.001821, Middle: 49.974, Lower: 48.946179},
+	}
+	for i, w := range want {
+		v := got[len(got)-len(want)+i]
+		assert.InDelta(t, w.Upper, v.Upper, delta)
+		assert.InDelta(t, w.Middle, v.Middle, delta)
+		assert.InDelta(t, w.Lower, v.Lower, delta)
+	}
+
+	_, err = indicators.NewBollinger(0, 2)
+	assert.ErrorIs(t, err, indicators.ErrInvalidPeriod)
+}
+
+func TestATRSeries(t *testing.T) {
+	t.Parallel()
+
+	want := []float64{
+		0.616000, 0.574800, 0.511840, 0.507472, 0.525978, 0.484782, 0.573826, 0.611061, 0.578848, 0.555079, 0.664063, 0.627250,
+		0.571800, 0.701440, 0.691152, 0.744922,
+	}
+
+	got, err := indicators.ATRSeries(bars(), 5)
+	assert.NoError(t, err)
+	assertSeries(t, want, got, delta)
+
+	a, err := indicators.NewATR(5)
+	assert.NoError(t, err)
+
+	for _, b := range bars() {
+		a.Update(b)
+	}
+
+	v, ok := a.Value()
+	assert.True(t, ok)
+	assert.InDelta(t, 0.744922, v, delta)
+}
@@ -0,0 +1,42 @@
+package indicators
+
+// typicalPrices is the number of prices averaged by the typical price of a bar.
+const typicalPrices = 3
+
+// VWAP is the volume weighted average price of bars since the start or the last reset, weighting the typical price of every bar,
+// the average of its high, low and close prices, by its volume.
+type VWAP struct {
+	value  float64
+	volume float64
+}
+
+// NewVWAP creates a volume weighted average price.
+func NewVWAP() *VWAP {
+	return &VWAP{}
+}
+
+// Update adds the bar and returns the average, defined once a bar with a volume is added.
+func (v *VWAP) Update(b Bar) (float64, bool) {
+	if b.Volume > 0 {
+		typical := (b.High + b.Low + b.Close) / typicalPrices
+		v.value += (typical*b.Volume - v.value*b.Volume) / (v.volume + b.Volume)
+		v.volume += b.Volume
+	}
+
+	return v.Value()
+}
+
+// Value returns the average, defined once a bar with a volume is added.
+func (v *VWAP) Value() (float64, bool) {
+	return v.value, v.volume > 0
+}
+
+// Reset starts a new average, e.g. at the start of a session.
+func (v *VWAP) Reset() {
+	v.value, v.volume = 0, 0
+}
+
+// VWAPSeries returns the volume weighted average price of the bars.
+func VWAPSeries(bars []Bar) []float64 {
+	return series(bars, NewVWAP().Update)
+}
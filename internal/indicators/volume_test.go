@@ -0,0 +1,42 @@
+package indicators_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/indicators"
+)
+
+func TestVWAPSeries(t *testing.T) {
+	t.Parallel()
+
+	got := indicators.VWAPSeries(bars())
+	assert.Len(t, got, len(bars()))
+	assert.InDelta(t, 48.216667, got[0], delta)
+	assert.InDelta(t, 48.365758, got[1], delta)
+	assert.InDelta(t, 49.457138, got[len(got)-1], delta)
+}
+
+func TestVWAP(t *testing.T) {
+	t.Parallel()
+
+	v := indicators.NewVWAP()
+
+	_, ok := v.Update(indicators.Bar{High: 10, Low: 10, Close: 10})
+	assert.False(t, ok, "no volume traded yet")
+
+	v.Update(indicators.Bar{High: 12, Low: 9, Close: 9, Volume: 1})
+
+	got, ok := v.Update(indicators.Bar{High: 20, Low: 20, Close: 20, Volume: 3})
+	assert.True(t, ok)
+	assert.InDelta(t, 17.5, got, delta)
+
+	v.Reset()
+
+	_, ok = v.Value()
+	assert.False(t, ok)
+
+	got, _ = v.Update(indicators.Bar{High: 20, Low: 20, Close: 20, Volume: 3})
+	assert.InDelta(t, 20, got, delta)
+}
@@ -0,0 +1,219 @@
+// Package orderbook provides an in-memory L2 order book. Levels are kept sorted, bids from the highest price and asks from the lowest,
+// and every method is safe for concurrent use so that a book can be read while it is updated from a stream.
+package orderbook
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// ErrInsufficientLiquidity is returned when the book does not hold enough quantity to fill an order.
+var ErrInsufficientLiquidity = errors.New("insufficient liquidity")
+
+// ErrEmptyBook is returned when a side of the book has no level.
+var ErrEmptyBook = errors.New("empty order book")
+
+// Book is an L2 order book of a symbol.
+type Book struct {
+	mu           sync.RWMutex
+	symbol       string
+	lastUpdateID uint64
+	bids         []connector.PriceLevel
+	asks         []connector.PriceLevel
+}
+
+// New creates an empty book of the symbol.
+func New(symbol string) *Book {
+	return &Book{symbol: symbol}
+}
+
+// Symbol returns the symbol of the book.
+func (b *Book) Symbol() string {
+	return b.symbol
+}
+
+// Reset replaces the content of the book with a snapshot.
+func (b *Book) Reset(snapshot connector.OrderBook) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bids, b.asks = nil, nil
+	b.lastUpdateID = snapshot.LastUpdateID
+
+	for _, l := range snapshot.Bids {
+		b.bids = set(b.bids, l, descending)
+	}
+
+	for _, l := range snapshot.Asks {
+		b.asks = set(b.asks, l, ascending)
+	}
+}
+
+// Apply applies a diff to the book and records the ID of its last update. Levels with a zero quantity are removed.
+func (b *Book) Apply(lastUpdateID uint64, bids, asks []connector.PriceLevel) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, l := range bids {
+		b.bids = set(b.bids, l, descending)
+	}
+
+	for _, l := range asks {
+		b.asks = set(b.asks, l, ascending)
+	}
+
+	b.lastUpdateID = lastUpdateID
+}
+
+// LastUpdateID returns the ID of the last update applied to the book.
+func (b *Book) LastUpdateID() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.lastUpdateID
+}
+
+// Top returns a copy of the book limited to the n best levels of each side. A non-positive n returns every level.
+func (b *Book) Top(n int) connector.OrderBook {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return connector.OrderBook{Symbol: b.symbol, LastUpdateID: b.lastUpdateID, Bids: top(b.bids, n), Asks: top(b.asks, n)}
+}
+
+// BestBid returns the highest bid. It returns false when there is no bid.
+func (b *Book) BestBid() (connector.PriceLevel, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if len(b.bids) == 0 {
+		return connector.PriceLevel{}, false
+	}
+
+	return b.bids[0], true
+}
+
+// BestAsk returns the lowest ask. It returns false when there is no ask.
+func (b *Book) BestAsk() (connector.PriceLevel, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if len(b.asks) == 0 {
+		return connector.PriceLevel{}, false
+	}
+
+	return b.asks[0], true
+}
+
+// Spread returns the difference between the best ask and the best bid.
+func (b *Book) Spread() (decimal.Decimal, error) {
+	bid, ok := b.BestBid()
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%w: no bid for %s", ErrEmptyBook, b.symbol)
+	}
+
+	ask, ok := b.BestAsk()
+	if !ok {
+		return decimal.Zero, fmt.Errorf("%w: no ask for %s", ErrEmptyBook, b.symbol)
+	}
+
+	return ask.Price.Sub(bid.Price), nil
+}
+
+// DepthAt returns the quantity resting at prices at least as good as price on the side of the book:
+// bids at or above the price for SELL orders hitting the bids, and asks at or below the price for BUY orders lifting the asks.
+func (b *Book) DepthAt(side connector.Side, price decimal.Decimal) decimal.Decimal {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	depth := decimal.Zero
+
+	for _, l := range b.levels(side) {
+		if (side == connector.SideBuy && l.Price.GreaterThan(price)) || (side == connector.SideSell && l.Price.LessThan(price)) {
+			break
+		}
+
+		depth = depth.Add(l.Quantity)
+	}
+
+	return depth
+}
+
+// VWAP returns the volume weighted average price of a market order of the side filling quantity against the book.
+func (b *Book) VWAP(side connector.Side, quantity decimal.Decimal) (decimal.Decimal, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	remaining, notional := quantity, decimal.Zero
+
+	for _, l := range b.levels(side) {
+		if !remaining.IsPositive() {
+			break
+		}
+
+		filled := decimal.Min(remaining, l.Quantity)
+		notional = notional.Add(filled.Mul(l.Price))
+		remaining = remaining.Sub(filled)
+	}
+
+	if remaining.IsPositive() || !quantity.IsPositive() {
+		return decimal.Zero, fmt.Errorf("%w: cannot fill %s %s of %s", ErrInsufficientLiquidity, side, quantity, b.symbol)
+	}
+
+	return notional.Div(quantity), nil
+}
+
+// levels returns the side of the book consumed by an order of the given side.
+func (b *Book) levels(side connector.Side) []connector.PriceLevel {
+	if side == connector.SideBuy {
+		return b.asks
+	}
+
+	return b.bids
+}
+
+// before reports whether the price a sorts before the price b on a side of the book.
+type before func(a, b decimal.Decimal) bool
+
+func descending(a, b decimal.Decimal) bool { return a.GreaterThan(b) }
+
+func ascending(a, b decimal.Decimal) bool { return a.LessThan(b) }
+
+// set updates, inserts or removes the level in the sorted levels.
+func set(levels []connector.PriceLevel, l connector.PriceLevel, less before) []connector.PriceLevel {
+	i := sort.Search(len(levels), func(i int) bool { return !less(levels[i].Price, l.Price) })
+	found := i < len(levels) && levels[i].Price.Equal(l.Price)
+
+	switch {
+	case l.Quantity.IsZero() && found:
+		return append(levels[:i], levels[i+1:]...)
+	case l.Quantity.IsZero():
+		return levels
+	case found:
+		levels[i].Quantity = l.Quantity
+		return levels
+	default:
+		levels = append(levels, connector.PriceLevel{})
+		copy(levels[i+1:], levels[i:])
+		levels[i] = l
+
+		return levels
+	}
+}
+
+func top(levels []connector.PriceLevel, n int) []connector.PriceLevel {
+	if n <= 0 || n > len(levels) {
+		n = len(levels)
+	}
+
+	res := make([]connector.PriceLevel, n)
+	copy(res, levels[:n])
+
+	return res
+}
@@ -0,0 +1,197 @@
+package orderbook_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/orderbook"
+)
+
+func level(price, quantity string) connector.PriceLevel {
+	return connector.PriceLevel{Price: decimal.RequireFromString(price), Quantity: decimal.RequireFromString(quantity)}
+}
+
+// newBook returns a book with bids 100 x 1, 99 x 2, 98 x 3 and asks 101 x 1, 102 x 2, 103 x 3.
+func newBook() *orderbook.Book {
+	b := orderbook.New("BTCUSDT")
+	b.Reset(connector.OrderBook{
+		LastUpdateID: 10,
+		Bids:         []connector.PriceLevel{level("99", "2"), level("100", "1"), level("98", "3")},
+		Asks:         []connector.PriceLevel{level("103", "3"), level("101", "1"), level("102", "2")},
+	})
+
+	return b
+}
+
+func TestBook_Reset(t *testing.T) {
+	t.Parallel()
+
+	b := newBook()
+
+	assert.Equal(t, "BTCUSDT", b.Symbol())
+	assert.Equal(t, uint64(10), b.LastUpdateID())
+	assert.Equal(t, connector.OrderBook{
+		Symbol: "BTCUSDT", LastUpdateID: 10,
+		Bids: []connector.PriceLevel{level("100", "1"), level("99", "2")},
+		Asks: []connector.PriceLevel{level("101", "1"), level("102", "2")},
+	}, b.Top(2))
+	assert.Len(t, b.Top(0).Bids, 3)
+	assert.Len(t, b.Top(10).Asks, 3)
+}
+
+func TestBook_Apply(t *testing.T) {
+	t.Parallel()
+
+	b := newBook()
+	b.Apply(12,
+		[]connector.PriceLevel{level("100", "0"), level("99.5", "4"), level("98", "1.5"), level("90", "0")},
+		[]connector.PriceLevel{level("101", "0"), level("104", "1")},
+	)
+
+	assert.Equal(t, connector.OrderBook{
+		Symbol: "BTCUSDT", LastUpdateID: 12,
+		Bids: []connector.PriceLevel{level("99.5", "4"), level("99", "2"), level("98", "1.5")},
+		Asks: []connector.PriceLevel{level("102", "2"), level("103", "3"), level("104", "1")},
+	}, b.Top(0))
+}
+
+func TestBook_BestAndSpread(t *testing.T) {
+	t.Parallel()
+
+	b := newBook()
+
+	bid, ok := b.BestBid()
+	assert.True(t, ok)
+	assert.Equal(t, level("100", "1"), bid)
+
+	ask, ok := b.BestAsk()
+	assert.True(t, ok)
+	assert.Equal(t, level("101", "1"), ask)
+
+	spread, err := b.Spread()
+	assert.NoError(t, err)
+	assert.Equal(t, "1", spread.String())
+
+	empty := orderbook.New("ETHUSDT")
+
+	_, ok = empty.BestBid()
+	assert.False(t, ok)
+
+	_, err = empty.Spread()
+	assert.EqualError(t, err, "empty order book: no bid for ETHUSDT")
+	assert.ErrorIs(t, err, orderbook.ErrEmptyBook)
+
+	empty.Apply(1, []connector.PriceLevel{level("100", "1")}, nil)
+
+	_, err = empty.Spread()
+	assert.EqualError(t, err, "empty order book: no ask for ETHUSDT")
+}
+
+func TestBook_DepthAt(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		side  connector.Side
+		price string
+		want  string
+	}{
+		"asks up to the best ask":  {side: connector.SideBuy, price: "101", want: "1"},
+		"asks up to a price":       {side: connector.SideBuy, price: "102.5", want: "3"},
+		"asks below the best ask":  {side: connector.SideBuy, price: "100", want: "0"},
+		"bids down to a price":     {side: connector.SideSell, price: "99", want: "3"},
+		"bids down to every level": {side: connector.SideSell, price: "1", want: "6"},
+	}
+
+	b := newBook()
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, b.DepthAt(tt.side, decimal.RequireFromString(tt.price)).String())
+		})
+	}
+}
+
+func TestBook_VWAP(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		vwap string
+		err  error
+	}
+
+	tests := map[string]struct {
+		side     connector.Side
+		quantity string
+		want     want
+	}{
+		"buy within the best ask": {side: connector.SideBuy, quantity: "0.5", want: want{vwap: "101"}},
+		"buy through two levels":  {side: connector.SideBuy, quantity: "2", want: want{vwap: "101.5"}},
+		"sell through all levels": {side: connector.SideSell, quantity: "6", want: want{vwap: "98.6666666666666667"}},
+		"not enough liquidity": {
+			side: connector.SideSell, quantity: "6.5",
+			want: want{err: errors.New("insufficient liquidity: cannot fill SELL 6.5 of BTCUSDT")},
+		},
+		"zero quantity": {
+			side: connector.SideBuy, quantity: "0",
+			want: want{err: errors.New("insufficient liquidity: cannot fill BUY 0 of BTCUSDT")},
+		},
+	}
+
+	b := newBook()
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			vwap, err := b.VWAP(tt.side, decimal.RequireFromString(tt.quantity))
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				assert.ErrorIs(t, err, orderbook.ErrInsufficientLiquidity)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.vwap, vwap.String())
+		})
+	}
+}
+
+func TestBook_Concurrency(t *testing.T) {
+	t.Parallel()
+
+	b := newBook()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+			b.Apply(uint64(20+i), []connector.PriceLevel{level("97", "1")}, []connector.PriceLevel{level("105", "1")})
+		}(i)
+
+		go func() {
+			defer wg.Done()
+			_, _ = b.Spread()
+			_ = b.Top(5)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Len(t, b.Top(0).Bids, 4)
+	assert.Len(t, b.Top(0).Asks, 4)
+}
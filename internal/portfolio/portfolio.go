@@ -0,0 +1,290 @@
+// Package portfolio provides a consolidated view of the holdings of several exchanges. Balances are collected from every exchange,
+// merged per asset and valued in a quote asset using the ticker prices of the exchanges.
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// percent converts a ratio into a percentage.
+const percent = 100
+
+// Holding is the amount of an asset held on an exchange, valued in the quote asset.
+// Price and Value are zero when the asset could not be priced.
+type Holding struct {
+	Exchange string
+	Asset    string
+	Amount   decimal.Decimal
+	Price    decimal.Decimal
+	Value    decimal.Decimal
+	Priced   bool
+}
+
+// AssetTotal is the amount of an asset held across every exchange.
+type AssetTotal struct {
+	Asset      string
+	Amount     decimal.Decimal
+	Price      decimal.Decimal
+	Value      decimal.Decimal
+	Allocation decimal.Decimal
+	Priced     bool
+}
+
+// ExchangeTotal is the value held on an exchange.
+type ExchangeTotal struct {
+	Exchange   string
+	Value      decimal.Decimal
+	Allocation decimal.Decimal
+}
+
+// Portfolio is the consolidated view of the holdings. Allocations are percentages of Total, which only sums the priced holdings.
+type Portfolio struct {
+	Quote     string
+	Holdings  []Holding
+	Assets    []AssetTotal
+	Exchanges []ExchangeTotal
+	Total     decimal.Decimal
+}
+
+// Unpriced returns the assets that could not be valued in the quote asset.
+func (p *Portfolio) Unpriced() []string {
+	var assets []string
+
+	for _, a := range p.Assets {
+		if !a.Priced {
+			assets = append(assets, a.Asset)
+		}
+	}
+
+	return assets
+}
+
+// Service builds portfolios from exchanges.
+type Service struct {
+	exchanges []connector.Exchange
+}
+
+// NewService creates a new service collecting the holdings of the exchanges.
+func NewService(exchanges ...connector.Exchange) *Service {
+	return &Service{exchanges: exchanges}
+}
+
+// Build collects the non-zero balances of every exchange and values them in the quote asset.
+// An asset is priced from the first exchange listing a market between the asset and the quote, in either direction.
+func (s *Service) Build(ctx context.Context, quote string) (*Portfolio, error) {
+	quote = strings.ToUpper(quote)
+	p := &Portfolio{Quote: quote, Total: decimal.Zero}
+
+	for _, e := range s.exchanges {
+		balances, err := e.GetBalances(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting balances of %s: %w", e.Name(), err)
+		}
+
+		for _, b := range connector.NonZeroBalances(balances) {
+			p.Holdings = append(p.Holdings, Holding{Exchange: e.Name(), Asset: b.Asset, Amount: b.Total()})
+		}
+	}
+
+	prices, err := s.prices(ctx, quote, assets(p.Holdings))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, h := range p.Holdings {
+		if price, ok := prices[h.Asset]; ok {
+			p.Holdings[i].Price, p.Holdings[i].Value, p.Holdings[i].Priced = price, h.Amount.Mul(price), true
+			p.Total = p.Total.Add(p.Holdings[i].Value)
+		}
+	}
+
+	sort.Slice(p.Holdings, func(i, j int) bool {
+		if p.Holdings[i].Exchange != p.Holdings[j].Exchange {
+			return p.Holdings[i].Exchange < p.Holdings[j].Exchange
+		}
+
+		return p.Holdings[i].Asset < p.Holdings[j].Asset
+	})
+
+	p.Assets, p.Exchanges = totals(p.Holdings, p.Total)
+
+	return p, nil
+}
+
+// market is a ticker symbol pricing an asset, inverted when the asset is the quote asset of the symbol.
+type market struct {
+	symbol   string
+	inverted bool
+}
+
+// prices returns the price in the quote asset of the assets that could be priced.
+func (s *Service) prices(ctx context.Context, quote string, assets []string) (map[string]decimal.Decimal, error) {
+	prices := map[string]decimal.Decimal{quote: decimal.NewFromInt(1)}
+
+	for _, e := range s.exchanges {
+		markets, err := s.markets(ctx, e, quote, assets, prices)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(markets) == 0 {
+			continue
+		}
+
+		symbols := make([]string, 0, len(markets))
+		for _, m := range markets {
+			symbols = append(symbols, m.symbol)
+		}
+
+		sort.Strings(symbols)
+
+		tickers, err := e.GetTickers(ctx, symbols)
+		if err != nil {
+			return nil, fmt.Errorf("error getting tickers of %s: %w", e.Name(), err)
+		}
+
+		for asset, m := range markets {
+			for _, t := range tickers {
+				if t.Symbol != m.symbol || !t.Price.IsPositive() {
+					continue
+				}
+
+				if m.inverted {
+					prices[asset] = decimal.NewFromInt(1).DivRound(t.Price, int32(decimal.DivisionPrecision))
+				} else {
+					prices[asset] = t.Price
+				}
+			}
+		}
+	}
+
+	return prices, nil
+}
+
+// markets returns the markets of the exchange pricing the assets that are not priced yet.
+func (s *Service) markets(
+	ctx context.Context, e connector.Exchange, quote string, assets []string, prices map[string]decimal.Decimal,
+) (map[string]market, error) {
+	var missing []string
+
+	for _, a := range assets {
+		if _, ok := prices[a]; !ok {
+			missing = append(missing, a)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	symbols, err := e.GetSymbols(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting symbols of %s: %w", e.Name(), err)
+	}
+
+	markets := make(map[string]market, len(missing))
+
+	for _, a := range missing {
+		for _, sym := range symbols {
+			switch {
+			case sym.BaseAsset == a && sym.QuoteAsset == quote:
+				markets[a] = market{symbol: sym.Name}
+			case sym.BaseAsset == quote && sym.QuoteAsset == a:
+				if _, ok := markets[a]; !ok {
+					markets[a] = market{symbol: sym.Name, inverted: true}
+				}
+			}
+		}
+	}
+
+	return markets, nil
+}
+
+// assets returns the sorted distinct assets of the holdings.
+func assets(holdings []Holding) []string {
+	seen := make(map[string]bool, len(holdings))
+
+	var res []string
+
+	for _, h := range holdings {
+		if !seen[h.Asset] {
+			seen[h.Asset] = true
+			res = append(res, h.Asset)
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// totals sums the holdings per asset and per exchange, both sorted by descending value then name.
+func totals(holdings []Holding, total decimal.Decimal) ([]AssetTotal, []ExchangeTotal) {
+	byAsset := make(map[string]*AssetTotal)
+	byExchange := make(map[string]*ExchangeTotal)
+
+	for _, h := range holdings {
+		a, ok := byAsset[h.Asset]
+		if !ok {
+			a = &AssetTotal{Asset: h.Asset, Price: h.Price, Priced: h.Priced}
+			byAsset[h.Asset] = a
+		}
+
+		a.Amount = a.Amount.Add(h.Amount)
+		a.Value = a.Value.Add(h.Value)
+
+		e, ok := byExchange[h.Exchange]
+		if !ok {
+			e = &ExchangeTotal{Exchange: h.Exchange}
+			byExchange[h.Exchange] = e
+		}
+
+		e.Value = e.Value.Add(h.Value)
+	}
+
+	assetTotals := make([]AssetTotal, 0, len(byAsset))
+	for _, a := range byAsset {
+		a.Allocation = allocation(a.Value, total)
+		assetTotals = append(assetTotals, *a)
+	}
+
+	sort.Slice(assetTotals, func(i, j int) bool {
+		return less(assetTotals[i].Value, assetTotals[j].Value, assetTotals[i].Asset, assetTotals[j].Asset)
+	})
+
+	exchangeTotals := make([]ExchangeTotal, 0, len(byExchange))
+	for _, e := range byExchange {
+		e.Allocation = allocation(e.Value, total)
+		exchangeTotals = append(exchangeTotals, *e)
+	}
+
+	sort.Slice(exchangeTotals, func(i, j int) bool {
+		return less(exchangeTotals[i].Value, exchangeTotals[j].Value, exchangeTotals[i].Exchange, exchangeTotals[j].Exchange)
+	})
+
+	return assetTotals, exchangeTotals
+}
+
+// allocation returns the percentage of the total represented by the value, zero when the total is zero.
+func allocation(value, total decimal.Decimal) decimal.Decimal {
+	if total.IsZero() {
+		return decimal.Zero
+	}
+
+	return value.Mul(decimal.NewFromInt(percent)).Div(total)
+}
+
+func less(valueA, valueB decimal.Decimal, nameA, nameB string) bool {
+	if !valueA.Equal(valueB) {
+		return valueA.GreaterThan(valueB)
+	}
+
+	return nameA < nameB
+}
@@ -0,0 +1,178 @@
+package portfolio_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/portfolio"
+)
+
+type fakeExchange struct {
+	name        string
+	balances    []connector.Balance
+	symbols     []connector.Symbol
+	prices      map[string]string
+	balancesErr error
+	symbolsErr  error
+	tickersErr  error
+	requested   []string
+}
+
+func (f *fakeExchange) Name() string { return f.name }
+
+func (f *fakeExchange) GetBalances(_ context.Context) ([]connector.Balance, error) {
+	return f.balances, f.balancesErr
+}
+
+func (f *fakeExchange) GetTickers(_ context.Context, symbols []string) ([]connector.Ticker, error) {
+	if f.tickersErr != nil {
+		return nil, f.tickersErr
+	}
+
+	f.requested = append(f.requested, symbols...)
+
+	tickers := make([]connector.Ticker, 0, len(symbols))
+	for _, s := range symbols {
+		tickers = append(tickers, connector.Ticker{Symbol: s, Price: decimal.RequireFromString(f.prices[s])})
+	}
+
+	return tickers, nil
+}
+
+func (f *fakeExchange) GetOrderBook(_ context.Context, _ string, _ int) (*connector.OrderBook, error) {
+	return nil, nil
+}
+
+func (f *fakeExchange) GetTrades(_ context.Context, _ string, _ int) ([]connector.Trade, error) {
+	return nil, nil
+}
+
+func (f *fakeExchange) GetSymbols(_ context.Context) ([]connector.Symbol, error) {
+	return f.symbols, f.symbolsErr
+}
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func symbol(base, quote string) connector.Symbol {
+	return connector.Symbol{Name: base + quote, BaseAsset: base, QuoteAsset: quote}
+}
+
+func TestService_Build(t *testing.T) {
+	t.Parallel()
+
+	a := &fakeExchange{
+		name: "alpha",
+		balances: []connector.Balance{
+			{Asset: "BTC", Free: d("0.5"), Locked: d("0.5")},
+			{Asset: "USDT", Free: d("1000")},
+			{Asset: "DOGE", Free: d("0"), Locked: d("0")},
+		},
+		symbols: []connector.Symbol{symbol("BTC", "USDT"), symbol("ETH", "BTC")},
+		prices:  map[string]string{"BTCUSDT": "60000"},
+	}
+	b := &fakeExchange{
+		name: "beta",
+		balances: []connector.Balance{
+			{Asset: "BTC", Free: d("1")},
+			{Asset: "EUR", Free: d("500")},
+			{Asset: "XYZ", Free: d("10")},
+		},
+		symbols: []connector.Symbol{symbol("BTC", "USDT"), symbol("USDT", "EUR")},
+		prices:  map[string]string{"USDTEUR": "0.8", "BTCUSDT": "1"},
+	}
+
+	p, err := portfolio.NewService(a, b).Build(context.Background(), "usdt")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "USDT", p.Quote)
+	assert.Equal(t, "121625", p.Total.String())
+	assert.Equal(t, []string{"BTCUSDT"}, a.requested)
+	assert.Equal(t, []string{"USDTEUR"}, b.requested)
+	assert.Equal(t, []string{"XYZ"}, p.Unpriced())
+
+	holdings := make([]string, 0, len(p.Holdings))
+	for _, h := range p.Holdings {
+		holdings = append(holdings, fmt.Sprintf("%s %s %s @ %s = %s %t", h.Exchange, h.Asset, h.Amount, h.Price, h.Value, h.Priced))
+	}
+
+	assert.Equal(t, []string{
+		"alpha BTC 1 @ 60000 = 60000 true",
+		"alpha USDT 1000 @ 1 = 1000 true",
+		"beta BTC 1 @ 60000 = 60000 true",
+		"beta EUR 500 @ 1.25 = 625 true",
+		"beta XYZ 10 @ 0 = 0 false",
+	}, holdings)
+
+	assets := make(map[string]string, len(p.Assets))
+	order := make([]string, 0, len(p.Assets))
+
+	for _, asset := range p.Assets {
+		assets[asset.Asset] = asset.Allocation.StringFixed(2)
+		order = append(order, asset.Asset)
+	}
+
+	assert.Equal(t, []string{"BTC", "USDT", "EUR", "XYZ"}, order)
+	assert.Equal(t, map[string]string{"BTC": "98.66", "USDT": "0.82", "EUR": "0.51", "XYZ": "0.00"}, assets)
+	assert.True(t, p.Assets[0].Amount.Equal(d("2")))
+
+	assert.Len(t, p.Exchanges, 2)
+	assert.Equal(t, "alpha", p.Exchanges[0].Exchange)
+	assert.Equal(t, "61000", p.Exchanges[0].Value.String())
+	assert.Equal(t, "50.15", p.Exchanges[0].Allocation.StringFixed(2))
+	assert.Equal(t, "beta", p.Exchanges[1].Exchange)
+	assert.Equal(t, "49.85", p.Exchanges[1].Allocation.StringFixed(2))
+}
+
+func TestService_Build_Empty(t *testing.T) {
+	t.Parallel()
+
+	p, err := portfolio.NewService(&fakeExchange{name: "alpha"}).Build(context.Background(), "USDT")
+	assert.NoError(t, err)
+	assert.True(t, p.Total.IsZero())
+	assert.Empty(t, p.Holdings)
+	assert.Empty(t, p.Exchanges)
+	assert.Empty(t, p.Unpriced())
+}
+
+func TestService_Build_Errors(t *testing.T) {
+	t.Parallel()
+
+	balances := []connector.Balance{{Asset: "BTC", Free: d("1")}}
+
+	tests := map[string]struct {
+		exchange *fakeExchange
+		want     error
+	}{
+		"balances error": {
+			exchange: &fakeExchange{name: "alpha", balancesErr: errors.New("balances error")},
+			want:     errors.New("error getting balances of alpha: balances error"),
+		},
+		"symbols error": {
+			exchange: &fakeExchange{name: "alpha", balances: balances, symbolsErr: errors.New("symbols error")},
+			want:     errors.New("error getting symbols of alpha: symbols error"),
+		},
+		"tickers error": {
+			exchange: &fakeExchange{name: "alpha", balances: balances, symbols: []connector.Symbol{symbol("BTC", "USDT")}, tickersErr: errors.New("tickers error")},
+			want:     errors.New("error getting tickers of alpha: tickers error"),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := portfolio.NewService(tt.exchange).Build(context.Background(), "USDT")
+			assert.EqualError(t, err, tt.want.Error())
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// killSwitchFileMode is the mode of the kill switch file.
+const killSwitchFileMode = 0o600
+
+// KillSwitch blocks every new order while a file exists at its path. It is checked before every order, so creating the file,
+// with the kill command, by hand or from another process, stops the running strategies from placing orders at once.
+type KillSwitch struct {
+	path string
+}
+
+// NewKillSwitch creates the kill switch of the file at the path. The kill switch of an empty path is never engaged.
+func NewKillSwitch(path string) *KillSwitch {
+	return &KillSwitch{path: path}
+}
+
+// Path returns the path of the kill switch file.
+func (k *KillSwitch) Path() string {
+	return k.path
+}
+
+// Engaged reports whether the kill switch is engaged, and returns the content of its file, the reason it was engaged for.
+func (k *KillSwitch) Engaged() (bool, string, error) {
+	if k.path == "" {
+		return false, "", nil
+	}
+
+	b, err := os.ReadFile(k.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, "", nil
+	}
+
+	if err != nil {
+		return false, "", fmt.Errorf("error reading kill switch: %w", err)
+	}
+
+	return true, strings.TrimSpace(string(b)), nil
+}
+
+// Engage engages the kill switch, writing the time and the reason in its file.
+func (k *KillSwitch) Engage(reason string, now time.Time) error {
+	if k.path == "" {
+		return errors.New("no kill switch file is configured")
+	}
+
+	content := fmt.Sprintf("engaged at %s", now.UTC().Format(time.RFC3339))
+	if reason != "" {
+		content += ": " + reason
+	}
+
+	if err := os.WriteFile(k.path, []byte(content+"\n"), killSwitchFileMode); err != nil {
+		return fmt.Errorf("error writing kill switch: %w", err)
+	}
+
+	return nil
+}
+
+// Release releases the kill switch, removing its file. Releasing a kill switch which is not engaged does nothing.
+func (k *KillSwitch) Release() error {
+	if k.path == "" {
+		return nil
+	}
+
+	if err := os.Remove(k.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing kill switch: %w", err)
+	}
+
+	return nil
+}
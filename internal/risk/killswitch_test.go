@@ -0,0 +1,52 @@
+package risk_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/risk"
+)
+
+func TestKillSwitch(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "kill")
+	k := risk.NewKillSwitch(path)
+
+	engaged, _, err := k.Engaged()
+	assert.NoError(t, err)
+	assert.False(t, engaged)
+
+	assert.NoError(t, k.Engage("", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+	engaged, reason, err := k.Engaged()
+	assert.NoError(t, err)
+	assert.True(t, engaged)
+	assert.Equal(t, "engaged at 2024-01-02T03:04:05Z", reason)
+
+	assert.NoError(t, k.Release())
+	assert.NoError(t, k.Release(), "releasing a released kill switch does nothing")
+
+	assert.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	engaged, reason, err = k.Engaged()
+	assert.NoError(t, err)
+	assert.True(t, engaged, "any file engages the kill switch")
+	assert.Empty(t, reason)
+}
+
+func TestKillSwitch_NoPath(t *testing.T) {
+	t.Parallel()
+
+	k := risk.NewKillSwitch("")
+
+	engaged, _, err := k.Engaged()
+	assert.NoError(t, err)
+	assert.False(t, engaged)
+	assert.EqualError(t, k.Engage("", time.Now()), "no kill switch file is configured")
+	assert.NoError(t, k.Release())
+}
@@ -0,0 +1,102 @@
+package risk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/config"
+)
+
+// Limits are the limits checked before an order is placed. A zero limit disables its check, and an empty AllowedSymbols allows
+// every symbol. MaxOrderNotional and DailyLossLimit are amounts of the Quote asset, and PriceCollar is a fraction of the last price.
+// MaxPositions only limits the holdings of the assets it lists, a zero position forbidding to buy the asset.
+type Limits struct {
+	AllowedSymbols   map[string]bool
+	MaxOrderNotional decimal.Decimal
+	MaxPositions     map[string]decimal.Decimal
+	MaxOpenOrders    int
+	DailyLossLimit   decimal.Decimal
+	PriceCollar      decimal.Decimal
+	Quote            string
+}
+
+// NewLimits creates the limits of the risk configuration. The symbols and assets are case insensitive.
+func NewLimits(cfg config.Risk) (Limits, error) {
+	l := Limits{AllowedSymbols: make(map[string]bool), MaxOpenOrders: cfg.MaxOpenOrders, Quote: cfg.QuoteAsset()}
+
+	for _, s := range cfg.AllowedSymbols {
+		if s = strings.ToUpper(strings.TrimSpace(s)); s != "" {
+			l.AllowedSymbols[s] = true
+		}
+	}
+
+	var err error
+
+	if l.MaxOrderNotional, err = parseLimit(cfg.MaxOrderNotional); err != nil {
+		return Limits{}, fmt.Errorf("invalid max order notional: %w", err)
+	}
+
+	if l.MaxPositions, err = parsePositions(cfg.MaxPositions); err != nil {
+		return Limits{}, fmt.Errorf("invalid max positions: %w", err)
+	}
+
+	if l.MaxOpenOrders < 0 {
+		return Limits{}, fmt.Errorf("invalid max open orders: %d is negative", l.MaxOpenOrders)
+	}
+
+	if l.DailyLossLimit, err = parseLimit(cfg.DailyLossLimit); err != nil {
+		return Limits{}, fmt.Errorf("invalid daily loss limit: %w", err)
+	}
+
+	if l.PriceCollar, err = parseLimit(cfg.PriceCollar); err != nil {
+		return Limits{}, fmt.Errorf("invalid price collar: %w", err)
+	}
+
+	return l, nil
+}
+
+// parseLimit parses a limit, zero when it is empty.
+func parseLimit(s string) (decimal.Decimal, error) {
+	if s == "" {
+		return decimal.Zero, nil
+	}
+
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("error parsing %q: %w", s, err)
+	}
+
+	if d.IsNegative() {
+		return decimal.Zero, fmt.Errorf("%s is negative", s)
+	}
+
+	return d, nil
+}
+
+// parsePositions parses the maximum positions given as comma separated ASSET=AMOUNT pairs, e.g. BTC=0.5,ETH=10.
+func parsePositions(s string) (map[string]decimal.Decimal, error) {
+	positions := make(map[string]decimal.Decimal)
+
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		asset, amount, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(asset) == "" {
+			return nil, fmt.Errorf("invalid position %q: expected ASSET=AMOUNT", pair)
+		}
+
+		d, err := parseLimit(strings.TrimSpace(amount))
+		if err != nil {
+			return nil, fmt.Errorf("invalid position of %s: %w", asset, err)
+		}
+
+		positions[strings.ToUpper(strings.TrimSpace(asset))] = d
+	}
+
+	return positions, nil
+}
@@ -0,0 +1,84 @@
+package risk_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/risk"
+)
+
+func TestNewLimits(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		limits risk.Limits
+		err    error
+	}
+
+	tests := map[string]struct {
+		cfg  config.Risk
+		want want
+	}{
+		"empty": {
+			want: want{limits: risk.Limits{AllowedSymbols: map[string]bool{}, MaxPositions: map[string]decimal.Decimal{}, Quote: "USDT"}},
+		},
+		"limits": {
+			cfg: config.Risk{
+				AllowedSymbols: []string{"btcusdt", " ETHUSDT "}, MaxOrderNotional: "1000", MaxPositions: "btc=0.5, ETH=10", MaxOpenOrders: 5,
+				DailyLossLimit: "250", PriceCollar: "0.05", Quote: "fdusd",
+			},
+			want: want{limits: risk.Limits{
+				AllowedSymbols: map[string]bool{"BTCUSDT": true, "ETHUSDT": true}, MaxOrderNotional: d("1000"),
+				MaxPositions: map[string]decimal.Decimal{"BTC": d("0.5"), "ETH": d("10")}, MaxOpenOrders: 5, DailyLossLimit: d("250"),
+				PriceCollar: d("0.05"), Quote: "FDUSD",
+			}},
+		},
+		"invalid notional": {
+			cfg:  config.Risk{MaxOrderNotional: "lots"},
+			want: want{err: errors.New(`invalid max order notional: error parsing "lots": can't convert lots to decimal`)},
+		},
+		"negative daily loss limit": {
+			cfg:  config.Risk{DailyLossLimit: "-1"},
+			want: want{err: errors.New("invalid daily loss limit: -1 is negative")},
+		},
+		"invalid position": {
+			cfg:  config.Risk{MaxPositions: "BTC"},
+			want: want{err: errors.New(`invalid max positions: invalid position "BTC": expected ASSET=AMOUNT`)},
+		},
+		"negative max open orders": {
+			cfg:  config.Risk{MaxOpenOrders: -1},
+			want: want{err: errors.New("invalid max open orders: -1 is negative")},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := risk.NewLimits(tt.cfg)
+			if tt.want.err != nil {
+				assert.EqualError(t, err, tt.want.err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.limits.AllowedSymbols, got.AllowedSymbols)
+			assert.Equal(t, tt.want.limits.MaxOpenOrders, got.MaxOpenOrders)
+			assert.Equal(t, tt.want.limits.Quote, got.Quote)
+			assert.Equal(t, tt.want.limits.MaxOrderNotional.String(), got.MaxOrderNotional.String())
+			assert.Equal(t, tt.want.limits.DailyLossLimit.String(), got.DailyLossLimit.String())
+			assert.Equal(t, tt.want.limits.PriceCollar.String(), got.PriceCollar.String())
+			assert.Len(t, got.MaxPositions, len(tt.want.limits.MaxPositions))
+
+			for asset, position := range tt.want.limits.MaxPositions {
+				assert.Equal(t, position.String(), got.MaxPositions[asset].String())
+			}
+		})
+	}
+}
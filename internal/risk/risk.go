@@ -0,0 +1,440 @@
+// Package risk provides the pre-trade risk engine, which checks every order against the configured limits and the kill switch
+// before it is placed on the exchange. A rejected order never reaches the exchange, and the rejection is logged and returned
+// as a *Violation wrapping the error of the rule.
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/config"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/portfolio"
+)
+
+// ratioPlaces is the number of decimal places of the deviations and values of the violations.
+const ratioPlaces = 8
+
+// The rules the orders are checked against, wrapped by the *Violation rejecting an order.
+var (
+	// ErrKillSwitch is returned when the kill switch is engaged.
+	ErrKillSwitch = errors.New("kill switch engaged")
+	// ErrSymbolNotAllowed is returned when the symbol of the order is not in the allowed symbols.
+	ErrSymbolNotAllowed = errors.New("symbol not allowed")
+	// ErrMaxOrderNotional is returned when the notional of the order is above the maximum.
+	ErrMaxOrderNotional = errors.New("max order notional exceeded")
+	// ErrMaxPosition is returned when a buy order would take the position of the asset above the maximum.
+	ErrMaxPosition = errors.New("max position exceeded")
+	// ErrMaxOpenOrders is returned when the maximum number of orders are open already.
+	ErrMaxOpenOrders = errors.New("max open orders reached")
+	// ErrDailyLossLimit is returned when the loss of the day reaches the limit.
+	ErrDailyLossLimit = errors.New("daily loss limit reached")
+	// ErrPriceCollar is returned when the limit price deviates from the last price by more than the collar.
+	ErrPriceCollar = errors.New("price outside collar")
+)
+
+// ErrUnknownSymbol is returned when the exchange does not list the symbol of an order.
+var ErrUnknownSymbol = errors.New("unknown symbol")
+
+var (
+	_ connector.Exchange = (*Engine)(nil)
+	_ connector.Trader   = (*Engine)(nil)
+)
+
+// Violation is the error rejecting an order which breaks a rule. Err is the error of the rule, e.g. ErrMaxOrderNotional.
+type Violation struct {
+	Err    error
+	Symbol string
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *Violation) Error() string {
+	return fmt.Sprintf("order on %s rejected by risk check: %s: %s", e.Symbol, e.Err, e.Reason)
+}
+
+// Unwrap returns the error of the rule.
+func (e *Violation) Unwrap() error {
+	return e.Err
+}
+
+// Exchange is an exchange able to trade.
+type Exchange interface {
+	connector.Exchange
+	connector.Trader
+}
+
+// Engine is an exchange placing the orders which pass the risk checks on the exchange it wraps. The other methods are the ones of
+// the wrapped exchange. The orders are checked and placed one at a time, so the open orders and positions they are checked against
+// include the previous ones. Failing to get the data of a check rejects the order too.
+type Engine struct {
+	Exchange
+	limits    Limits
+	kill      *KillSwitch
+	statePath string
+	l         *zap.Logger
+	now       func() time.Time
+	mu        sync.Mutex
+	state     *state
+	symbols   map[string]connector.Symbol
+}
+
+// Option configures the risk engine.
+type Option func(*Engine)
+
+// WithKillSwitch sets the kill switch blocking the orders. No kill switch is checked by default.
+func WithKillSwitch(k *KillSwitch) Option {
+	return func(e *Engine) {
+		e.kill = k
+	}
+}
+
+// WithStatePath persists the equity the daily loss is measured from in the file, so it is kept between runs.
+// It is only kept in memory by default.
+func WithStatePath(path string) Option {
+	return func(e *Engine) {
+		e.statePath = path
+	}
+}
+
+// WithLogger sets the logger of the rejected orders.
+func WithLogger(l *zap.Logger) Option {
+	return func(e *Engine) {
+		e.l = l
+	}
+}
+
+// WithClock sets the clock of the engine, which dates the daily loss.
+func WithClock(now func() time.Time) Option {
+	return func(e *Engine) {
+		e.now = now
+	}
+}
+
+// New creates a risk engine checking the orders placed on the exchange against the limits.
+func New(exchange Exchange, limits Limits, opts ...Option) *Engine {
+	e := &Engine{Exchange: exchange, limits: limits, kill: NewKillSwitch(""), l: zap.NewNop(), now: time.Now}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// NewFromConfig creates a risk engine with the limits, the kill switch and the state file of the risk configuration.
+func NewFromConfig(cfg *config.Config, exchange Exchange, opts ...Option) (*Engine, error) {
+	limits, err := NewLimits(cfg.Risk)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append([]Option{WithKillSwitch(NewKillSwitch(cfg.Risk.KillSwitchPath)), WithStatePath(cfg.Risk.StatePath)}, opts...)
+
+	return New(exchange, limits, opts...), nil
+}
+
+// PlaceOrder checks the order, and places it on the wrapped exchange when it passes the checks.
+func (e *Engine) PlaceOrder(ctx context.Context, req connector.OrderRequest) (*connector.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.check(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return e.Exchange.PlaceOrder(ctx, req)
+}
+
+// Check checks the order against the kill switch and the limits without placing it.
+func (e *Engine) Check(ctx context.Context, req connector.OrderRequest) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.check(ctx, req)
+}
+
+func (e *Engine) check(ctx context.Context, req connector.OrderRequest) error {
+	err := e.checkRules(ctx, req)
+
+	var v *Violation
+	if errors.As(err, &v) {
+		e.l.Warn("order rejected by risk check", zap.String("exchange", e.Name()), zap.String("rule", v.Err.Error()), zap.String("reason", v.Reason),
+			zap.String("symbol", req.Symbol), zap.String("side", string(req.Side)), zap.String("type", string(req.Type)),
+			zap.Stringer("price", req.Price), zap.Stringer("quantity", req.Quantity))
+	}
+
+	return err
+}
+
+func (e *Engine) checkRules(ctx context.Context, req connector.OrderRequest) error {
+	engaged, reason, err := e.kill.Engaged()
+	if err != nil {
+		return err
+	}
+
+	if engaged {
+		if reason == "" {
+			reason = "no reason given"
+		}
+
+		return &Violation{Err: ErrKillSwitch, Symbol: req.Symbol, Reason: fmt.Sprintf("%s exists, %s", e.kill.Path(), reason)}
+	}
+
+	if len(e.limits.AllowedSymbols) > 0 && !e.limits.AllowedSymbols[req.Symbol] {
+		return &Violation{Err: ErrSymbolNotAllowed, Symbol: req.Symbol, Reason: "the symbol is not in the allowed symbols"}
+	}
+
+	checks := []func(context.Context, connector.OrderRequest) error{e.checkPrice, e.checkOpenOrders, e.checkPosition, e.checkDailyLoss}
+	for _, check := range checks {
+		if err := check(ctx, req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkPrice checks the limit price against the price collar, and the notional of the order.
+// The notional of a market order is valued at the last price.
+func (e *Engine) checkPrice(ctx context.Context, req connector.OrderRequest) error {
+	collar := e.limits.PriceCollar.IsPositive() && req.Type == connector.OrderTypeLimit
+	notional := e.limits.MaxOrderNotional.IsPositive()
+
+	if !collar && !notional {
+		return nil
+	}
+
+	last, err := e.lastPrice(ctx, req.Symbol)
+	if err != nil {
+		return err
+	}
+
+	if collar {
+		if deviation := req.Price.Sub(last).Abs().Div(last); deviation.GreaterThan(e.limits.PriceCollar) {
+			return &Violation{Err: ErrPriceCollar, Symbol: req.Symbol, Reason: fmt.Sprintf("the price %s deviates by %s from the last price %s, above the collar of %s",
+				req.Price, deviation.Round(ratioPlaces), last, e.limits.PriceCollar)}
+		}
+	}
+
+	if !notional {
+		return nil
+	}
+
+	price := req.Price
+	if req.Type == connector.OrderTypeMarket {
+		price = last
+	}
+
+	value, err := e.notional(ctx, req.Symbol, price.Mul(req.Quantity))
+	if err != nil {
+		return err
+	}
+
+	if value.GreaterThan(e.limits.MaxOrderNotional) {
+		return &Violation{Err: ErrMaxOrderNotional, Symbol: req.Symbol, Reason: fmt.Sprintf("the notional %s %s is above the maximum of %s %s",
+			value.Round(ratioPlaces), e.limits.Quote, e.limits.MaxOrderNotional, e.limits.Quote)}
+	}
+
+	return nil
+}
+
+// lastPrice returns the price of the last trade of the symbol, or its ticker price when the exchange reports no trade,
+// e.g. the paper exchange matching the orders against recorded books.
+func (e *Engine) lastPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	trades, err := e.GetTrades(ctx, symbol, 1)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("error getting last trade of %s: %w", symbol, err)
+	}
+
+	if len(trades) > 0 && trades[len(trades)-1].Price.IsPositive() {
+		return trades[len(trades)-1].Price, nil
+	}
+
+	tickers, err := e.GetTickers(ctx, []string{symbol})
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("error getting %s price: %w", symbol, err)
+	}
+
+	for _, t := range tickers {
+		if t.Symbol == symbol && t.Price.IsPositive() {
+			return t.Price, nil
+		}
+	}
+
+	return decimal.Zero, fmt.Errorf("no last price of %s to check the order against", symbol)
+}
+
+// notional values the amount of the quote asset of the symbol in the quote asset of the limits.
+func (e *Engine) notional(ctx context.Context, symbol string, amount decimal.Decimal) (decimal.Decimal, error) {
+	s, err := e.symbol(ctx, symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	if s.QuoteAsset == e.limits.Quote {
+		return amount, nil
+	}
+
+	pair := s.QuoteAsset + e.limits.Quote
+
+	tickers, err := e.GetTickers(ctx, []string{pair})
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("error getting %s price: %w", pair, err)
+	}
+
+	for _, t := range tickers {
+		if t.Symbol == pair {
+			return amount.Mul(t.Price), nil
+		}
+	}
+
+	return decimal.Zero, fmt.Errorf("no %s price to value the notional of the order in %s", pair, e.limits.Quote)
+}
+
+func (e *Engine) checkOpenOrders(ctx context.Context, req connector.OrderRequest) error {
+	if e.limits.MaxOpenOrders == 0 {
+		return nil
+	}
+
+	orders, err := e.ListOpenOrders(ctx, "")
+	if err != nil {
+		return fmt.Errorf("error listing open orders: %w", err)
+	}
+
+	if len(orders) >= e.limits.MaxOpenOrders {
+		return &Violation{Err: ErrMaxOpenOrders, Symbol: req.Symbol, Reason: fmt.Sprintf("%d orders are open, the maximum is %d", len(orders), e.limits.MaxOpenOrders)}
+	}
+
+	return nil
+}
+
+// checkPosition checks the position of the base asset a buy order would take, including the balance and the remaining quantity
+// of the open buy orders of the symbol.
+func (e *Engine) checkPosition(ctx context.Context, req connector.OrderRequest) error {
+	if len(e.limits.MaxPositions) == 0 || req.Side != connector.SideBuy {
+		return nil
+	}
+
+	s, err := e.symbol(ctx, req.Symbol)
+	if err != nil {
+		return err
+	}
+
+	maxPosition, ok := e.limits.MaxPositions[s.BaseAsset]
+	if !ok {
+		return nil
+	}
+
+	balances, err := e.GetBalances(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting balances: %w", err)
+	}
+
+	position := req.Quantity
+
+	for _, b := range balances {
+		if b.Asset == s.BaseAsset {
+			position = position.Add(b.Total())
+		}
+	}
+
+	orders, err := e.ListOpenOrders(ctx, req.Symbol)
+	if err != nil {
+		return fmt.Errorf("error listing open orders: %w", err)
+	}
+
+	for _, o := range orders {
+		if o.Side == connector.SideBuy {
+			position = position.Add(o.Quantity.Sub(o.ExecutedQuantity))
+		}
+	}
+
+	if position.GreaterThan(maxPosition) {
+		return &Violation{Err: ErrMaxPosition, Symbol: req.Symbol, Reason: fmt.Sprintf("the position of %s would be %s, above the maximum of %s",
+			s.BaseAsset, position, maxPosition)}
+	}
+
+	return nil
+}
+
+// checkDailyLoss checks the loss of the equity of the exchange since the first order checked on the UTC day.
+// The equity is the value of the balances in the quote asset of the limits, so deposits and withdrawals count as profits and losses.
+func (e *Engine) checkDailyLoss(ctx context.Context, req connector.OrderRequest) error {
+	if !e.limits.DailyLossLimit.IsPositive() {
+		return nil
+	}
+
+	p, err := portfolio.NewService(e.Exchange).Build(ctx, e.limits.Quote)
+	if err != nil {
+		return fmt.Errorf("error valuing balances: %w", err)
+	}
+
+	start, err := e.startEquity(p.Total)
+	if err != nil {
+		return err
+	}
+
+	if loss := start.Sub(p.Total); loss.GreaterThanOrEqual(e.limits.DailyLossLimit) {
+		return &Violation{Err: ErrDailyLossLimit, Symbol: req.Symbol, Reason: fmt.Sprintf("the equity fell from %s to %s %s today, reaching the limit of %s %s",
+			start.Round(ratioPlaces), p.Total.Round(ratioPlaces), e.limits.Quote, e.limits.DailyLossLimit, e.limits.Quote)}
+	}
+
+	return nil
+}
+
+// startEquity returns the equity of the exchange at the first order checked on the UTC day, which is the given equity
+// for the first order.
+func (e *Engine) startEquity(equity decimal.Decimal) (decimal.Decimal, error) {
+	if e.state == nil || e.statePath != "" {
+		s, err := loadState(e.statePath)
+		if err != nil {
+			return decimal.Zero, err
+		}
+
+		e.state = s
+	}
+
+	day := e.now().UTC().Format(time.DateOnly)
+	if e.state.Day != day {
+		e.state = newState(day)
+	}
+
+	if start, ok := e.state.Equity[e.Name()]; ok {
+		return start, nil
+	}
+
+	e.state.Equity[e.Name()] = equity
+	if err := e.state.save(e.statePath); err != nil {
+		return decimal.Zero, err
+	}
+
+	return equity, nil
+}
+
+func (e *Engine) symbol(ctx context.Context, name string) (connector.Symbol, error) {
+	if e.symbols == nil {
+		symbols, err := e.GetSymbols(ctx)
+		if err != nil {
+			return connector.Symbol{}, fmt.Errorf("error getting symbols: %w", err)
+		}
+
+		e.symbols = make(map[string]connector.Symbol, len(symbols))
+		for _, s := range symbols {
+			e.symbols[s.Name] = s
+		}
+	}
+
+	s, ok := e.symbols[name]
+	if !ok {
+		return connector.Symbol{}, fmt.Errorf("%w: %s", ErrUnknownSymbol, name)
+	}
+
+	return s, nil
+}
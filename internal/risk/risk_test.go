@@ -0,0 +1,285 @@
+package risk_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/risk"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+// fakeExchange lists BTCUSDT and ETHBTC, whose tickers are at 100 and 0.05. Only BTCUSDT has trades.
+type fakeExchange struct {
+	balances []connector.Balance
+	open     []connector.Order
+	placed   []connector.OrderRequest
+	err      error
+}
+
+func (e *fakeExchange) Name() string {
+	return "fake"
+}
+
+func (e *fakeExchange) GetBalances(_ context.Context) ([]connector.Balance, error) {
+	return e.balances, e.err
+}
+
+func (e *fakeExchange) GetTickers(_ context.Context, _ []string) ([]connector.Ticker, error) {
+	return []connector.Ticker{{Symbol: "BTCUSDT", Price: d("100")}, {Symbol: "ETHBTC", Price: d("0.05")}}, e.err
+}
+
+func (e *fakeExchange) GetOrderBook(_ context.Context, symbol string, _ int) (*connector.OrderBook, error) {
+	return &connector.OrderBook{Symbol: symbol}, nil
+}
+
+func (e *fakeExchange) GetTrades(_ context.Context, symbol string, _ int) ([]connector.Trade, error) {
+	if symbol != "BTCUSDT" || e.err != nil {
+		return nil, e.err
+	}
+
+	return []connector.Trade{{Symbol: symbol, Price: d("100"), Quantity: d("1")}}, nil
+}
+
+func (e *fakeExchange) GetSymbols(_ context.Context) ([]connector.Symbol, error) {
+	return []connector.Symbol{{Name: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT"}, {Name: "ETHBTC", BaseAsset: "ETH", QuoteAsset: "BTC"}}, e.err
+}
+
+func (e *fakeExchange) PlaceOrder(_ context.Context, req connector.OrderRequest) (*connector.Order, error) {
+	e.placed = append(e.placed, req)
+
+	return &connector.Order{ID: int64(len(e.placed)), Symbol: req.Symbol, Side: req.Side, Type: req.Type, Status: connector.OrderStatusNew}, nil
+}
+
+func (e *fakeExchange) CancelOrder(_ context.Context, symbol string, orderID int64) (*connector.Order, error) {
+	return &connector.Order{ID: orderID, Symbol: symbol, Status: connector.OrderStatusCanceled}, nil
+}
+
+func (e *fakeExchange) GetOrder(_ context.Context, symbol string, orderID int64) (*connector.Order, error) {
+	return &connector.Order{ID: orderID, Symbol: symbol}, nil
+}
+
+func (e *fakeExchange) ListOpenOrders(_ context.Context, symbol string) ([]connector.Order, error) {
+	var res []connector.Order
+
+	for _, o := range e.open {
+		if symbol == "" || o.Symbol == symbol {
+			res = append(res, o)
+		}
+	}
+
+	return res, e.err
+}
+
+func limitBuy(symbol, price, quantity string) connector.OrderRequest {
+	return connector.OrderRequest{Symbol: symbol, Side: connector.SideBuy, Type: connector.OrderTypeLimit, Price: d(price), Quantity: d(quantity)}
+}
+
+func TestEngine_PlaceOrder(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		limits   risk.Limits
+		balances []connector.Balance
+		open     []connector.Order
+		req      connector.OrderRequest
+	}
+
+	tests := map[string]struct {
+		args args
+		want error
+	}{
+		"no limits": {
+			args: args{req: limitBuy("BTCUSDT", "1000", "100")},
+		},
+		"allowed symbol": {
+			args: args{limits: risk.Limits{AllowedSymbols: map[string]bool{"BTCUSDT": true}}, req: limitBuy("BTCUSDT", "100", "1")},
+		},
+		"symbol not allowed": {
+			args: args{limits: risk.Limits{AllowedSymbols: map[string]bool{"BTCUSDT": true}}, req: limitBuy("ETHBTC", "0.05", "1")},
+			want: risk.ErrSymbolNotAllowed,
+		},
+		"notional under the maximum": {
+			args: args{limits: risk.Limits{MaxOrderNotional: d("500"), Quote: "USDT"}, req: limitBuy("BTCUSDT", "99", "5")},
+		},
+		"notional above the maximum": {
+			args: args{limits: risk.Limits{MaxOrderNotional: d("500"), Quote: "USDT"}, req: limitBuy("BTCUSDT", "101", "5")},
+			want: risk.ErrMaxOrderNotional,
+		},
+		"market notional valued at the last trade": {
+			args: args{
+				limits: risk.Limits{MaxOrderNotional: d("500"), Quote: "USDT"},
+				req:    connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideSell, Type: connector.OrderTypeMarket, Quantity: d("5.01")},
+			},
+			want: risk.ErrMaxOrderNotional,
+		},
+		"notional valued in the quote asset of the limits": {
+			// 0.05 BTC * 100 = 5 USDT per ETH.
+			args: args{limits: risk.Limits{MaxOrderNotional: d("500"), Quote: "USDT"}, req: limitBuy("ETHBTC", "0.05", "101")},
+			want: risk.ErrMaxOrderNotional,
+		},
+		"price inside the collar": {
+			args: args{limits: risk.Limits{PriceCollar: d("0.05")}, req: limitBuy("BTCUSDT", "95", "1")},
+		},
+		"price outside the collar": {
+			args: args{limits: risk.Limits{PriceCollar: d("0.05")}, req: limitBuy("BTCUSDT", "94.9", "1")},
+			want: risk.ErrPriceCollar,
+		},
+		"ticker price without trades": {
+			args: args{limits: risk.Limits{PriceCollar: d("0.05")}, req: limitBuy("ETHBTC", "0.06", "1")},
+			want: risk.ErrPriceCollar,
+		},
+		"market order has no collar": {
+			args: args{
+				limits: risk.Limits{PriceCollar: d("0.05")},
+				req:    connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideBuy, Type: connector.OrderTypeMarket, Quantity: d("1")},
+			},
+		},
+		"open orders under the maximum": {
+			args: args{limits: risk.Limits{MaxOpenOrders: 2}, open: []connector.Order{{Symbol: "ETHBTC"}}, req: limitBuy("BTCUSDT", "100", "1")},
+		},
+		"max open orders reached": {
+			args: args{limits: risk.Limits{MaxOpenOrders: 2}, open: []connector.Order{{Symbol: "ETHBTC"}, {Symbol: "BTCUSDT"}}, req: limitBuy("BTCUSDT", "100", "1")},
+			want: risk.ErrMaxOpenOrders,
+		},
+		"position up to the maximum": {
+			args: args{
+				limits:   risk.Limits{MaxPositions: map[string]decimal.Decimal{"BTC": d("1")}},
+				balances: []connector.Balance{{Asset: "BTC", Free: d("0.2"), Locked: d("0.1")}},
+				open:     []connector.Order{{Symbol: "BTCUSDT", Side: connector.SideBuy, Quantity: d("0.5"), ExecutedQuantity: d("0.2")}},
+				req:      limitBuy("BTCUSDT", "100", "0.4"),
+			},
+		},
+		"position above the maximum": {
+			args: args{
+				limits:   risk.Limits{MaxPositions: map[string]decimal.Decimal{"BTC": d("1")}},
+				balances: []connector.Balance{{Asset: "BTC", Free: d("0.2"), Locked: d("0.1")}},
+				open:     []connector.Order{{Symbol: "BTCUSDT", Side: connector.SideBuy, Quantity: d("0.5"), ExecutedQuantity: d("0.2")}},
+				req:      limitBuy("BTCUSDT", "100", "0.41"),
+			},
+			want: risk.ErrMaxPosition,
+		},
+		"sell orders reduce the position": {
+			args: args{
+				limits:   risk.Limits{MaxPositions: map[string]decimal.Decimal{"BTC": d("1")}},
+				balances: []connector.Balance{{Asset: "BTC", Free: d("5")}},
+				req:      connector.OrderRequest{Symbol: "BTCUSDT", Side: connector.SideSell, Type: connector.OrderTypeLimit, Price: d("100"), Quantity: d("1")},
+			},
+		},
+		"position of an unlisted asset": {
+			args: args{
+				limits:   risk.Limits{MaxPositions: map[string]decimal.Decimal{"BTC": d("1")}},
+				balances: []connector.Balance{{Asset: "ETH", Free: d("5")}},
+				req:      limitBuy("ETHBTC", "0.05", "10"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			e := &fakeExchange{balances: tt.args.balances, open: tt.args.open}
+
+			order, err := risk.New(e, tt.args.limits).PlaceOrder(context.Background(), tt.args.req)
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+
+				var v *risk.Violation
+				assert.True(t, errors.As(err, &v))
+				assert.Equal(t, tt.args.req.Symbol, v.Symbol)
+				assert.Empty(t, e.placed, "a rejected order is not placed")
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.args.req.Symbol, order.Symbol)
+			assert.Equal(t, []connector.OrderRequest{tt.args.req}, e.placed)
+		})
+	}
+}
+
+func TestEngine_KillSwitch(t *testing.T) {
+	t.Parallel()
+
+	k := risk.NewKillSwitch(filepath.Join(t.TempDir(), "kill"))
+	e := &fakeExchange{}
+	g := risk.New(e, risk.Limits{}, risk.WithKillSwitch(k))
+	req := limitBuy("BTCUSDT", "100", "1")
+
+	_, err := g.PlaceOrder(context.Background(), req)
+	assert.NoError(t, err)
+
+	assert.NoError(t, k.Engage("maintenance", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+	_, err = g.PlaceOrder(context.Background(), req)
+	assert.ErrorIs(t, err, risk.ErrKillSwitch)
+	assert.ErrorContains(t, err, "engaged at 2024-01-02T03:04:05Z: maintenance")
+	assert.Len(t, e.placed, 1)
+
+	assert.NoError(t, k.Release())
+
+	_, err = g.PlaceOrder(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Len(t, e.placed, 2)
+}
+
+func TestEngine_DailyLoss(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "risk.json")
+	now := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	e := &fakeExchange{balances: []connector.Balance{{Asset: "USDT", Free: d("1000")}}}
+	newEngine := func() *risk.Engine {
+		return risk.New(e, risk.Limits{DailyLossLimit: d("100"), Quote: "USDT"}, risk.WithStatePath(path), risk.WithClock(func() time.Time { return now }))
+	}
+	req := limitBuy("BTCUSDT", "100", "1")
+
+	_, err := newEngine().PlaceOrder(context.Background(), req)
+	assert.NoError(t, err, "the equity of the first order of the day is the start equity")
+
+	e.balances = []connector.Balance{{Asset: "USDT", Free: d("900.01")}}
+
+	_, err = newEngine().PlaceOrder(context.Background(), req)
+	assert.NoError(t, err)
+
+	e.balances = []connector.Balance{{Asset: "USDT", Free: d("800")}, {Asset: "BTC", Free: d("0.99")}}
+
+	_, err = newEngine().PlaceOrder(context.Background(), req)
+	assert.ErrorIs(t, err, risk.ErrDailyLossLimit, "the start equity is persisted between runs")
+
+	now = now.Add(24 * time.Hour)
+
+	_, err = newEngine().PlaceOrder(context.Background(), req)
+	assert.NoError(t, err, "the loss is measured from the first order of the new day")
+}
+
+func TestEngine_CheckErrors(t *testing.T) {
+	t.Parallel()
+
+	e := &fakeExchange{err: errors.New("network error")}
+	g := risk.New(e, risk.Limits{MaxOpenOrders: 1})
+
+	err := g.Check(context.Background(), limitBuy("BTCUSDT", "100", "1"))
+	assert.EqualError(t, err, "error listing open orders: network error")
+
+	var v *risk.Violation
+	assert.False(t, errors.As(err, &v))
+
+	g = risk.New(&fakeExchange{}, risk.Limits{MaxOrderNotional: d("1"), Quote: "USDT"})
+
+	err = g.Check(context.Background(), limitBuy("XRPUSDT", "1", "1"))
+	assert.EqualError(t, err, "no last price of XRPUSDT to check the order against")
+}
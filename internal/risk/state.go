@@ -0,0 +1,73 @@
+package risk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/shopspring/decimal"
+)
+
+// stateFileMode is the mode of the state file, which is only read by its owner.
+const stateFileMode = 0o600
+
+// state is the persisted state of the risk engine: the equity of every exchange at the first order checked on Day, a UTC date.
+type state struct {
+	Day    string                     `json:"day"`
+	Equity map[string]decimal.Decimal `json:"equity"`
+}
+
+func newState(day string) *state {
+	return &state{Day: day, Equity: make(map[string]decimal.Decimal)}
+}
+
+// loadState loads the state persisted at the path, or creates an empty state when the path is empty or the file does not exist yet.
+func loadState(path string) (*state, error) {
+	if path == "" {
+		return newState(""), nil
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return newState(""), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading risk state: %w", err)
+	}
+
+	s := newState("")
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("error decoding risk state %s: %w", path, err)
+	}
+
+	if s.Equity == nil {
+		s.Equity = make(map[string]decimal.Decimal)
+	}
+
+	return s, nil
+}
+
+// save persists the state at the path, unless it is empty. The file is replaced at once, so a failed save keeps the previous state.
+func (s *state) save(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding risk state: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, stateFileMode); err != nil {
+		return fmt.Errorf("error writing risk state: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error writing risk state: %w", err)
+	}
+
+	return nil
+}
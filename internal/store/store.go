@@ -0,0 +1,296 @@
+// Package store provides an embedded candle store backed by bbolt. Candles are keyed by exchange, symbol, interval and open time,
+// so writing the same candle twice overwrites it, and the store can report the intervals missing from a series.
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// openTimeout is how long Open waits for the lock of a database already opened by another process.
+const openTimeout = time.Second
+
+// seriesSeparator separates the exchange, symbol and interval in the name of a series bucket.
+const seriesSeparator = "/"
+
+// seriesFields is the number of fields of a series name, the exchange, symbol and interval.
+const seriesFields = 3
+
+// keySize is the size of a candle key, the open time in unix milliseconds.
+const keySize = 8
+
+// ErrInvalidSeries is returned when a series has an empty field or a field containing the separator.
+var ErrInvalidSeries = errors.New("invalid candle series")
+
+// candlesBucket is the top level bucket holding one nested bucket per series.
+func candlesBucket() []byte {
+	return []byte("candles")
+}
+
+// Series identifies the candles of a symbol and interval on an exchange.
+type Series struct {
+	Exchange string
+	Symbol   string
+	Interval string
+}
+
+// String returns the name of the series, exchange/symbol/interval.
+func (s Series) String() string {
+	return strings.Join([]string{s.Exchange, s.Symbol, s.Interval}, seriesSeparator)
+}
+
+func (s Series) validate() error {
+	for _, f := range []string{s.Exchange, s.Symbol, s.Interval} {
+		if f == "" || strings.Contains(f, seriesSeparator) {
+			return fmt.Errorf("%w: %q", ErrInvalidSeries, s.String())
+		}
+	}
+
+	return nil
+}
+
+// Gap is a range of missing candles opened in [Start, End).
+type Gap struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Missing returns the number of candles of the given interval missing in the gap.
+func (g Gap) Missing(interval time.Duration) int {
+	return int(g.End.Sub(g.Start) / interval)
+}
+
+// record is the stored representation of a candle. The series and open time are part of the key.
+type record struct {
+	CloseTime   time.Time       `json:"close_time"`
+	Open        decimal.Decimal `json:"open"`
+	High        decimal.Decimal `json:"high"`
+	Low         decimal.Decimal `json:"low"`
+	Close       decimal.Decimal `json:"close"`
+	Volume      decimal.Decimal `json:"volume"`
+	QuoteVolume decimal.Decimal `json:"quote_volume"`
+	Trades      uint64          `json:"trades"`
+}
+
+// Store is an embedded candle store.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens the store at path, creating the file when it does not exist.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("error opening candle store %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(candlesBucket()); err != nil {
+			return fmt.Errorf("error creating candles bucket: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("error initializing candle store %s: %w", path, err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the store.
+func (s *Store) Close() error {
+	if err := s.db.Close(); err != nil {
+		return fmt.Errorf("error closing candle store: %w", err)
+	}
+
+	return nil
+}
+
+// Upsert writes the candles of the exchange in a single transaction, replacing the candles with the same series and open time.
+func (s *Store) Upsert(exchange string, candles []connector.Candle) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket(candlesBucket())
+
+		for _, c := range candles {
+			series := Series{Exchange: exchange, Symbol: c.Symbol, Interval: c.Interval}
+			if err := series.validate(); err != nil {
+				return err
+			}
+
+			b, err := root.CreateBucketIfNotExists([]byte(series.String()))
+			if err != nil {
+				return fmt.Errorf("error creating bucket of %s: %w", series, err)
+			}
+
+			v, err := json.Marshal(record{
+				CloseTime: c.CloseTime.UTC(), Open: c.Open, High: c.High, Low: c.Low, Close: c.Close,
+				Volume: c.Volume, QuoteVolume: c.QuoteVolume, Trades: c.Trades,
+			})
+			if err != nil {
+				return fmt.Errorf("error encoding candle: %w", err)
+			}
+
+			if err := b.Put(encodeTime(c.OpenTime), v); err != nil {
+				return fmt.Errorf("error writing candle of %s: %w", series, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error upserting candles: %w", err)
+	}
+
+	return nil
+}
+
+// Candles returns the candles of the series opened in [start, end), ordered by open time. Zero times leave the range open.
+func (s *Store) Candles(series Series, start, end time.Time) ([]connector.Candle, error) {
+	var candles []connector.Candle
+
+	err := s.scan(series, start, end, func(openTime time.Time, v []byte) error {
+		var r record
+		if err := json.Unmarshal(v, &r); err != nil {
+			return fmt.Errorf("error decoding candle of %s opened at %s: %w", series, openTime.Format(time.RFC3339), err)
+		}
+
+		candles = append(candles, connector.Candle{
+			Symbol: series.Symbol, Interval: series.Interval, OpenTime: openTime, CloseTime: r.CloseTime,
+			Open: r.Open, High: r.High, Low: r.Low, Close: r.Close, Volume: r.Volume, QuoteVolume: r.QuoteVolume, Trades: r.Trades,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return candles, nil
+}
+
+// Gaps returns the ranges of candles of the series missing in [start, end), given the duration of its interval.
+// A zero start or end is replaced by the first stored open time or the end of the last stored candle, so only inner gaps are reported.
+func (s *Store) Gaps(series Series, interval time.Duration, start, end time.Time) ([]Gap, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid interval %s", interval)
+	}
+
+	var (
+		gaps     []Gap
+		expected = start
+		last     time.Time
+	)
+
+	err := s.scan(series, start, end, func(openTime time.Time, _ []byte) error {
+		if expected.IsZero() {
+			expected = openTime
+		}
+
+		if openTime.After(expected) {
+			gaps = append(gaps, Gap{Start: expected, End: openTime})
+		}
+
+		expected, last = openTime.Add(interval), openTime
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if end.IsZero() && !last.IsZero() {
+		end = last.Add(interval)
+	}
+
+	if !expected.IsZero() && expected.Before(end) {
+		gaps = append(gaps, Gap{Start: expected, End: end})
+	}
+
+	return gaps, nil
+}
+
+// ListSeries lists the series holding candles, sorted by name.
+func (s *Store) ListSeries() ([]Series, error) {
+	var series []Series
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(candlesBucket()).ForEachBucket(func(k []byte) error {
+			parts := strings.Split(string(k), seriesSeparator)
+			if len(parts) == seriesFields {
+				series = append(series, Series{Exchange: parts[0], Symbol: parts[1], Interval: parts[2]})
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing candle series: %w", err)
+	}
+
+	sort.Slice(series, func(i, j int) bool { return series[i].String() < series[j].String() })
+
+	return series, nil
+}
+
+// scan calls fn with the open time and the value of every candle of the series opened in [start, end), in order.
+func (s *Store) scan(series Series, start, end time.Time, fn func(time.Time, []byte) error) error {
+	if err := series.validate(); err != nil {
+		return err
+	}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(candlesBucket()).Bucket([]byte(series.String()))
+		if b == nil {
+			return nil
+		}
+
+		c := b.Cursor()
+
+		k, v := c.First()
+		if !start.IsZero() {
+			k, v = c.Seek(encodeTime(start))
+		}
+
+		for ; k != nil; k, v = c.Next() {
+			openTime := decodeTime(k)
+			if !end.IsZero() && !openTime.Before(end) {
+				break
+			}
+
+			if err := fn(openTime, v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error reading candles of %s: %w", series, err)
+	}
+
+	return nil
+}
+
+// encodeTime encodes a time as big endian unix milliseconds, so that keys sort by time.
+func encodeTime(t time.Time) []byte {
+	k := make([]byte, keySize)
+	binary.BigEndian.PutUint64(k, uint64(t.UnixMilli()))
+
+	return k
+}
+
+func decodeTime(k []byte) time.Time {
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(k))).UTC()
+}
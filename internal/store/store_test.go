@@ -0,0 +1,184 @@
+package store_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/store"
+)
+
+func btcusdt() store.Series {
+	return store.Series{Exchange: "binance", Symbol: "BTCUSDT", Interval: "1h"}
+}
+
+func newStore(t *testing.T) *store.Store {
+	t.Helper()
+
+	s, err := store.Open(filepath.Join(t.TempDir(), "candles.db"))
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, s.Close())
+	})
+
+	return s
+}
+
+// hours returns one hour candles of BTCUSDT opened at the given hours of 2024-01-01.
+func hours(closePrice string, openHours ...int) []connector.Candle {
+	res := make([]connector.Candle, 0, len(openHours))
+
+	for _, h := range openHours {
+		open := at(h)
+		res = append(res, connector.Candle{
+			Symbol: "BTCUSDT", Interval: "1h", OpenTime: open, CloseTime: open.Add(time.Hour - time.Millisecond),
+			Open: decimal.RequireFromString("100"), High: decimal.RequireFromString("110"), Low: decimal.RequireFromString("90"),
+			Close: decimal.RequireFromString(closePrice), Volume: decimal.RequireFromString("1.5"), QuoteVolume: decimal.RequireFromString("150"), Trades: 7,
+		})
+	}
+
+	return res
+}
+
+func at(hour int) time.Time {
+	return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(hour) * time.Hour)
+}
+
+func TestStore_Upsert(t *testing.T) {
+	s := newStore(t)
+
+	assert.NoError(t, s.Upsert("binance", hours("105", 0, 1, 2)))
+	assert.NoError(t, s.Upsert("binance", hours("106", 1)))
+
+	candles, err := s.Candles(btcusdt(), time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, candles, 3)
+	assert.Equal(t, hours("105", 0)[0], candles[0])
+	assert.Equal(t, "106", candles[1].Close.String())
+
+	candles, err = s.Candles(btcusdt(), at(1), at(2))
+	assert.NoError(t, err)
+	assert.Equal(t, hours("106", 1), candles)
+
+	candles, err = s.Candles(store.Series{Exchange: "binance", Symbol: "ETHUSDT", Interval: "1h"}, time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Empty(t, candles)
+
+	err = s.Upsert("binance", []connector.Candle{{Symbol: "BTC/USDT", Interval: "1h"}})
+	assert.EqualError(t, err, `error upserting candles: invalid candle series: "binance/BTC/USDT/1h"`)
+	assert.ErrorIs(t, err, store.ErrInvalidSeries)
+}
+
+func TestStore_Gaps(t *testing.T) {
+	s := newStore(t)
+
+	assert.NoError(t, s.Upsert("binance", hours("105", 2, 3, 5, 8, 9)))
+
+	type args struct {
+		start time.Time
+		end   time.Time
+	}
+
+	type want struct {
+		gaps    []store.Gap
+		missing []int
+	}
+
+	tests := map[string]struct {
+		args args
+		want want
+	}{
+		"inner gaps": {
+			want: want{gaps: []store.Gap{{Start: at(4), End: at(5)}, {Start: at(6), End: at(8)}}, missing: []int{1, 2}},
+		},
+		"range wider than the stored candles": {
+			args: args{start: at(0), end: at(12)},
+			want: want{
+				gaps:    []store.Gap{{Start: at(0), End: at(2)}, {Start: at(4), End: at(5)}, {Start: at(6), End: at(8)}, {Start: at(10), End: at(12)}},
+				missing: []int{2, 1, 2, 2},
+			},
+		},
+		"range within the stored candles": {
+			args: args{start: at(3), end: at(6)},
+			want: want{gaps: []store.Gap{{Start: at(4), End: at(5)}}, missing: []int{1}},
+		},
+		"no gap": {
+			args: args{start: at(8), end: at(10)},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gaps, err := s.Gaps(btcusdt(), time.Hour, tt.args.start, tt.args.end)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.gaps, gaps)
+
+			for i, g := range gaps {
+				assert.Equal(t, tt.want.missing[i], g.Missing(time.Hour))
+			}
+		})
+	}
+}
+
+func TestStore_Gaps_EmptySeries(t *testing.T) {
+	s := newStore(t)
+
+	gaps, err := s.Gaps(btcusdt(), time.Hour, at(0), at(3))
+	assert.NoError(t, err)
+	assert.Equal(t, []store.Gap{{Start: at(0), End: at(3)}}, gaps)
+
+	gaps, err = s.Gaps(btcusdt(), time.Hour, time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Empty(t, gaps)
+
+	_, err = s.Gaps(btcusdt(), 0, time.Time{}, time.Time{})
+	assert.EqualError(t, err, "invalid interval 0s")
+
+	_, err = s.Gaps(store.Series{Exchange: "binance"}, time.Hour, time.Time{}, time.Time{})
+	assert.True(t, errors.Is(err, store.ErrInvalidSeries))
+}
+
+func TestStore_ListSeries(t *testing.T) {
+	s := newStore(t)
+
+	eth := hours("105", 0)
+	eth[0].Symbol = "ETHUSDT"
+
+	assert.NoError(t, s.Upsert("binance", append(hours("105", 0), eth...)))
+	assert.NoError(t, s.Upsert("kraken", hours("105", 0)))
+
+	series, err := s.ListSeries()
+	assert.NoError(t, err)
+	assert.Equal(t, []store.Series{
+		{Exchange: "binance", Symbol: "BTCUSDT", Interval: "1h"},
+		{Exchange: "binance", Symbol: "ETHUSDT", Interval: "1h"},
+		{Exchange: "kraken", Symbol: "BTCUSDT", Interval: "1h"},
+	}, series)
+	assert.Equal(t, "binance/BTCUSDT/1h", series[0].String())
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "candles.db")
+
+	s, err := store.Open(path)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Upsert("binance", hours("105", 0)))
+	assert.NoError(t, s.Close())
+
+	s, err = store.Open(path)
+	assert.NoError(t, err)
+
+	candles, err := s.Candles(btcusdt(), time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, candles, 1)
+	assert.NoError(t, s.Close())
+
+	_, err = store.Open(t.TempDir())
+	assert.Error(t, err)
+}
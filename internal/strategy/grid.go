@@ -0,0 +1,185 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// GridName is the name of the grid strategy.
+const GridName = "grid"
+
+// Number of price levels of the grid, which has at least a lowest and a highest level.
+const (
+	defaultGridLevels = 10
+	minGridLevels     = 2
+)
+
+// defaultGridFee is the default fee of the orders, as a fraction of the traded amount.
+var defaultGridFee = decimal.RequireFromString("0.001")
+
+// gridOrder is an order of the grid at the price level of the index.
+type gridOrder struct {
+	level int
+	side  connector.Side
+}
+
+// Grid is the grid trading strategy. Its price levels are evenly spaced from the lower to the upper price. On the first candle, it places
+// a limit buy at every level below the close price it can pay for, then a limit sell one level above every filled buy, and a limit buy
+// one level below every filled sell, so it profits from the price oscillating across the levels.
+type Grid struct {
+	Base
+	params   Params
+	levels   []decimal.Decimal
+	quantity decimal.Decimal
+	fee      decimal.Decimal
+	orders   map[int64]gridOrder
+	started  bool
+}
+
+var _ Strategy = (*Grid)(nil)
+
+// NewGrid creates a grid strategy from the parameters lower and upper, the prices of the lowest and highest levels, levels,
+// the number of levels defaulting to 10, quantity, the quantity of every order in the base asset, and fee, the fee of the orders
+// as a fraction of the traded amount defaulting to 0.001, which a buy is paid for with.
+func NewGrid(p Params) (Strategy, error) {
+	lower, err := p.Decimal("lower", decimal.Zero)
+	if err != nil {
+		return nil, err
+	}
+
+	upper, err := p.Decimal("upper", decimal.Zero)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := p.Int("levels", defaultGridLevels)
+	if err != nil {
+		return nil, err
+	}
+
+	qty, err := p.Decimal("quantity", decimal.Zero)
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := p.Decimal("fee", defaultGridFee)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lower.IsPositive() || upper.LessThanOrEqual(lower) {
+		return nil, fmt.Errorf("%w: the prices must be 0 < lower < upper, got %s and %s", ErrInvalidParams, lower, upper)
+	}
+
+	if n < minGridLevels {
+		return nil, fmt.Errorf("%w: a grid has at least %d levels, got %d", ErrInvalidParams, minGridLevels, n)
+	}
+
+	if !qty.IsPositive() {
+		return nil, fmt.Errorf("%w: the quantity must be positive, got %s", ErrInvalidParams, qty)
+	}
+
+	if fee.IsNegative() || fee.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return nil, fmt.Errorf("%w: the fee must be in [0, 1), got %s", ErrInvalidParams, fee)
+	}
+
+	step := upper.Sub(lower).Div(decimal.NewFromInt(int64(n - 1)))
+	levels := make([]decimal.Decimal, 0, n)
+
+	for i := 0; i < n; i++ {
+		levels = append(levels, lower.Add(step.Mul(decimal.NewFromInt(int64(i)))))
+	}
+
+	return &Grid{params: p, levels: levels, quantity: qty, fee: fee, orders: make(map[int64]gridOrder)}, nil
+}
+
+// OnCandle places the buys of the levels below the close price of the first candle, from the lowest level.
+func (g *Grid) OnCandle(ctx context.Context, a Account, c connector.Candle) error {
+	if g.started || c.Symbol != g.params.Symbol {
+		return nil
+	}
+
+	g.started = true
+
+	for i, price := range g.levels {
+		if price.GreaterThanOrEqual(c.Close) {
+			break
+		}
+
+		if err := g.buy(ctx, a, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OnOrderUpdate replaces a filled order of the grid by the opposite order on the next level.
+func (g *Grid) OnOrderUpdate(ctx context.Context, a Account, o connector.Order) error {
+	placed, ok := g.orders[o.ID]
+	if !ok || o.Status == connector.OrderStatusNew || o.Status == connector.OrderStatusPartiallyFilled {
+		return nil
+	}
+
+	delete(g.orders, o.ID)
+
+	if o.Status != connector.OrderStatusFilled {
+		return nil
+	}
+
+	if placed.side == connector.SideBuy && placed.level+1 < len(g.levels) {
+		// The fee of a buy may be charged in the base asset, so the sell is limited to the free balance.
+		free, err := freeBalance(ctx, a, g.params.Base)
+		if err != nil {
+			return err
+		}
+
+		if qty := decimal.Min(o.ExecutedQuantity, free); qty.IsPositive() {
+			return g.place(ctx, a, placed.level+1, connector.SideSell, qty)
+		}
+	}
+
+	if placed.side == connector.SideSell && placed.level > 0 {
+		return g.buy(ctx, a, placed.level-1)
+	}
+
+	return nil
+}
+
+// buy places a buy at the level, unless the free quote balance cannot pay for it with its fee.
+func (g *Grid) buy(ctx context.Context, a Account, level int) error {
+	quote, err := freeBalance(ctx, a, g.params.Quote)
+	if err != nil {
+		return err
+	}
+
+	if quote.LessThan(g.levels[level].Mul(g.quantity).Mul(decimal.NewFromInt(1).Add(g.fee))) {
+		return nil
+	}
+
+	return g.place(ctx, a, level, connector.SideBuy, g.quantity)
+}
+
+func (g *Grid) place(ctx context.Context, a Account, level int, side connector.Side, qty decimal.Decimal) error {
+	req := connector.OrderRequest{
+		Symbol: g.params.Symbol, Side: side, Type: connector.OrderTypeLimit, Quantity: qty, Price: g.levels[level], TimeInForce: "GTC",
+	}
+
+	o, err := a.PlaceOrder(ctx, req)
+	if err != nil {
+		return fmt.Errorf("error placing %s order at %s: %w", side, g.levels[level], err)
+	}
+
+	g.orders[o.ID] = gridOrder{level: level, side: side}
+
+	// An order filled when it is placed is not reported by the runner, which only tracks the open orders.
+	if !open(*o) {
+		return g.OnOrderUpdate(ctx, a, *o)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package strategy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/backtest"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/strategy"
+)
+
+func TestNewGrid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		values  map[string]string
+		wantErr bool
+	}{
+		"Grid":                {values: map[string]string{"lower": "90", "upper": "110", "levels": "5", "quantity": "1"}},
+		"Upper below lower":   {values: map[string]string{"lower": "110", "upper": "90", "quantity": "1"}, wantErr: true},
+		"Single level":        {values: map[string]string{"lower": "90", "upper": "110", "levels": "1", "quantity": "1"}, wantErr: true},
+		"Missing quantity":    {values: map[string]string{"lower": "90", "upper": "110"}, wantErr: true},
+		"Zero fee":            {values: map[string]string{"lower": "90", "upper": "110", "quantity": "1", "fee": "0"}},
+		"Negative fee":        {values: map[string]string{"lower": "90", "upper": "110", "quantity": "1", "fee": "-0.001"}, wantErr: true},
+		"Invalid lower price": {values: map[string]string{"lower": "low", "upper": "110", "quantity": "1"}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := strategy.NewGrid(params(tt.values))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, strategy.ErrInvalidParams)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGrid(t *testing.T) {
+	t.Parallel()
+
+	g, err := strategy.NewGrid(params(map[string]string{"lower": "90", "upper": "110", "levels": "5", "quantity": "2"}))
+	assert.NoError(t, err)
+
+	// The balance pays for the buys at 90 and 95 with their fees, not at 100.
+	a := newFakeAccount(map[string]string{"USDT": "371"})
+	ctx := context.Background()
+
+	assert.NoError(t, g.OnCandle(ctx, a, candle(0, "102")))
+	assert.Equal(t, [][4]string{{"BUY", "LIMIT", "2", "90"}, {"BUY", "LIMIT", "2", "95"}}, a.placed())
+
+	a.balances["USDT"] = d("0")
+	a.fill(2)
+	assert.NoError(t, g.OnOrderUpdate(ctx, a, a.orders[1]))
+	assert.Len(t, a.placed(), 2, "no sell without a base balance")
+
+	a.balances["BTC"] = d("1.998")
+	a.orders[0].Status = connector.OrderStatusFilled
+	a.orders[0].ExecutedQuantity = d("2")
+	assert.NoError(t, g.OnOrderUpdate(ctx, a, a.orders[0]))
+	assert.Equal(t, [4]string{"SELL", "LIMIT", "1.998", "95"}, a.placed()[2])
+
+	a.balances["USDT"] = d("1000")
+	a.fill(3)
+	assert.NoError(t, g.OnOrderUpdate(ctx, a, a.orders[2]))
+	assert.Equal(t, [4]string{"BUY", "LIMIT", "2", "90"}, a.placed()[3])
+
+	assert.NoError(t, g.OnCandle(ctx, a, candle(1, "102")))
+	assert.NoError(t, g.OnOrderUpdate(ctx, a, a.orders[2]), "an order is only replaced once")
+	assert.Len(t, a.placed(), 4)
+}
+
+func TestGrid_OnCandle_Fee(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		fee  string
+		want [][4]string
+	}{
+		"Default fee":        {want: [][4]string{}},
+		"Fee of the account": {fee: "0.001", want: [][4]string{}},
+		"No fee":             {fee: "0", want: [][4]string{{"BUY", "LIMIT", "1", "90"}}},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			values := map[string]string{"lower": "90", "upper": "110", "levels": "3", "quantity": "1"}
+			if tt.fee != "" {
+				values["fee"] = tt.fee
+			}
+
+			g, err := strategy.NewGrid(params(values))
+			assert.NoError(t, err)
+
+			// The balance pays for the buy at 90 without its fee only.
+			a := newFakeAccount(map[string]string{"USDT": "90"})
+			assert.NoError(t, g.OnCandle(context.Background(), a, candle(0, "102")))
+			assert.ElementsMatch(t, tt.want, a.placed())
+		})
+	}
+}
+
+func TestGrid_Replay_Fee(t *testing.T) {
+	t.Parallel()
+
+	g, err := strategy.NewGrid(params(map[string]string{"lower": "90", "upper": "110", "levels": "3", "quantity": "1"}))
+	assert.NoError(t, err)
+
+	// The capital pays for the buy at 90 but not for its maker fee, so the level is skipped instead of failing the run.
+	r, err := backtest.New(backtest.WithCapital(d("90"))).Run(context.Background(), strategy.Replay(g), []connector.Candle{candle(0, "102")})
+	assert.NoError(t, err)
+	assert.Empty(t, r.Trades)
+}
+
+func TestGrid_Replay(t *testing.T) {
+	t.Parallel()
+
+	g, err := strategy.NewGrid(params(map[string]string{"lower": "90", "upper": "110", "levels": "5", "quantity": "1"}))
+	assert.NoError(t, err)
+
+	// The price falls through the buy at 95, rises through the sell at 100, then falls through the buy at 95 again.
+	candles := []connector.Candle{candle(0, "97"), candle(1, "93"), candle(2, "101"), candle(3, "94")}
+	candles[1].Open, candles[1].High = d("97"), d("97")
+	candles[2].Open, candles[2].Low = d("93"), d("93")
+	candles[3].Open, candles[3].High = d("101"), d("101")
+
+	r, err := backtest.New(backtest.WithCapital(d("1000"))).Run(context.Background(), strategy.Replay(g), candles)
+	assert.NoError(t, err)
+
+	trades := make([][3]string, 0, len(r.Trades))
+	for _, tr := range r.Trades {
+		trades = append(trades, [3]string{string(tr.Side), tr.Price.String(), tr.Quantity.String()})
+	}
+
+	assert.Equal(t, [][3]string{{"BUY", "95", "1"}, {"SELL", "100", "1"}, {"BUY", "95", "1"}}, trades)
+	assert.Equal(t, "5", r.Trades[1].Profit.String())
+}
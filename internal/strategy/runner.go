@@ -0,0 +1,247 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/twk/trader-b/internal/backtest"
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// Event is a market event fed to a runner. Exactly one of the pointers is set. Candle is a closed candle, and Order an update of an
+// order of the account, e.g. from a user data stream.
+type Event struct {
+	Candle *connector.Candle
+	Trade  *connector.Trade
+	Order  *connector.Order
+}
+
+// Runner feeds a strategy with the events of the market, one at a time. The strategy trades through the runner, which tracks the
+// orders it places and reports the changes of their status or executed quantity with OnOrderUpdate when they are polled on every timer.
+type Runner struct {
+	strategy Strategy
+	account  *tracker
+	timer    time.Duration
+	now      func() time.Time
+	log      *zap.Logger
+}
+
+// RunnerOption configures a Runner.
+type RunnerOption func(*Runner)
+
+// WithTimer sets the interval of the timer of Run, zero disables it.
+func WithTimer(d time.Duration) RunnerOption {
+	return func(r *Runner) {
+		r.timer = d
+	}
+}
+
+// WithRunnerClock sets the clock of the timer.
+func WithRunnerClock(now func() time.Time) RunnerOption {
+	return func(r *Runner) {
+		r.now = now
+	}
+}
+
+// WithRunnerLogger sets the logger of the runner.
+func WithRunnerLogger(l *zap.Logger) RunnerOption {
+	return func(r *Runner) {
+		r.log = l
+	}
+}
+
+// NewRunner creates a new Runner of the strategy trading with the account, with a one minute timer.
+func NewRunner(s Strategy, a Account, opts ...RunnerOption) *Runner {
+	r := &Runner{strategy: s, account: newTracker(a), timer: time.Minute, now: time.Now, log: zap.NewNop()}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Run feeds the strategy with the events until the channel is closed or the context is done, and calls the timer at its interval.
+// It stops at the first error of the strategy.
+func (r *Runner) Run(ctx context.Context, events <-chan Event) error {
+	var tick <-chan time.Time
+
+	if r.timer > 0 {
+		t := time.NewTicker(r.timer)
+		defer t.Stop()
+
+		tick = t.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("error running strategy: %w", ctx.Err())
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			if err := r.Handle(ctx, e); err != nil {
+				return err
+			}
+		case <-tick:
+			if err := r.Timer(ctx, r.now()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Handle feeds the strategy with the event.
+func (r *Runner) Handle(ctx context.Context, e Event) error {
+	switch {
+	case e.Candle != nil:
+		if err := r.strategy.OnCandle(ctx, r.account, *e.Candle); err != nil {
+			return fmt.Errorf("error handling candle opened at %s: %w", e.Candle.OpenTime.Format(time.RFC3339), err)
+		}
+	case e.Trade != nil:
+		if err := r.strategy.OnTrade(ctx, r.account, *e.Trade); err != nil {
+			return fmt.Errorf("error handling trade %d: %w", e.Trade.ID, err)
+		}
+	case e.Order != nil:
+		if !r.account.update(*e.Order) {
+			return nil
+		}
+
+		if err := r.strategy.OnOrderUpdate(ctx, r.account, *e.Order); err != nil {
+			return fmt.Errorf("error handling update of order %d: %w", e.Order.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Timer polls the open orders placed by the strategy and reports their updates, then calls the timer of the strategy.
+func (r *Runner) Timer(ctx context.Context, now time.Time) error {
+	updates, err := r.account.poll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range updates {
+		r.log.Info("order updated", zap.Int64("id", o.ID), zap.String("status", o.Status), zap.Stringer("executed", o.ExecutedQuantity))
+
+		if err := r.strategy.OnOrderUpdate(ctx, r.account, o); err != nil {
+			return fmt.Errorf("error handling update of order %d: %w", o.ID, err)
+		}
+	}
+
+	if err := r.strategy.OnTimer(ctx, r.account, now); err != nil {
+		return fmt.Errorf("error handling timer: %w", err)
+	}
+
+	return nil
+}
+
+// Replay adapts the strategy to a backtest. On every candle, the timer is called at the close time of the candle, so the orders
+// executed by the candle are reported first, then the candle is handled.
+func Replay(s Strategy) backtest.Strategy {
+	return &replay{strategy: s}
+}
+
+type replay struct {
+	strategy Strategy
+	runner   *Runner
+}
+
+func (p *replay) OnCandle(ctx context.Context, c connector.Candle, a backtest.Account) error {
+	if p.runner == nil {
+		p.runner = NewRunner(p.strategy, a)
+	}
+
+	if err := p.runner.Timer(ctx, c.CloseTime); err != nil {
+		return err
+	}
+
+	return p.runner.Handle(ctx, Event{Candle: &c})
+}
+
+var _ Account = (*tracker)(nil)
+
+// tracker is an account recording the last known state of the open orders placed through it.
+type tracker struct {
+	Account
+	orders map[int64]connector.Order
+}
+
+func newTracker(a Account) *tracker {
+	return &tracker{Account: a, orders: make(map[int64]connector.Order)}
+}
+
+// PlaceOrder places the order and tracks it while it is open.
+func (t *tracker) PlaceOrder(ctx context.Context, req connector.OrderRequest) (*connector.Order, error) {
+	o, err := t.Account.PlaceOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.update(*o)
+
+	return o, nil
+}
+
+// CancelOrder cancels the order and stops tracking it.
+func (t *tracker) CancelOrder(ctx context.Context, symbol string, orderID int64) (*connector.Order, error) {
+	o, err := t.Account.CancelOrder(ctx, symbol, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	t.update(*o)
+
+	return o, nil
+}
+
+// update records the state of the order, and reports whether it changed since it was last seen.
+func (t *tracker) update(o connector.Order) bool {
+	prev, ok := t.orders[o.ID]
+	changed := !ok || prev.Status != o.Status || !prev.ExecutedQuantity.Equal(o.ExecutedQuantity)
+
+	if open(o) {
+		t.orders[o.ID] = o
+	} else {
+		delete(t.orders, o.ID)
+	}
+
+	return changed
+}
+
+// poll gets the tracked orders, from the oldest, and returns the ones which changed.
+func (t *tracker) poll(ctx context.Context) ([]connector.Order, error) {
+	ids := make([]int64, 0, len(t.orders))
+	for id := range t.orders {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var updates []connector.Order
+
+	for _, id := range ids {
+		prev := t.orders[id]
+
+		o, err := t.Account.GetOrder(ctx, prev.Symbol, id)
+		if err != nil {
+			return nil, fmt.Errorf("error polling order %d: %w", id, err)
+		}
+
+		if t.update(*o) {
+			updates = append(updates, *o)
+		}
+	}
+
+	return updates, nil
+}
+
+func open(o connector.Order) bool {
+	return o.Status == connector.OrderStatusNew || o.Status == connector.OrderStatusPartiallyFilled
+}
@@ -0,0 +1,117 @@
+package strategy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/backtest"
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/strategy"
+)
+
+// recorder records the events it handles, and places a limit buy on the first candle.
+type recorder struct {
+	events []string
+	placed bool
+}
+
+func (r *recorder) OnCandle(ctx context.Context, a strategy.Account, c connector.Candle) error {
+	r.events = append(r.events, "candle "+c.Close.String())
+
+	if r.placed {
+		return nil
+	}
+
+	r.placed = true
+	_, err := a.PlaceOrder(ctx, connector.OrderRequest{
+		Symbol: c.Symbol, Side: connector.SideBuy, Type: connector.OrderTypeLimit, Quantity: d("1"), Price: d("90"),
+	})
+
+	return err
+}
+
+func (r *recorder) OnTrade(_ context.Context, _ strategy.Account, t connector.Trade) error {
+	r.events = append(r.events, "trade "+t.Price.String())
+	return nil
+}
+
+func (r *recorder) OnOrderUpdate(_ context.Context, _ strategy.Account, o connector.Order) error {
+	r.events = append(r.events, "order "+o.Status)
+	return nil
+}
+
+func (r *recorder) OnTimer(_ context.Context, _ strategy.Account, now time.Time) error {
+	r.events = append(r.events, "timer "+now.Format("15:04"))
+	return nil
+}
+
+func TestRunner_Run(t *testing.T) {
+	t.Parallel()
+
+	a := newFakeAccount(map[string]string{"USDT": "1000"})
+	s := &recorder{}
+	r := strategy.NewRunner(s, a, strategy.WithTimer(0))
+
+	c := candle(0, "100")
+	tr := connector.Trade{ID: 1, Symbol: "BTCUSDT", Price: d("99")}
+	events := make(chan strategy.Event, 3)
+	events <- strategy.Event{Candle: &c}
+	events <- strategy.Event{Trade: &tr}
+
+	filled := connector.Order{ID: 1, Symbol: "BTCUSDT", Status: connector.OrderStatusFilled, ExecutedQuantity: d("1")}
+	events <- strategy.Event{Order: &filled}
+	close(events)
+
+	assert.NoError(t, r.Run(context.Background(), events))
+	assert.Equal(t, []string{"candle 100", "trade 99", "order FILLED"}, s.events)
+}
+
+func TestRunner_Run_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := strategy.NewRunner(&recorder{}, newFakeAccount(nil)).Run(ctx, make(chan strategy.Event))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRunner_Timer(t *testing.T) {
+	t.Parallel()
+
+	a := newFakeAccount(map[string]string{"USDT": "1000"})
+	s := &recorder{}
+	r := strategy.NewRunner(s, a)
+	c := candle(0, "100")
+
+	assert.NoError(t, r.Handle(context.Background(), strategy.Event{Candle: &c}))
+	assert.NoError(t, r.Timer(context.Background(), at(1)))
+
+	a.fill(1)
+
+	assert.NoError(t, r.Timer(context.Background(), at(2)))
+	assert.NoError(t, r.Timer(context.Background(), at(3)))
+	assert.Equal(t, []string{"candle 100", "timer 00:01", "order FILLED", "timer 00:02", "timer 00:03"}, s.events)
+
+	a.err = errors.New("boom")
+	assert.NoError(t, r.Timer(context.Background(), at(4)), "the filled order is not polled anymore")
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	s := &recorder{}
+	candles := []connector.Candle{candle(0, "100"), candle(1, "95"), candle(2, "90")}
+	candles[2].Low = d("85")
+
+	r, err := backtest.New(backtest.WithCapital(d("1000"))).Run(context.Background(), strategy.Replay(s), candles)
+	assert.NoError(t, err)
+	assert.Len(t, r.Trades, 1)
+	assert.Equal(t, []string{
+		"timer 00:00", "candle 100", "timer 00:01", "candle 95", "order FILLED", "timer 00:02", "candle 90",
+	}, s.events)
+}
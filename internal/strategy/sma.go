@@ -0,0 +1,155 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/indicators"
+)
+
+// SMACrossName is the name of the SMA crossover strategy.
+const SMACrossName = "sma"
+
+// Default periods of the moving averages of the SMA crossover strategy.
+const (
+	defaultFastPeriod = 10
+	defaultSlowPeriod = 30
+)
+
+// defaultFraction is the default fraction of the free quote balance bought with. A market buy is sized at the close price,
+// so the remaining balance pays for its fee and for an ask above the close.
+var defaultFraction = decimal.RequireFromString("0.99")
+
+// SMACross is the moving average crossover strategy. It buys with a fraction of the free quote balance when the simple moving average
+// of the fast period crosses above the one of the slow period, and sells the free base balance when it crosses below.
+// It buys only without a base balance, so it holds a single position at a time.
+type SMACross struct {
+	Base
+	params   Params
+	fast     *indicators.SMA
+	slow     *indicators.SMA
+	fraction decimal.Decimal
+	// above is 1 when the fast average was last above the slow one, -1 when below, and 0 until they are known.
+	above int
+}
+
+var _ Strategy = (*SMACross)(nil)
+
+// NewSMACross creates an SMA crossover strategy from the parameters fast and slow, the periods of the averages defaulting to 10 and 30,
+// and fraction, the fraction of the free quote balance bought with defaulting to 0.99.
+func NewSMACross(p Params) (Strategy, error) {
+	fast, err := p.Int("fast", defaultFastPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	slow, err := p.Int("slow", defaultSlowPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	fraction, err := p.Decimal("fraction", defaultFraction)
+	if err != nil {
+		return nil, err
+	}
+
+	if fast < 1 || slow <= fast {
+		return nil, fmt.Errorf("%w: the periods must be 0 < fast < slow, got %d and %d", ErrInvalidParams, fast, slow)
+	}
+
+	if !fraction.IsPositive() || fraction.GreaterThan(decimal.NewFromInt(1)) {
+		return nil, fmt.Errorf("%w: the fraction must be in (0, 1], got %s", ErrInvalidParams, fraction)
+	}
+
+	fastSMA, err := indicators.NewSMA(fast)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidParams, err)
+	}
+
+	slowSMA, err := indicators.NewSMA(slow)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidParams, err)
+	}
+
+	return &SMACross{params: p, fast: fastSMA, slow: slowSMA, fraction: fraction}, nil
+}
+
+// OnCandle updates the averages with the close price of the candle, and trades when they cross.
+func (s *SMACross) OnCandle(ctx context.Context, a Account, c connector.Candle) error {
+	if c.Symbol != s.params.Symbol {
+		return nil
+	}
+
+	price := c.Close.InexactFloat64()
+	fast, _ := s.fast.Update(price)
+
+	slow, ok := s.slow.Update(price)
+	if !ok || fast == slow {
+		return nil
+	}
+
+	above := 1
+	if fast < slow {
+		above = -1
+	}
+
+	prev := s.above
+	s.above = above
+
+	switch {
+	case prev < 0 && above > 0:
+		return s.buy(ctx, a, c.Close)
+	case prev > 0 && above < 0:
+		return s.sell(ctx, a)
+	default:
+		return nil
+	}
+}
+
+func (s *SMACross) buy(ctx context.Context, a Account, price decimal.Decimal) error {
+	base, err := freeBalance(ctx, a, s.params.Base)
+	if err != nil {
+		return err
+	}
+
+	if base.IsPositive() {
+		return nil
+	}
+
+	quote, err := freeBalance(ctx, a, s.params.Quote)
+	if err != nil {
+		return err
+	}
+
+	qty := quote.Mul(s.fraction).Div(price).RoundDown(quantityPlaces)
+	if !qty.IsPositive() {
+		return nil
+	}
+
+	return placeMarket(ctx, a, s.params.Symbol, connector.SideBuy, qty)
+}
+
+func (s *SMACross) sell(ctx context.Context, a Account) error {
+	qty, err := freeBalance(ctx, a, s.params.Base)
+	if err != nil {
+		return err
+	}
+
+	if !qty.IsPositive() {
+		return nil
+	}
+
+	return placeMarket(ctx, a, s.params.Symbol, connector.SideSell, qty)
+}
+
+func placeMarket(ctx context.Context, a Account, symbol string, side connector.Side, qty decimal.Decimal) error {
+	req := connector.OrderRequest{Symbol: symbol, Side: side, Type: connector.OrderTypeMarket, Quantity: qty}
+	if _, err := a.PlaceOrder(ctx, req); err != nil {
+		return fmt.Errorf("error placing %s order: %w", side, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package strategy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/strategy"
+)
+
+func TestNewSMACross(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		values  map[string]string
+		wantErr bool
+	}{
+		"Defaults":              {},
+		"Periods":               {values: map[string]string{"fast": "2", "slow": "3", "fraction": "0.5"}},
+		"Fast not below slow":   {values: map[string]string{"fast": "3", "slow": "3"}, wantErr: true},
+		"Zero fast period":      {values: map[string]string{"fast": "0"}, wantErr: true},
+		"Fraction above one":    {values: map[string]string{"fraction": "1.5"}, wantErr: true},
+		"Invalid period number": {values: map[string]string{"slow": "x"}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := strategy.NewSMACross(params(tt.values))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, strategy.ErrInvalidParams)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSMACross_OnCandle(t *testing.T) {
+	t.Parallel()
+
+	s, err := strategy.NewSMACross(params(map[string]string{"fast": "2", "slow": "3", "fraction": "0.5"}))
+	assert.NoError(t, err)
+
+	a := newFakeAccount(map[string]string{"USDT": "1000"})
+
+	// The fast average is below the slow one from the third candle, crosses above on the fifth and below on the seventh.
+	closes := []string{"100", "90", "80", "80", "120", "120", "60"}
+	for i, c := range closes {
+		if i == len(closes)-1 {
+			a.balances["BTC"] = d("4.16666666")
+		}
+
+		assert.NoError(t, s.OnCandle(context.Background(), a, candle(i, c)))
+	}
+
+	assert.Equal(t, [][4]string{
+		{"BUY", "MARKET", "4.16666666", "0"},
+		{"SELL", "MARKET", "4.16666666", "0"},
+	}, a.placed())
+}
+
+func TestSMACross_OnCandle_DefaultFraction(t *testing.T) {
+	t.Parallel()
+
+	s, err := strategy.NewSMACross(params(map[string]string{"fast": "1", "slow": "2"}))
+	assert.NoError(t, err)
+
+	a := newFakeAccount(map[string]string{"USDT": "1000"})
+
+	// The buy keeps a margin of the balance for its fee and an ask above the close.
+	for i, c := range []string{"100", "90", "100"} {
+		assert.NoError(t, s.OnCandle(context.Background(), a, candle(i, c)))
+	}
+
+	assert.Equal(t, [][4]string{{"BUY", "MARKET", "9.9", "0"}}, a.placed())
+}
+
+func TestSMACross_OnCandle_HoldsOnePosition(t *testing.T) {
+	t.Parallel()
+
+	s, err := strategy.NewSMACross(params(map[string]string{"fast": "1", "slow": "2"}))
+	assert.NoError(t, err)
+
+	a := newFakeAccount(map[string]string{"USDT": "1000", "BTC": "1"})
+
+	for i, c := range []string{"100", "90", "120"} {
+		assert.NoError(t, s.OnCandle(context.Background(), a, candle(i, c)))
+	}
+
+	assert.Empty(t, a.placed())
+}
@@ -0,0 +1,189 @@
+// Package strategy provides the interface of the trading strategies, the runner feeding them market events, and the reference strategies.
+// The same strategy runs against the simulated account of a backtest, the paper exchange or a live exchange.
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/twk/trader-b/internal/connector"
+)
+
+// quantityPlaces is the number of decimal places the quantities of the orders are rounded down to.
+const quantityPlaces = 8
+
+var (
+	// ErrUnknownStrategy is returned when a strategy is not registered.
+	ErrUnknownStrategy = errors.New("unknown strategy")
+	// ErrInvalidParams is returned when the parameters of a strategy are invalid.
+	ErrInvalidParams = errors.New("invalid strategy parameters")
+)
+
+// Account is the account a strategy trades with.
+type Account interface {
+	connector.Trader
+	GetBalances(ctx context.Context) ([]connector.Balance, error)
+}
+
+// Strategy is trading logic fed with market events by a Runner, one event at a time.
+// OnCandle receives the closed candles, OnTrade the trades of the market, OnOrderUpdate the changes of the status or executed
+// quantity of the orders, and OnTimer is called periodically.
+type Strategy interface {
+	OnCandle(ctx context.Context, a Account, c connector.Candle) error
+	OnTrade(ctx context.Context, a Account, t connector.Trade) error
+	OnOrderUpdate(ctx context.Context, a Account, o connector.Order) error
+	OnTimer(ctx context.Context, a Account, now time.Time) error
+}
+
+// Base ignores every event. It is embedded by the strategies only handling some of them.
+type Base struct{}
+
+// OnCandle ignores the candle.
+func (Base) OnCandle(_ context.Context, _ Account, _ connector.Candle) error {
+	return nil
+}
+
+// OnTrade ignores the trade.
+func (Base) OnTrade(_ context.Context, _ Account, _ connector.Trade) error {
+	return nil
+}
+
+// OnOrderUpdate ignores the order update.
+func (Base) OnOrderUpdate(_ context.Context, _ Account, _ connector.Order) error {
+	return nil
+}
+
+// OnTimer ignores the timer.
+func (Base) OnTimer(_ context.Context, _ Account, _ time.Time) error {
+	return nil
+}
+
+// Params are the settings of a strategy: the symbol it trades with its base and quote assets, and its own values keyed by name.
+type Params struct {
+	Symbol string
+	Base   string
+	Quote  string
+	Values map[string]string
+}
+
+// ParseParams parses comma separated name=value pairs, e.g. fast=10,slow=30.
+func ParseParams(s string) (map[string]string, error) {
+	res := make(map[string]string)
+
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("%w: %q is not a name=value pair", ErrInvalidParams, pair)
+		}
+
+		res[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+
+	return res, nil
+}
+
+// Int returns the integer value of the name, or the default when it is not set.
+func (p Params) Int(name string, def int) (int, error) {
+	s, ok := p.Values[name]
+	if !ok {
+		return def, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s must be an integer, got %q", ErrInvalidParams, name, s)
+	}
+
+	return v, nil
+}
+
+// Decimal returns the decimal value of the name, or the default when it is not set.
+func (p Params) Decimal(name string, def decimal.Decimal) (decimal.Decimal, error) {
+	s, ok := p.Values[name]
+	if !ok {
+		return def, nil
+	}
+
+	v, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%w: %s must be a number, got %q", ErrInvalidParams, name, s)
+	}
+
+	return v, nil
+}
+
+// Factory creates a strategy from its parameters.
+type Factory func(p Params) (Strategy, error)
+
+// Registry holds the strategy factories keyed by strategy name.
+type Registry struct {
+	factories map[string]Factory
+}
+
+// NewRegistry creates a new registry holding the reference strategies.
+func NewRegistry() *Registry {
+	r := &Registry{factories: make(map[string]Factory)}
+	r.Register(SMACrossName, NewSMACross)
+	r.Register(GridName, NewGrid)
+
+	return r
+}
+
+// Register registers the factory under the strategy name. Registering the same name twice replaces the factory.
+func (r *Registry) Register(name string, f Factory) {
+	r.factories[name] = f
+}
+
+// Names returns the sorted names of the registered strategies.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Get creates the strategy registered under the name.
+func (r *Registry) Get(name string, p Params) (Strategy, error) {
+	f, ok := r.factories[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s, available: %s", ErrUnknownStrategy, name, strings.Join(r.Names(), ", "))
+	}
+
+	s, err := f(p)
+	if err != nil {
+		return nil, fmt.Errorf("strategy %s: %w", name, err)
+	}
+
+	return s, nil
+}
+
+// freeBalance returns the free balance of the asset.
+func freeBalance(ctx context.Context, a Account, asset string) (decimal.Decimal, error) {
+	balances, err := a.GetBalances(ctx)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("error getting balances: %w", err)
+	}
+
+	for _, b := range balances {
+		if b.Asset == asset {
+			return b.Free, nil
+		}
+	}
+
+	return decimal.Zero, nil
+}
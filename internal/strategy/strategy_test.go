@@ -0,0 +1,196 @@
+package strategy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/twk/trader-b/internal/connector"
+	"github.com/twk/trader-b/internal/strategy"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func at(minute int) time.Time {
+	return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(minute) * time.Minute)
+}
+
+func candle(minute int, closePrice string) connector.Candle {
+	return connector.Candle{
+		Symbol: "BTCUSDT", Interval: "1m", OpenTime: at(minute), CloseTime: at(minute + 1).Add(-time.Millisecond),
+		Open: d(closePrice), High: d(closePrice), Low: d(closePrice), Close: d(closePrice),
+	}
+}
+
+func params(values map[string]string) strategy.Params {
+	return strategy.Params{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT", Values: values}
+}
+
+// fakeAccount records the placed orders of BTCUSDT, which stay as they are placed until the tests change them.
+// The limit buys take their amount from the USDT balance, and the sells their quantity from the BTC balance.
+type fakeAccount struct {
+	balances map[string]decimal.Decimal
+	orders   []connector.Order
+	err      error
+}
+
+func newFakeAccount(balances map[string]string) *fakeAccount {
+	a := &fakeAccount{balances: make(map[string]decimal.Decimal)}
+	for asset, amount := range balances {
+		a.balances[asset] = d(amount)
+	}
+
+	return a
+}
+
+func (a *fakeAccount) GetBalances(_ context.Context) ([]connector.Balance, error) {
+	res := make([]connector.Balance, 0, len(a.balances))
+	for asset, free := range a.balances {
+		res = append(res, connector.Balance{Asset: asset, Free: free})
+	}
+
+	return res, a.err
+}
+
+func (a *fakeAccount) PlaceOrder(_ context.Context, req connector.OrderRequest) (*connector.Order, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	status := connector.OrderStatusNew
+	if req.Type == connector.OrderTypeMarket {
+		status = connector.OrderStatusFilled
+	}
+
+	o := connector.Order{
+		ID: int64(len(a.orders) + 1), Symbol: req.Symbol, Side: req.Side, Type: req.Type, Status: status, Price: req.Price, Quantity: req.Quantity,
+	}
+	if status == connector.OrderStatusFilled {
+		o.ExecutedQuantity = req.Quantity
+	}
+
+	switch {
+	case req.Side == connector.SideSell:
+		a.balances["BTC"] = a.balances["BTC"].Sub(req.Quantity)
+	case req.Type == connector.OrderTypeLimit:
+		a.balances["USDT"] = a.balances["USDT"].Sub(req.Price.Mul(req.Quantity))
+	}
+
+	a.orders = append(a.orders, o)
+
+	return &o, nil
+}
+
+func (a *fakeAccount) CancelOrder(_ context.Context, _ string, orderID int64) (*connector.Order, error) {
+	a.orders[orderID-1].Status = connector.OrderStatusCanceled
+	o := a.orders[orderID-1]
+
+	return &o, a.err
+}
+
+func (a *fakeAccount) GetOrder(_ context.Context, _ string, orderID int64) (*connector.Order, error) {
+	o := a.orders[orderID-1]
+	return &o, a.err
+}
+
+func (a *fakeAccount) ListOpenOrders(_ context.Context, _ string) ([]connector.Order, error) {
+	return nil, a.err
+}
+
+// fill fills the order with the given ID.
+func (a *fakeAccount) fill(orderID int64) {
+	a.orders[orderID-1].Status = connector.OrderStatusFilled
+	a.orders[orderID-1].ExecutedQuantity = a.orders[orderID-1].Quantity
+}
+
+// placed returns the placed orders as side, type, quantity and price.
+func (a *fakeAccount) placed() [][4]string {
+	res := make([][4]string, 0, len(a.orders))
+	for _, o := range a.orders {
+		res = append(res, [4]string{string(o.Side), string(o.Type), o.Quantity.String(), o.Price.String()})
+	}
+
+	return res
+}
+
+func TestParseParams(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		values map[string]string
+		err    error
+	}
+
+	tests := map[string]struct {
+		s    string
+		want want
+	}{
+		"Pairs":             {s: "fast=10, Slow=30", want: want{values: map[string]string{"fast": "10", "slow": "30"}}},
+		"Empty":             {s: "", want: want{values: map[string]string{}}},
+		"Missing separator": {s: "fast", want: want{err: strategy.ErrInvalidParams}},
+		"Missing name":      {s: "=10", want: want{err: strategy.ErrInvalidParams}},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			values, err := strategy.ParseParams(tt.s)
+			assert.True(t, errors.Is(err, tt.want.err), "got %v", err)
+			assert.Equal(t, tt.want.values, values)
+		})
+	}
+}
+
+func TestParams(t *testing.T) {
+	t.Parallel()
+
+	p := params(map[string]string{"n": "5", "x": "0.5", "bad": "five"})
+
+	n, err := p.Int("n", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+
+	n, err = p.Int("missing", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = p.Int("bad", 1)
+	assert.ErrorIs(t, err, strategy.ErrInvalidParams)
+
+	x, err := p.Decimal("x", d("1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.5", x.String())
+
+	x, err = p.Decimal("missing", d("1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "1", x.String())
+
+	_, err = p.Decimal("bad", d("1"))
+	assert.ErrorIs(t, err, strategy.ErrInvalidParams)
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := strategy.NewRegistry()
+	assert.Equal(t, []string{strategy.GridName, strategy.SMACrossName}, r.Names())
+
+	s, err := r.Get(strategy.SMACrossName, params(nil))
+	assert.NoError(t, err)
+	assert.IsType(t, &strategy.SMACross{}, s)
+
+	_, err = r.Get("unknown", params(nil))
+	assert.ErrorIs(t, err, strategy.ErrUnknownStrategy)
+
+	_, err = r.Get(strategy.GridName, params(nil))
+	assert.ErrorIs(t, err, strategy.ErrInvalidParams)
+}